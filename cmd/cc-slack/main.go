@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -15,8 +16,10 @@ import (
 
 	"github.com/gorilla/mux"
 	slackapi "github.com/slack-go/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/approval"
 	"github.com/yuya-takeyama/cc-slack/internal/config"
 	"github.com/yuya-takeyama/cc-slack/internal/database"
+	"github.com/yuya-takeyama/cc-slack/internal/forge"
 	"github.com/yuya-takeyama/cc-slack/internal/mcp"
 	"github.com/yuya-takeyama/cc-slack/internal/session"
 	"github.com/yuya-takeyama/cc-slack/internal/slack"
@@ -96,6 +99,11 @@ func main() {
 	// Create session manager with database support
 	sessionMgr := session.NewManager(sqlDB, cfg, slackHandler, cfg.Server.BaseURL, cfg.Slack.FileUpload.ImagesDir)
 
+	// Pull requests can be opened from threads when a GitHub token is configured
+	if cfg.GitHub.Token != "" {
+		sessionMgr.SetForge(forge.NewGitHub(cfg.GitHub.Token, cfg.GitHub.APIURL))
+	}
+
 	// Now create the actual Slack handler with the session manager
 	handler := slack.NewHandler(cfg, sessionMgr, botUserID)
 	*slackHandler = *handler
@@ -105,6 +113,16 @@ func main() {
 
 	// Set Slack integration in MCP server
 	mcpServer.SetSlackIntegration(slackHandler, sessionMgr)
+	mcpServer.SetApprovalRecorder(sessionMgr)
+	sessionMgr.SetSessionGrants(mcpServer)
+
+	// Set approval policy evaluated before asking in Slack
+	approvalPolicy, err := approval.NewPolicy(cfg.ApprovalPolicy, cfg.WorkingDirs)
+	if err != nil {
+		log.Fatalf("Failed to create approval policy: %v", err)
+	}
+	mcpServer.SetApprovalPolicy(approvalPolicy)
+	mcpServer.SetApprovalConfig(cfg.Approval, cfg.WorkingDirs)
 
 	// Set MCP server as approval responder in Slack handler
 	slackHandler.SetApprovalResponder(mcpServer)
@@ -113,12 +131,15 @@ func main() {
 	router := mux.NewRouter()
 
 	// Slack endpoints (with 30-second timeout)
-	router.HandleFunc("/slack/events", http.TimeoutHandler(
-		http.HandlerFunc(slackHandler.HandleEvent), 30*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodPost)
-	router.HandleFunc("/slack/interactive", http.TimeoutHandler(
-		http.HandlerFunc(slackHandler.HandleInteraction), 30*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodPost)
-	router.HandleFunc("/slack/commands", http.TimeoutHandler(
-		http.HandlerFunc(slackHandler.HandleSlashCommand), 30*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodPost)
+	// In Socket Mode, Slack requests arrive over the WebSocket connection instead
+	if !cfg.Slack.SocketMode.Enabled {
+		router.HandleFunc("/slack/events", http.TimeoutHandler(
+			http.HandlerFunc(slackHandler.HandleEvent), 30*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodPost)
+		router.HandleFunc("/slack/interactive", http.TimeoutHandler(
+			http.HandlerFunc(slackHandler.HandleInteraction), 30*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodPost)
+		router.HandleFunc("/slack/commands", http.TimeoutHandler(
+			http.HandlerFunc(slackHandler.HandleSlashCommand), 30*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodPost)
+	}
 
 	// MCP endpoints (no additional timeout, uses server timeout)
 	router.PathPrefix("/mcp").HandlerFunc(mcpServer.Handle)
@@ -130,6 +151,15 @@ func main() {
 			fmt.Fprint(w, "OK")
 		}), 5*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodGet)
 
+	// Outbound Slack queue metrics (with 5-second timeout)
+	router.HandleFunc("/metrics/slack-outbox", http.TimeoutHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(slackHandler.OutboxStats()); err != nil {
+				log.Printf("Failed to encode outbox stats: %v", err)
+			}
+		}), 5*time.Second, "Request timeout").ServeHTTP).Methods(http.MethodGet)
+
 	// Manager proxy endpoints (with 30-second timeout)
 	managerProxyHandler := func(w http.ResponseWriter, r *http.Request) {
 		// Extract the path after /api/manager/
@@ -211,6 +241,18 @@ func main() {
 		}
 	}()
 
+	// Start Socket Mode connection if enabled
+	socketModeCtx, stopSocketMode := context.WithCancel(context.Background())
+	defer stopSocketMode()
+	if cfg.Slack.SocketMode.Enabled {
+		socketModeRunner := slack.NewSocketModeRunner(slackHandler, cfg.Slack.AppToken)
+		go func() {
+			if err := socketModeRunner.Run(socketModeCtx); err != nil && err != context.Canceled {
+				log.Fatalf("Socket Mode connection failed: %v", err)
+			}
+		}()
+	}
+
 	// Handle graceful shutdown
 	done := make(chan bool, 1)
 	quit := make(chan os.Signal, 1)
@@ -219,6 +261,7 @@ func main() {
 	go func() {
 		<-quit
 		log.Println("Server is shutting down...")
+		stopSocketMode()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -226,12 +269,21 @@ func main() {
 		if err := srv.Shutdown(ctx); err != nil {
 			log.Fatalf("Could not gracefully shutdown the server: %v\n", err)
 		}
+
+		// Send the Slack messages that are still queued
+		if err := slackHandler.Shutdown(ctx); err != nil {
+			log.Printf("Could not send all queued Slack messages: %v", err)
+		}
 		close(done)
 	}()
 
 	log.Printf("Server starting on port %d", cfg.Server.Port)
 	log.Printf("MCP endpoint: %s/mcp", cfg.Server.BaseURL)
-	log.Printf("Slack webhook endpoint: %s/slack/events", cfg.Server.BaseURL)
+	if cfg.Slack.SocketMode.Enabled {
+		log.Printf("Slack transport: Socket Mode")
+	} else {
+		log.Printf("Slack webhook endpoint: %s/slack/events", cfg.Server.BaseURL)
+	}
 	log.Printf("Session timeout: %v", cfg.Session.Timeout)
 	log.Printf("Cleanup interval: %v", cfg.Session.CleanupInterval)
 	log.Printf("Database path: %s", cfg.Database.Path)
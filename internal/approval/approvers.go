@@ -0,0 +1,99 @@
+package approval
+
+import (
+	"fmt"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+)
+
+// Approver modes
+const (
+	// ApproversAnyone lets anyone who can see the thread answer approval requests
+	ApproversAnyone = "anyone"
+	// ApproversInitiator lets only the user who started the session answer approval requests
+	ApproversInitiator = "initiator"
+	// ApproversInitiatorAndAdmins lets the initiator and configured admins answer approval requests
+	ApproversInitiatorAndAdmins = "initiator_and_admins"
+	// ApproversUserGroup lets the initiator and members of a Slack user group answer approval requests
+	ApproversUserGroup = "user_group"
+)
+
+// UserGroupMembersFunc returns the user IDs of the members of a Slack user group
+type UserGroupMembersFunc func(userGroupID string) ([]string, error)
+
+// Approvers decides who may answer approval requests posted to Slack
+type Approvers struct {
+	mode      string
+	admins    []string
+	userGroup string
+
+	// Backup approvers that requests are escalated to are always allowed to answer them
+	backupUsers     []string
+	backupUserGroup string
+}
+
+// NewApprovers creates the approver policy from approval settings
+func NewApprovers(cfg config.ApprovalConfig) *Approvers {
+	mode := cfg.Approvers.Mode
+	if mode == "" {
+		mode = ApproversAnyone
+	}
+
+	a := &Approvers{
+		mode:      mode,
+		admins:    cfg.Approvers.Admins,
+		userGroup: cfg.Approvers.UserGroup,
+	}
+	if cfg.Escalation.After > 0 {
+		a.backupUsers = cfg.Escalation.Users
+		a.backupUserGroup = cfg.Escalation.UserGroup
+	}
+
+	return a
+}
+
+// Restricted reports whether only some users may answer approval requests
+func (a *Approvers) Restricted() bool {
+	return a != nil && a.mode != ApproversAnyone
+}
+
+// IsAuthorized reports whether a user may answer an approval request in a session started by initiatorID
+// members is only called when user group membership has to be checked.
+func (a *Approvers) IsAuthorized(userID, initiatorID string, members UserGroupMembersFunc) (bool, error) {
+	if a == nil || a.mode == ApproversAnyone {
+		return true, nil
+	}
+	if userID == "" {
+		return false, nil
+	}
+	if userID == initiatorID || containsString(a.backupUsers, userID) {
+		return true, nil
+	}
+
+	switch a.mode {
+	case ApproversInitiatorAndAdmins:
+		if containsString(a.admins, userID) {
+			return true, nil
+		}
+	case ApproversUserGroup:
+		ok, err := isUserGroupMember(a.userGroup, userID, members)
+		if ok || err != nil {
+			return ok, err
+		}
+	}
+
+	return isUserGroupMember(a.backupUserGroup, userID, members)
+}
+
+// isUserGroupMember reports whether a user belongs to a Slack user group
+func isUserGroupMember(userGroupID, userID string, members UserGroupMembersFunc) (bool, error) {
+	if userGroupID == "" || members == nil {
+		return false, nil
+	}
+
+	userIDs, err := members(userGroupID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get members of user group %s: %w", userGroupID, err)
+	}
+	return containsString(userIDs, userID), nil
+}
@@ -0,0 +1,119 @@
+package approval
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+)
+
+func TestApproversIsAuthorized(t *testing.T) {
+	members := func(userGroupID string) ([]string, error) {
+		switch userGroupID {
+		case "S_ONCALL":
+			return []string{"U_ONCALL"}, nil
+		case "S_BACKUP":
+			return []string{"U_BACKUP_GROUP"}, nil
+		default:
+			return nil, errors.New("user group not found")
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cfg     config.ApprovalConfig
+		userID  string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "anyone",
+			cfg:    config.ApprovalConfig{},
+			userID: "U_OTHER",
+			want:   true,
+		},
+		{
+			name:   "initiator mode allows initiator",
+			cfg:    config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: ApproversInitiator}},
+			userID: "U_INITIATOR",
+			want:   true,
+		},
+		{
+			name:   "initiator mode rejects others",
+			cfg:    config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: ApproversInitiator}},
+			userID: "U_OTHER",
+			want:   false,
+		},
+		{
+			name:   "admin",
+			cfg:    config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: ApproversInitiatorAndAdmins, Admins: []string{"U_ADMIN"}}},
+			userID: "U_ADMIN",
+			want:   true,
+		},
+		{
+			name:   "non-admin",
+			cfg:    config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: ApproversInitiatorAndAdmins, Admins: []string{"U_ADMIN"}}},
+			userID: "U_OTHER",
+			want:   false,
+		},
+		{
+			name:   "user group member",
+			cfg:    config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: ApproversUserGroup, UserGroup: "S_ONCALL"}},
+			userID: "U_ONCALL",
+			want:   true,
+		},
+		{
+			name:   "user group mode allows initiator",
+			cfg:    config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: ApproversUserGroup, UserGroup: "S_ONCALL"}},
+			userID: "U_INITIATOR",
+			want:   true,
+		},
+		{
+			name:    "user group lookup failure",
+			cfg:     config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: ApproversUserGroup, UserGroup: "S_UNKNOWN"}},
+			userID:  "U_OTHER",
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "escalation backup user",
+			cfg: config.ApprovalConfig{
+				Approvers:  config.ApproversConfig{Mode: ApproversInitiator},
+				Escalation: config.EscalationConfig{After: time.Minute, Users: []string{"U_BACKUP"}},
+			},
+			userID: "U_BACKUP",
+			want:   true,
+		},
+		{
+			name: "escalation backup user group",
+			cfg: config.ApprovalConfig{
+				Approvers:  config.ApproversConfig{Mode: ApproversInitiator},
+				Escalation: config.EscalationConfig{After: time.Minute, UserGroup: "S_BACKUP"},
+			},
+			userID: "U_BACKUP_GROUP",
+			want:   true,
+		},
+		{
+			name: "escalation disabled",
+			cfg: config.ApprovalConfig{
+				Approvers:  config.ApproversConfig{Mode: ApproversInitiator},
+				Escalation: config.EscalationConfig{Users: []string{"U_BACKUP"}},
+			},
+			userID: "U_BACKUP",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewApprovers(tt.cfg).IsAuthorized(tt.userID, "U_INITIATOR", members)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsAuthorized() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsAuthorized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
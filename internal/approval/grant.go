@@ -0,0 +1,148 @@
+package approval
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Grant scopes
+const (
+	// ScopeSession grants identical requests for the rest of the session
+	ScopeSession = "session"
+	// ScopePattern grants Bash commands with the same command pattern for the rest of the session
+	ScopePattern = "pattern"
+)
+
+// shellOperators are shell constructs that can chain additional commands onto a granted one
+var shellOperators = []string{";", "&", "|", "`", "$(", ">", "<", "\n"}
+
+// subcommandRegexp matches a subcommand word such as "test" in "go test ./..."
+var subcommandRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// unpatternedPrograms are shells, interpreters and wrappers that run whatever their arguments say,
+// so a pattern such as "bash *" or "sudo *" would grant arbitrary commands
+var unpatternedPrograms = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true, "fish": true,
+	"python": true, "node": true, "ruby": true, "perl": true, "php": true, "deno": true, "bun": true,
+	"env": true, "xargs": true, "sudo": true, "doas": true, "su": true, "exec": true, "eval": true,
+	"command": true, "nohup": true, "nice": true, "time": true, "timeout": true, "npx": true,
+}
+
+// Grant represents an approval granted for the rest of a session
+type Grant struct {
+	ToolName string
+	Scope    string
+	Value    string // Request key for ScopeSession, command pattern for ScopePattern
+}
+
+// NewGrant creates a grant of the given scope from an approved request
+func NewGrant(scope, toolName string, input map[string]interface{}) (Grant, error) {
+	switch scope {
+	case ScopeSession:
+		return Grant{ToolName: toolName, Scope: scope, Value: requestKey(toolName, input)}, nil
+	case ScopePattern:
+		command, _ := input["command"].(string)
+		pattern := CommandPattern(command)
+		if pattern == "" {
+			return Grant{}, fmt.Errorf("no command pattern for %s request", toolName)
+		}
+		return Grant{ToolName: toolName, Scope: scope, Value: pattern}, nil
+	default:
+		return Grant{}, fmt.Errorf("unknown grant scope: %s", scope)
+	}
+}
+
+// Matches reports whether the grant covers a request
+func (g Grant) Matches(toolName string, input map[string]interface{}) bool {
+	if g.ToolName != toolName {
+		return false
+	}
+
+	switch g.Scope {
+	case ScopeSession:
+		return g.Value == requestKey(toolName, input)
+	case ScopePattern:
+		command, _ := input["command"].(string)
+		return MatchesCommandPattern(g.Value, command)
+	default:
+		return false
+	}
+}
+
+// Description returns a short human-readable description of what the grant covers
+func (g Grant) Description() string {
+	if g.Scope == ScopePattern {
+		return fmt.Sprintf("`%s *`", g.Value)
+	}
+	return fmt.Sprintf("`%s`", g.ToolName)
+}
+
+// CommandPattern returns the program and subcommand of a Bash command, e.g. "go test" for "go test ./..."
+// It returns an empty string for commands that cannot be granted by pattern: commands that chain or
+// redirect, commands run through a shell, interpreter or wrapper, and commands whose second word is
+// not a subcommand, such as "ls -la" or "cat ./notes.txt", since the pattern would be the bare program.
+func CommandPattern(command string) string {
+	if hasShellOperator(command) {
+		return ""
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 || isUnpatternedProgram(fields[0]) {
+		return ""
+	}
+	if len(fields) == 1 {
+		return fields[0]
+	}
+	if subcommandRegexp.MatchString(fields[1]) {
+		return fields[0] + " " + fields[1]
+	}
+	return ""
+}
+
+// isUnpatternedProgram reports whether a program is a shell, interpreter or wrapper
+// Paths and version suffixes are ignored, so "/usr/bin/python3.12" is treated as "python".
+func isUnpatternedProgram(program string) bool {
+	name := strings.TrimRight(path.Base(program), "0123456789.")
+	return unpatternedPrograms[name]
+}
+
+// MatchesCommandPattern reports whether a Bash command starts with the pattern and does not chain other commands
+func MatchesCommandPattern(pattern, command string) bool {
+	if pattern == "" || hasShellOperator(command) {
+		return false
+	}
+
+	normalized := strings.Join(strings.Fields(command), " ")
+	return normalized == pattern || strings.HasPrefix(normalized, pattern+" ")
+}
+
+// hasShellOperator reports whether a command contains shell operators
+func hasShellOperator(command string) bool {
+	for _, op := range shellOperators {
+		if strings.Contains(command, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestKey identifies a request by the input that determines what the tool does
+// Bash descriptions and WebFetch prompts do not change what runs or what is fetched. Any other
+// input, such as the content of a Write or Edit, does, so the whole input is compared.
+func requestKey(toolName string, input map[string]interface{}) string {
+	switch toolName {
+	case "Bash":
+		command, _ := input["command"].(string)
+		return command
+	case "WebFetch":
+		url, _ := input["url"].(string)
+		return url
+	}
+
+	// Map keys are sorted by encoding/json, so identical inputs produce identical keys
+	data, _ := json.Marshal(input)
+	return string(data)
+}
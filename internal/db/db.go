@@ -27,6 +27,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.countActiveSessionsByThreadStmt, err = db.PrepareContext(ctx, countActiveSessionsByThread); err != nil {
 		return nil, fmt.Errorf("error preparing query CountActiveSessionsByThread: %w", err)
 	}
+	if q.createMessageStmt, err = db.PrepareContext(ctx, createMessage); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateMessage: %w", err)
+	}
 	if q.createSessionWithInitialPromptStmt, err = db.PrepareContext(ctx, createSessionWithInitialPrompt); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateSessionWithInitialPrompt: %w", err)
 	}
@@ -54,6 +57,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listActiveSessionsStmt, err = db.PrepareContext(ctx, listActiveSessions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListActiveSessions: %w", err)
 	}
+	if q.listMessagesBySessionIDStmt, err = db.PrepareContext(ctx, listMessagesBySessionID); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMessagesBySessionID: %w", err)
+	}
+	if q.listRecentSessionsByUserStmt, err = db.PrepareContext(ctx, listRecentSessionsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query ListRecentSessionsByUser: %w", err)
+	}
 	if q.listSessionsStmt, err = db.PrepareContext(ctx, listSessions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSessions: %w", err)
 	}
@@ -87,9 +96,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateSessionStatusStmt, err = db.PrepareContext(ctx, updateSessionStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateSessionStatus: %w", err)
 	}
+	if q.updateThreadArchivedAtStmt, err = db.PrepareContext(ctx, updateThreadArchivedAt); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateThreadArchivedAt: %w", err)
+	}
+	if q.updateThreadPullRequestURLStmt, err = db.PrepareContext(ctx, updateThreadPullRequestURL); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateThreadPullRequestURL: %w", err)
+	}
 	if q.updateThreadTimestampStmt, err = db.PrepareContext(ctx, updateThreadTimestamp); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateThreadTimestamp: %w", err)
 	}
+	if q.updateThreadWorktreeStmt, err = db.PrepareContext(ctx, updateThreadWorktree); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateThreadWorktree: %w", err)
+	}
 	return &q, nil
 }
 
@@ -100,6 +118,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countActiveSessionsByThreadStmt: %w", cerr)
 		}
 	}
+	if q.createMessageStmt != nil {
+		if cerr := q.createMessageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createMessageStmt: %w", cerr)
+		}
+	}
 	if q.createSessionWithInitialPromptStmt != nil {
 		if cerr := q.createSessionWithInitialPromptStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createSessionWithInitialPromptStmt: %w", cerr)
@@ -145,6 +168,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listActiveSessionsStmt: %w", cerr)
 		}
 	}
+	if q.listMessagesBySessionIDStmt != nil {
+		if cerr := q.listMessagesBySessionIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMessagesBySessionIDStmt: %w", cerr)
+		}
+	}
+	if q.listRecentSessionsByUserStmt != nil {
+		if cerr := q.listRecentSessionsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listRecentSessionsByUserStmt: %w", cerr)
+		}
+	}
 	if q.listSessionsStmt != nil {
 		if cerr := q.listSessionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listSessionsStmt: %w", cerr)
@@ -200,11 +233,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateSessionStatusStmt: %w", cerr)
 		}
 	}
+	if q.updateThreadArchivedAtStmt != nil {
+		if cerr := q.updateThreadArchivedAtStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateThreadArchivedAtStmt: %w", cerr)
+		}
+	}
+	if q.updateThreadPullRequestURLStmt != nil {
+		if cerr := q.updateThreadPullRequestURLStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateThreadPullRequestURLStmt: %w", cerr)
+		}
+	}
 	if q.updateThreadTimestampStmt != nil {
 		if cerr := q.updateThreadTimestampStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateThreadTimestampStmt: %w", cerr)
 		}
 	}
+	if q.updateThreadWorktreeStmt != nil {
+		if cerr := q.updateThreadWorktreeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateThreadWorktreeStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -245,6 +293,7 @@ type Queries struct {
 	db                                  DBTX
 	tx                                  *sql.Tx
 	countActiveSessionsByThreadStmt     *sql.Stmt
+	createMessageStmt                   *sql.Stmt
 	createSessionWithInitialPromptStmt  *sql.Stmt
 	createThreadStmt                    *sql.Stmt
 	getActiveSessionByThreadStmt        *sql.Stmt
@@ -254,6 +303,8 @@ type Queries struct {
 	getThreadByIDStmt                   *sql.Stmt
 	getThreadByThreadTsStmt             *sql.Stmt
 	listActiveSessionsStmt              *sql.Stmt
+	listMessagesBySessionIDStmt         *sql.Stmt
+	listRecentSessionsByUserStmt        *sql.Stmt
 	listSessionsStmt                    *sql.Stmt
 	listSessionsByThreadIDStmt          *sql.Stmt
 	listSessionsByThreadIDPaginatedStmt *sql.Stmt
@@ -265,7 +316,10 @@ type Queries struct {
 	updateSessionModelStmt              *sql.Stmt
 	updateSessionOnCompleteStmt         *sql.Stmt
 	updateSessionStatusStmt             *sql.Stmt
+	updateThreadArchivedAtStmt          *sql.Stmt
+	updateThreadPullRequestURLStmt      *sql.Stmt
 	updateThreadTimestampStmt           *sql.Stmt
+	updateThreadWorktreeStmt            *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
@@ -273,6 +327,7 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		db:                                  tx,
 		tx:                                  tx,
 		countActiveSessionsByThreadStmt:     q.countActiveSessionsByThreadStmt,
+		createMessageStmt:                   q.createMessageStmt,
 		createSessionWithInitialPromptStmt:  q.createSessionWithInitialPromptStmt,
 		createThreadStmt:                    q.createThreadStmt,
 		getActiveSessionByThreadStmt:        q.getActiveSessionByThreadStmt,
@@ -282,6 +337,8 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getThreadByIDStmt:                   q.getThreadByIDStmt,
 		getThreadByThreadTsStmt:             q.getThreadByThreadTsStmt,
 		listActiveSessionsStmt:              q.listActiveSessionsStmt,
+		listMessagesBySessionIDStmt:         q.listMessagesBySessionIDStmt,
+		listRecentSessionsByUserStmt:        q.listRecentSessionsByUserStmt,
 		listSessionsStmt:                    q.listSessionsStmt,
 		listSessionsByThreadIDStmt:          q.listSessionsByThreadIDStmt,
 		listSessionsByThreadIDPaginatedStmt: q.listSessionsByThreadIDPaginatedStmt,
@@ -293,6 +350,9 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		updateSessionModelStmt:              q.updateSessionModelStmt,
 		updateSessionOnCompleteStmt:         q.updateSessionOnCompleteStmt,
 		updateSessionStatusStmt:             q.updateSessionStatusStmt,
+		updateThreadArchivedAtStmt:          q.updateThreadArchivedAtStmt,
+		updateThreadPullRequestURLStmt:      q.updateThreadPullRequestURLStmt,
 		updateThreadTimestampStmt:           q.updateThreadTimestampStmt,
+		updateThreadWorktreeStmt:            q.updateThreadWorktreeStmt,
 	}
 }
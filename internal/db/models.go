@@ -8,27 +8,46 @@ import (
 	"database/sql"
 )
 
+type Message struct {
+	ID        int64          `json:"id"`
+	SessionID int64          `json:"session_id"`
+	Type      string         `json:"type"`
+	Subtype   sql.NullString `json:"subtype"`
+	ToolName  sql.NullString `json:"tool_name"`
+	ToolUseID sql.NullString `json:"tool_use_id"`
+	Content   sql.NullString `json:"content"`
+	IsError   bool           `json:"is_error"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
 type Session struct {
-	ID            int64           `json:"id"`
-	ThreadID      int64           `json:"thread_id"`
-	SessionID     string          `json:"session_id"`
-	StartedAt     sql.NullTime    `json:"started_at"`
-	EndedAt       sql.NullTime    `json:"ended_at"`
-	Status        sql.NullString  `json:"status"`
-	Model         sql.NullString  `json:"model"`
-	TotalCostUsd  sql.NullFloat64 `json:"total_cost_usd"`
-	InputTokens   sql.NullInt64   `json:"input_tokens"`
-	OutputTokens  sql.NullInt64   `json:"output_tokens"`
-	DurationMs    sql.NullInt64   `json:"duration_ms"`
-	NumTurns      sql.NullInt64   `json:"num_turns"`
-	InitialPrompt sql.NullString  `json:"initial_prompt"`
+	ID               int64           `json:"id"`
+	ThreadID         int64           `json:"thread_id"`
+	SessionID        string          `json:"session_id"`
+	StartedAt        sql.NullTime    `json:"started_at"`
+	EndedAt          sql.NullTime    `json:"ended_at"`
+	Status           sql.NullString  `json:"status"`
+	Model            sql.NullString  `json:"model"`
+	TotalCostUsd     sql.NullFloat64 `json:"total_cost_usd"`
+	InputTokens      sql.NullInt64   `json:"input_tokens"`
+	OutputTokens     sql.NullInt64   `json:"output_tokens"`
+	DurationMs       sql.NullInt64   `json:"duration_ms"`
+	NumTurns         sql.NullInt64   `json:"num_turns"`
+	InitialPrompt    sql.NullString  `json:"initial_prompt"`
+	PermissionMode   sql.NullString  `json:"permission_mode"`
+	ExtendedThinking sql.NullBool    `json:"extended_thinking"`
+	UserID           sql.NullString  `json:"user_id"`
 }
 
 type Thread struct {
-	ID               int64        `json:"id"`
-	ChannelID        string       `json:"channel_id"`
-	ThreadTs         string       `json:"thread_ts"`
-	WorkingDirectory string       `json:"working_directory"`
-	CreatedAt        sql.NullTime `json:"created_at"`
-	UpdatedAt        sql.NullTime `json:"updated_at"`
+	ID               int64          `json:"id"`
+	ChannelID        string         `json:"channel_id"`
+	ThreadTs         string         `json:"thread_ts"`
+	WorkingDirectory string         `json:"working_directory"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	WorktreePath     sql.NullString `json:"worktree_path"`
+	WorktreeBranch   sql.NullString `json:"worktree_branch"`
+	ArchivedAt       sql.NullTime   `json:"archived_at"`
+	PullRequestUrl   sql.NullString `json:"pull_request_url"`
 }
@@ -10,6 +10,7 @@ import (
 
 type Querier interface {
 	CountActiveSessionsByThread(ctx context.Context, threadID int64) (int64, error)
+	CreateMessage(ctx context.Context, arg CreateMessageParams) error
 	CreateSessionWithInitialPrompt(ctx context.Context, arg CreateSessionWithInitialPromptParams) (Session, error)
 	CreateThread(ctx context.Context, arg CreateThreadParams) (Thread, error)
 	GetActiveSessionByThread(ctx context.Context, threadID int64) (Session, error)
@@ -19,6 +20,8 @@ type Querier interface {
 	GetThreadByID(ctx context.Context, id int64) (Thread, error)
 	GetThreadByThreadTs(ctx context.Context, threadTs string) (Thread, error)
 	ListActiveSessions(ctx context.Context) ([]Session, error)
+	ListMessagesBySessionID(ctx context.Context, sessionID int64) ([]Message, error)
+	ListRecentSessionsByUser(ctx context.Context, arg ListRecentSessionsByUserParams) ([]ListRecentSessionsByUserRow, error)
 	ListSessions(ctx context.Context) ([]Session, error)
 	ListSessionsByThreadID(ctx context.Context, threadID int64) ([]Session, error)
 	ListSessionsByThreadIDPaginated(ctx context.Context, arg ListSessionsByThreadIDPaginatedParams) ([]Session, error)
@@ -30,7 +33,10 @@ type Querier interface {
 	UpdateSessionModel(ctx context.Context, arg UpdateSessionModelParams) error
 	UpdateSessionOnComplete(ctx context.Context, arg UpdateSessionOnCompleteParams) error
 	UpdateSessionStatus(ctx context.Context, arg UpdateSessionStatusParams) error
+	UpdateThreadArchivedAt(ctx context.Context, arg UpdateThreadArchivedAtParams) error
+	UpdateThreadPullRequestURL(ctx context.Context, arg UpdateThreadPullRequestURLParams) error
 	UpdateThreadTimestamp(ctx context.Context, id int64) error
+	UpdateThreadWorktree(ctx context.Context, arg UpdateThreadWorktreeParams) error
 }
 
 var _ Querier = (*Queries)(nil)
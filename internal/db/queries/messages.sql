@@ -0,0 +1,11 @@
+-- name: CreateMessage :exec
+INSERT INTO messages (
+    session_id, type, subtype, tool_name, tool_use_id, content, is_error
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListMessagesBySessionID :many
+SELECT * FROM messages
+WHERE session_id = ?
+ORDER BY id ASC;
@@ -7,7 +7,7 @@ LIMIT 1;
 SELECT s.*
 FROM sessions s
 WHERE s.thread_id = ?
-  AND s.status = 'completed'
+  AND s.status IN ('completed', 'interrupted')
 ORDER BY s.ended_at DESC
 LIMIT 1;
 
@@ -21,9 +21,9 @@ LIMIT 1;
 
 -- name: CreateSessionWithInitialPrompt :one
 INSERT INTO sessions (
-    thread_id, session_id, model, initial_prompt
+    thread_id, session_id, model, initial_prompt, permission_mode, extended_thinking, user_id
 ) VALUES (
-    ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING *;
 
@@ -93,4 +93,11 @@ LIMIT ? OFFSET ?;
 SELECT * FROM sessions
 WHERE thread_id = ?
 ORDER BY started_at ASC
-LIMIT ? OFFSET ?;
\ No newline at end of file
+LIMIT ? OFFSET ?;
+-- name: ListRecentSessionsByUser :many
+SELECT s.session_id, s.status, s.ended_at, s.total_cost_usd, t.channel_id, t.thread_ts, t.working_directory
+FROM sessions s
+JOIN threads t ON t.id = s.thread_id
+WHERE s.user_id = ? AND s.ended_at IS NOT NULL
+ORDER BY s.ended_at DESC
+LIMIT ?;
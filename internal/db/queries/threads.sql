@@ -43,4 +43,19 @@ LEFT JOIN (
     FROM sessions
 ) s ON t.id = s.thread_id AND s.rn = 1
 ORDER BY t.updated_at DESC
-LIMIT ? OFFSET ?;
\ No newline at end of file
+LIMIT ? OFFSET ?;
+
+-- name: UpdateThreadWorktree :exec
+UPDATE threads
+SET worktree_path = ?, worktree_branch = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: UpdateThreadArchivedAt :exec
+UPDATE threads
+SET archived_at = ?
+WHERE id = ?;
+
+-- name: UpdateThreadPullRequestURL :exec
+UPDATE threads
+SET pull_request_url = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
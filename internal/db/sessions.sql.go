@@ -25,18 +25,21 @@ func (q *Queries) CountActiveSessionsByThread(ctx context.Context, threadID int6
 
 const createSessionWithInitialPrompt = `-- name: CreateSessionWithInitialPrompt :one
 INSERT INTO sessions (
-    thread_id, session_id, model, initial_prompt
+    thread_id, session_id, model, initial_prompt, permission_mode, extended_thinking, user_id
 ) VALUES (
-    ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?
 )
-RETURNING id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt
+RETURNING id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt, permission_mode, extended_thinking, user_id
 `
 
 type CreateSessionWithInitialPromptParams struct {
-	ThreadID      int64          `json:"thread_id"`
-	SessionID     string         `json:"session_id"`
-	Model         sql.NullString `json:"model"`
-	InitialPrompt sql.NullString `json:"initial_prompt"`
+	ThreadID         int64          `json:"thread_id"`
+	SessionID        string         `json:"session_id"`
+	Model            sql.NullString `json:"model"`
+	InitialPrompt    sql.NullString `json:"initial_prompt"`
+	PermissionMode   sql.NullString `json:"permission_mode"`
+	ExtendedThinking sql.NullBool   `json:"extended_thinking"`
+	UserID           sql.NullString `json:"user_id"`
 }
 
 func (q *Queries) CreateSessionWithInitialPrompt(ctx context.Context, arg CreateSessionWithInitialPromptParams) (Session, error) {
@@ -45,6 +48,9 @@ func (q *Queries) CreateSessionWithInitialPrompt(ctx context.Context, arg Create
 		arg.SessionID,
 		arg.Model,
 		arg.InitialPrompt,
+		arg.PermissionMode,
+		arg.ExtendedThinking,
+		arg.UserID,
 	)
 	var i Session
 	err := row.Scan(
@@ -61,12 +67,15 @@ func (q *Queries) CreateSessionWithInitialPrompt(ctx context.Context, arg Create
 		&i.DurationMs,
 		&i.NumTurns,
 		&i.InitialPrompt,
+		&i.PermissionMode,
+		&i.ExtendedThinking,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const getActiveSessionByThread = `-- name: GetActiveSessionByThread :one
-SELECT s.id, s.thread_id, s.session_id, s.started_at, s.ended_at, s.status, s.model, s.total_cost_usd, s.input_tokens, s.output_tokens, s.duration_ms, s.num_turns, s.initial_prompt
+SELECT s.id, s.thread_id, s.session_id, s.started_at, s.ended_at, s.status, s.model, s.total_cost_usd, s.input_tokens, s.output_tokens, s.duration_ms, s.num_turns, s.initial_prompt, s.permission_mode, s.extended_thinking, s.user_id
 FROM sessions s
 WHERE s.thread_id = ?
   AND s.status = 'active'
@@ -91,15 +100,18 @@ func (q *Queries) GetActiveSessionByThread(ctx context.Context, threadID int64)
 		&i.DurationMs,
 		&i.NumTurns,
 		&i.InitialPrompt,
+		&i.PermissionMode,
+		&i.ExtendedThinking,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const getLatestSessionByThread = `-- name: GetLatestSessionByThread :one
-SELECT s.id, s.thread_id, s.session_id, s.started_at, s.ended_at, s.status, s.model, s.total_cost_usd, s.input_tokens, s.output_tokens, s.duration_ms, s.num_turns, s.initial_prompt
+SELECT s.id, s.thread_id, s.session_id, s.started_at, s.ended_at, s.status, s.model, s.total_cost_usd, s.input_tokens, s.output_tokens, s.duration_ms, s.num_turns, s.initial_prompt, s.permission_mode, s.extended_thinking, s.user_id
 FROM sessions s
 WHERE s.thread_id = ?
-  AND s.status = 'completed'
+  AND s.status IN ('completed', 'interrupted')
 ORDER BY s.ended_at DESC
 LIMIT 1
 `
@@ -121,12 +133,15 @@ func (q *Queries) GetLatestSessionByThread(ctx context.Context, threadID int64)
 		&i.DurationMs,
 		&i.NumTurns,
 		&i.InitialPrompt,
+		&i.PermissionMode,
+		&i.ExtendedThinking,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const getSession = `-- name: GetSession :one
-SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt FROM sessions
+SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt, permission_mode, extended_thinking, user_id FROM sessions
 WHERE session_id = ?
 LIMIT 1
 `
@@ -148,12 +163,15 @@ func (q *Queries) GetSession(ctx context.Context, sessionID string) (Session, er
 		&i.DurationMs,
 		&i.NumTurns,
 		&i.InitialPrompt,
+		&i.PermissionMode,
+		&i.ExtendedThinking,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const listActiveSessions = `-- name: ListActiveSessions :many
-SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt FROM sessions
+SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt, permission_mode, extended_thinking, user_id FROM sessions
 WHERE status = 'active'
 ORDER BY started_at DESC
 `
@@ -181,6 +199,64 @@ func (q *Queries) ListActiveSessions(ctx context.Context) ([]Session, error) {
 			&i.DurationMs,
 			&i.NumTurns,
 			&i.InitialPrompt,
+			&i.PermissionMode,
+			&i.ExtendedThinking,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentSessionsByUser = `-- name: ListRecentSessionsByUser :many
+SELECT s.session_id, s.status, s.ended_at, s.total_cost_usd, t.channel_id, t.thread_ts, t.working_directory
+FROM sessions s
+JOIN threads t ON t.id = s.thread_id
+WHERE s.user_id = ? AND s.ended_at IS NOT NULL
+ORDER BY s.ended_at DESC
+LIMIT ?
+`
+
+type ListRecentSessionsByUserParams struct {
+	UserID sql.NullString `json:"user_id"`
+	Limit  int64          `json:"limit"`
+}
+
+type ListRecentSessionsByUserRow struct {
+	SessionID        string          `json:"session_id"`
+	Status           sql.NullString  `json:"status"`
+	EndedAt          sql.NullTime    `json:"ended_at"`
+	TotalCostUsd     sql.NullFloat64 `json:"total_cost_usd"`
+	ChannelID        string          `json:"channel_id"`
+	ThreadTs         string          `json:"thread_ts"`
+	WorkingDirectory string          `json:"working_directory"`
+}
+
+func (q *Queries) ListRecentSessionsByUser(ctx context.Context, arg ListRecentSessionsByUserParams) ([]ListRecentSessionsByUserRow, error) {
+	rows, err := q.query(ctx, q.listRecentSessionsByUserStmt, listRecentSessionsByUser, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecentSessionsByUserRow
+	for rows.Next() {
+		var i ListRecentSessionsByUserRow
+		if err := rows.Scan(
+			&i.SessionID,
+			&i.Status,
+			&i.EndedAt,
+			&i.TotalCostUsd,
+			&i.ChannelID,
+			&i.ThreadTs,
+			&i.WorkingDirectory,
 		); err != nil {
 			return nil, err
 		}
@@ -196,7 +272,7 @@ func (q *Queries) ListActiveSessions(ctx context.Context) ([]Session, error) {
 }
 
 const listSessions = `-- name: ListSessions :many
-SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt FROM sessions
+SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt, permission_mode, extended_thinking, user_id FROM sessions
 ORDER BY started_at DESC
 `
 
@@ -223,6 +299,9 @@ func (q *Queries) ListSessions(ctx context.Context) ([]Session, error) {
 			&i.DurationMs,
 			&i.NumTurns,
 			&i.InitialPrompt,
+			&i.PermissionMode,
+			&i.ExtendedThinking,
+			&i.UserID,
 		); err != nil {
 			return nil, err
 		}
@@ -238,7 +317,7 @@ func (q *Queries) ListSessions(ctx context.Context) ([]Session, error) {
 }
 
 const listSessionsByThreadID = `-- name: ListSessionsByThreadID :many
-SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt FROM sessions
+SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt, permission_mode, extended_thinking, user_id FROM sessions
 WHERE thread_id = ?
 ORDER BY started_at ASC
 `
@@ -266,6 +345,9 @@ func (q *Queries) ListSessionsByThreadID(ctx context.Context, threadID int64) ([
 			&i.DurationMs,
 			&i.NumTurns,
 			&i.InitialPrompt,
+			&i.PermissionMode,
+			&i.ExtendedThinking,
+			&i.UserID,
 		); err != nil {
 			return nil, err
 		}
@@ -281,7 +363,7 @@ func (q *Queries) ListSessionsByThreadID(ctx context.Context, threadID int64) ([
 }
 
 const listSessionsByThreadIDPaginated = `-- name: ListSessionsByThreadIDPaginated :many
-SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt FROM sessions
+SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt, permission_mode, extended_thinking, user_id FROM sessions
 WHERE thread_id = ?
 ORDER BY started_at ASC
 LIMIT ? OFFSET ?
@@ -316,6 +398,9 @@ func (q *Queries) ListSessionsByThreadIDPaginated(ctx context.Context, arg ListS
 			&i.DurationMs,
 			&i.NumTurns,
 			&i.InitialPrompt,
+			&i.PermissionMode,
+			&i.ExtendedThinking,
+			&i.UserID,
 		); err != nil {
 			return nil, err
 		}
@@ -331,7 +416,7 @@ func (q *Queries) ListSessionsByThreadIDPaginated(ctx context.Context, arg ListS
 }
 
 const listSessionsPaginated = `-- name: ListSessionsPaginated :many
-SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt FROM sessions
+SELECT id, thread_id, session_id, started_at, ended_at, status, model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt, permission_mode, extended_thinking, user_id FROM sessions
 ORDER BY started_at DESC
 LIMIT ? OFFSET ?
 `
@@ -364,6 +449,9 @@ func (q *Queries) ListSessionsPaginated(ctx context.Context, arg ListSessionsPag
 			&i.DurationMs,
 			&i.NumTurns,
 			&i.InitialPrompt,
+			&i.PermissionMode,
+			&i.ExtendedThinking,
+			&i.UserID,
 		); err != nil {
 			return nil, err
 		}
@@ -16,7 +16,7 @@ INSERT INTO threads (
 ) VALUES (
     ?, ?, ?
 )
-RETURNING id, channel_id, thread_ts, working_directory, created_at, updated_at
+RETURNING id, channel_id, thread_ts, working_directory, created_at, updated_at, worktree_path, worktree_branch, archived_at, pull_request_url
 `
 
 type CreateThreadParams struct {
@@ -35,12 +35,16 @@ func (q *Queries) CreateThread(ctx context.Context, arg CreateThreadParams) (Thr
 		&i.WorkingDirectory,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorktreePath,
+		&i.WorktreeBranch,
+		&i.ArchivedAt,
+		&i.PullRequestUrl,
 	)
 	return i, err
 }
 
 const getThread = `-- name: GetThread :one
-SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at FROM threads
+SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at, worktree_path, worktree_branch, archived_at, pull_request_url FROM threads
 WHERE channel_id = ? AND thread_ts = ?
 LIMIT 1
 `
@@ -60,12 +64,16 @@ func (q *Queries) GetThread(ctx context.Context, arg GetThreadParams) (Thread, e
 		&i.WorkingDirectory,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorktreePath,
+		&i.WorktreeBranch,
+		&i.ArchivedAt,
+		&i.PullRequestUrl,
 	)
 	return i, err
 }
 
 const getThreadByID = `-- name: GetThreadByID :one
-SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at FROM threads
+SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at, worktree_path, worktree_branch, archived_at, pull_request_url FROM threads
 WHERE id = ?
 LIMIT 1
 `
@@ -80,12 +88,16 @@ func (q *Queries) GetThreadByID(ctx context.Context, id int64) (Thread, error) {
 		&i.WorkingDirectory,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorktreePath,
+		&i.WorktreeBranch,
+		&i.ArchivedAt,
+		&i.PullRequestUrl,
 	)
 	return i, err
 }
 
 const getThreadByThreadTs = `-- name: GetThreadByThreadTs :one
-SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at FROM threads
+SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at, worktree_path, worktree_branch, archived_at, pull_request_url FROM threads
 WHERE thread_ts = ?
 LIMIT 1
 `
@@ -100,12 +112,16 @@ func (q *Queries) GetThreadByThreadTs(ctx context.Context, threadTs string) (Thr
 		&i.WorkingDirectory,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorktreePath,
+		&i.WorktreeBranch,
+		&i.ArchivedAt,
+		&i.PullRequestUrl,
 	)
 	return i, err
 }
 
 const listThreads = `-- name: ListThreads :many
-SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at FROM threads
+SELECT id, channel_id, thread_ts, working_directory, created_at, updated_at, worktree_path, worktree_branch, archived_at, pull_request_url FROM threads
 ORDER BY updated_at DESC
 `
 
@@ -125,6 +141,10 @@ func (q *Queries) ListThreads(ctx context.Context) ([]Thread, error) {
 			&i.WorkingDirectory,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WorktreePath,
+			&i.WorktreeBranch,
+			&i.ArchivedAt,
+			&i.PullRequestUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -141,7 +161,7 @@ func (q *Queries) ListThreads(ctx context.Context) ([]Thread, error) {
 
 const listThreadsPaginated = `-- name: ListThreadsPaginated :many
 SELECT 
-    t.id, t.channel_id, t.thread_ts, t.working_directory, t.created_at, t.updated_at,
+    t.id, t.channel_id, t.thread_ts, t.working_directory, t.created_at, t.updated_at, t.worktree_path, t.worktree_branch, t.archived_at, t.pull_request_url,
     s.initial_prompt AS first_session_prompt
 FROM threads t
 LEFT JOIN (
@@ -167,6 +187,10 @@ type ListThreadsPaginatedRow struct {
 	WorkingDirectory   string         `json:"working_directory"`
 	CreatedAt          sql.NullTime   `json:"created_at"`
 	UpdatedAt          sql.NullTime   `json:"updated_at"`
+	WorktreePath       sql.NullString `json:"worktree_path"`
+	WorktreeBranch     sql.NullString `json:"worktree_branch"`
+	ArchivedAt         sql.NullTime   `json:"archived_at"`
+	PullRequestUrl     sql.NullString `json:"pull_request_url"`
 	FirstSessionPrompt sql.NullString `json:"first_session_prompt"`
 }
 
@@ -186,6 +210,10 @@ func (q *Queries) ListThreadsPaginated(ctx context.Context, arg ListThreadsPagin
 			&i.WorkingDirectory,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WorktreePath,
+			&i.WorktreeBranch,
+			&i.ArchivedAt,
+			&i.PullRequestUrl,
 			&i.FirstSessionPrompt,
 		); err != nil {
 			return nil, err
@@ -201,6 +229,38 @@ func (q *Queries) ListThreadsPaginated(ctx context.Context, arg ListThreadsPagin
 	return items, nil
 }
 
+const updateThreadArchivedAt = `-- name: UpdateThreadArchivedAt :exec
+UPDATE threads
+SET archived_at = ?
+WHERE id = ?
+`
+
+type UpdateThreadArchivedAtParams struct {
+	ArchivedAt sql.NullTime `json:"archived_at"`
+	ID         int64        `json:"id"`
+}
+
+func (q *Queries) UpdateThreadArchivedAt(ctx context.Context, arg UpdateThreadArchivedAtParams) error {
+	_, err := q.exec(ctx, q.updateThreadArchivedAtStmt, updateThreadArchivedAt, arg.ArchivedAt, arg.ID)
+	return err
+}
+
+const updateThreadPullRequestURL = `-- name: UpdateThreadPullRequestURL :exec
+UPDATE threads
+SET pull_request_url = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateThreadPullRequestURLParams struct {
+	PullRequestUrl sql.NullString `json:"pull_request_url"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) UpdateThreadPullRequestURL(ctx context.Context, arg UpdateThreadPullRequestURLParams) error {
+	_, err := q.exec(ctx, q.updateThreadPullRequestURLStmt, updateThreadPullRequestURL, arg.PullRequestUrl, arg.ID)
+	return err
+}
+
 const updateThreadTimestamp = `-- name: UpdateThreadTimestamp :exec
 UPDATE threads
 SET updated_at = CURRENT_TIMESTAMP
@@ -211,3 +271,20 @@ func (q *Queries) UpdateThreadTimestamp(ctx context.Context, id int64) error {
 	_, err := q.exec(ctx, q.updateThreadTimestampStmt, updateThreadTimestamp, id)
 	return err
 }
+
+const updateThreadWorktree = `-- name: UpdateThreadWorktree :exec
+UPDATE threads
+SET worktree_path = ?, worktree_branch = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateThreadWorktreeParams struct {
+	WorktreePath   sql.NullString `json:"worktree_path"`
+	WorktreeBranch sql.NullString `json:"worktree_branch"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) UpdateThreadWorktree(ctx context.Context, arg UpdateThreadWorktreeParams) error {
+	_, err := q.exec(ctx, q.updateThreadWorktreeStmt, updateThreadWorktree, arg.WorktreePath, arg.WorktreeBranch, arg.ID)
+	return err
+}
@@ -0,0 +1,358 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// maxLCSCells bounds the work of the line matching; larger changes are shown as a full replacement
+const maxLCSCells = 4_000_000
+
+// maxFileSize is the largest file read from disk to compute a diff
+const maxFileSize = 1 << 20
+
+// Result is a unified diff of a file change
+type Result struct {
+	Path    string
+	Text    string // Unified diff hunks without file headers
+	Added   int
+	Removed int
+}
+
+// String returns the unified diff with file headers
+func (r Result) String() string {
+	return fmt.Sprintf("--- a/%s\n+++ b/%s\n%s", strings.TrimPrefix(r.Path, "/"), strings.TrimPrefix(r.Path, "/"), r.Text)
+}
+
+// op is a line operation of a diff
+type op struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// FromToolInput builds the diff of an Edit, MultiEdit or Write tool input
+// The current file is read from disk when it is inside one of dirs so hunks carry real line numbers;
+// otherwise Edit and MultiEdit fall back to diffing old_string against new_string, and Write shows
+// the new content only. Files outside dirs are never read, so a change proposed to a file such as
+// ~/.ssh/id_rsa does not post its current contents.
+func FromToolInput(toolName string, input map[string]interface{}, dirs []string) (Result, bool) {
+	filePath, _ := input["file_path"].(string)
+	if filePath == "" {
+		return Result{}, false
+	}
+
+	var current string
+	var readable bool
+	if insideAny(filePath, dirs) {
+		current, readable = readFile(filePath)
+	}
+
+	switch toolName {
+	case "Write":
+		content, ok := input["content"].(string)
+		if !ok {
+			return Result{}, false
+		}
+		return Unified(filePath, current, content), true
+
+	case "Edit":
+		e, ok := parseEdit(input)
+		if !ok {
+			return Result{}, false
+		}
+		if readable {
+			if updated, ok := applyEdits(current, []edit{e}); ok {
+				return Unified(filePath, current, updated), true
+			}
+		}
+		return Unified(filePath, e.oldString, e.newString), true
+
+	case "MultiEdit":
+		rawEdits, _ := input["edits"].([]interface{})
+		var edits []edit
+		for _, raw := range rawEdits {
+			if m, ok := raw.(map[string]interface{}); ok {
+				if e, ok := parseEdit(m); ok {
+					edits = append(edits, e)
+				}
+			}
+		}
+		if len(edits) == 0 {
+			return Result{}, false
+		}
+		if readable {
+			if updated, ok := applyEdits(current, edits); ok {
+				return Unified(filePath, current, updated), true
+			}
+		}
+
+		// Without the file, show each edit as its own set of hunks
+		result := Result{Path: filePath}
+		for _, e := range edits {
+			r := Unified(filePath, e.oldString, e.newString)
+			result.Text += r.Text
+			result.Added += r.Added
+			result.Removed += r.Removed
+		}
+		return result, true
+
+	default:
+		return Result{}, false
+	}
+}
+
+// Unified computes a unified diff between two texts
+func Unified(path, oldText, newText string) Result {
+	result := Result{Path: path}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	for _, o := range ops {
+		switch o.kind {
+		case '-':
+			result.Removed++
+		case '+':
+			result.Added++
+		}
+	}
+
+	result.Text = formatHunks(ops)
+	return result
+}
+
+// edit is a single old_string/new_string replacement
+type edit struct {
+	oldString  string
+	newString  string
+	replaceAll bool
+}
+
+// parseEdit reads an edit from Edit tool input or a MultiEdit edits entry
+func parseEdit(input map[string]interface{}) (edit, bool) {
+	oldString, ok := input["old_string"].(string)
+	if !ok {
+		return edit{}, false
+	}
+	newString, _ := input["new_string"].(string)
+	replaceAll, _ := input["replace_all"].(bool)
+	return edit{oldString: oldString, newString: newString, replaceAll: replaceAll}, true
+}
+
+// applyEdits applies edits to content in order, failing when an old_string is not found
+func applyEdits(content string, edits []edit) (string, bool) {
+	for _, e := range edits {
+		if e.oldString == "" || !strings.Contains(content, e.oldString) {
+			return "", false
+		}
+		n := 1
+		if e.replaceAll {
+			n = -1
+		}
+		content = strings.Replace(content, e.oldString, e.newString, n)
+	}
+	return content, true
+}
+
+// insideAny reports whether an absolute path is inside one of dirs once symlinks are resolved
+func insideAny(path string, dirs []string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		resolvedDir, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(resolvedDir, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return true
+		}
+	}
+	return false
+}
+
+// readFile reads a file for diffing, treating missing or large files as unreadable
+func readFile(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxFileSize {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// splitLines splits text into lines without their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes line operations turning a into b
+func diffLines(a, b []string) []op {
+	// Common prefix and suffix are matched directly to keep the table small
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{kind: ' ', line: line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{kind: ' ', line: line})
+	}
+	return ops
+}
+
+// diffMiddle matches lines by longest common subsequence
+func diffMiddle(a, b []string) []op {
+	var ops []op
+	if len(a)*len(b) > maxLCSCells {
+		for _, line := range a {
+			ops = append(ops, op{kind: '-', line: line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{kind: '+', line: line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{kind: ' ', line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{kind: '-', line: a[i]})
+			i++
+		default:
+			ops = append(ops, op{kind: '+', line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{kind: '-', line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{kind: '+', line: b[j]})
+	}
+	return ops
+}
+
+// formatHunks renders line operations as unified diff hunks with context
+func formatHunks(ops []op) string {
+	var text strings.Builder
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				break
+			}
+			end = next
+		}
+
+		hunkStart := max(start-contextLines, 0)
+		hunkEnd := min(end+contextLines, len(ops))
+
+		// Line numbers of the hunk start in the old and new text
+		oldLine, newLine := 1, 1
+		for _, o := range ops[:hunkStart] {
+			if o.kind != '+' {
+				oldLine++
+			}
+			if o.kind != '-' {
+				newLine++
+			}
+		}
+
+		var oldCount, newCount int
+		var body strings.Builder
+		for _, o := range ops[hunkStart:hunkEnd] {
+			if o.kind != '+' {
+				oldCount++
+			}
+			if o.kind != '-' {
+				newCount++
+			}
+			body.WriteByte(o.kind)
+			body.WriteString(o.line)
+			body.WriteByte('\n')
+		}
+
+		text.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount)))
+		text.WriteString(body.String())
+
+		start = hunkEnd
+	}
+
+	return text.String()
+}
+
+// hunkRange formats the line range of a hunk header
+func hunkRange(line, count int) string {
+	if count == 0 {
+		// An empty range refers to the line before the change
+		return fmt.Sprintf("%d,0", line-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
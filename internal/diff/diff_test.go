@@ -0,0 +1,218 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldText     string
+		newText     string
+		want        string
+		wantAdded   int
+		wantRemoved int
+	}{
+		{
+			name:        "single line change",
+			oldText:     "a\nb\nc\n",
+			newText:     "a\nB\nc\n",
+			want:        "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+			wantAdded:   1,
+			wantRemoved: 1,
+		},
+		{
+			name:        "new file",
+			oldText:     "",
+			newText:     "package main\n\nfunc main() {}\n",
+			want:        "@@ -0,0 +1,3 @@\n+package main\n+\n+func main() {}\n",
+			wantAdded:   3,
+			wantRemoved: 0,
+		},
+		{
+			name:        "context is limited",
+			oldText:     "1\n2\n3\n4\n5\n6\n7\n8\n",
+			newText:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			want:        "@@ -6,3 +6,4 @@\n 6\n 7\n 8\n+9\n",
+			wantAdded:   1,
+			wantRemoved: 0,
+		},
+		{
+			name:        "distant changes make separate hunks",
+			oldText:     "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			newText:     "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want:        "@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+			wantAdded:   2,
+			wantRemoved: 2,
+		},
+		{
+			name:    "no changes",
+			oldText: "a\n",
+			newText: "a\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("main.go", tt.oldText, tt.newText)
+			if got.Text != tt.want {
+				t.Errorf("Unified() text =\n%s\nwant\n%s", got.Text, tt.want)
+			}
+			if got.Added != tt.wantAdded || got.Removed != tt.wantRemoved {
+				t.Errorf("Unified() counts = +%d -%d, want +%d -%d", got.Added, got.Removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestFromToolInput(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "main.go")
+	content := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n"
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		toolName    string
+		input       map[string]interface{}
+		wantHeader  string
+		wantAdded   int
+		wantRemoved int
+	}{
+		{
+			name:     "edit uses file line numbers",
+			toolName: "Edit",
+			input: map[string]interface{}{
+				"file_path":  filePath,
+				"old_string": "\"hello\"",
+				"new_string": "\"hello, world\"",
+			},
+			wantHeader:  "@@ -3,5 +3,5 @@",
+			wantAdded:   1,
+			wantRemoved: 1,
+		},
+		{
+			name:     "edit of missing file diffs the strings",
+			toolName: "Edit",
+			input: map[string]interface{}{
+				"file_path":  filepath.Join(dir, "missing.go"),
+				"old_string": "a\nb",
+				"new_string": "a\nc",
+			},
+			wantHeader:  "@@ -1,2 +1,2 @@",
+			wantAdded:   1,
+			wantRemoved: 1,
+		},
+		{
+			name:     "multi edit",
+			toolName: "MultiEdit",
+			input: map[string]interface{}{
+				"file_path": filePath,
+				"edits": []interface{}{
+					map[string]interface{}{"old_string": "package main", "new_string": "package app"},
+					map[string]interface{}{"old_string": "func main()", "new_string": "func Run()"},
+				},
+			},
+			wantHeader:  "@@ -1,7 +1,7 @@",
+			wantAdded:   2,
+			wantRemoved: 2,
+		},
+		{
+			name:     "write new file",
+			toolName: "Write",
+			input: map[string]interface{}{
+				"file_path": filepath.Join(dir, "new.txt"),
+				"content":   "hello\n",
+			},
+			wantHeader: "@@ -0,0 +1 @@",
+			wantAdded:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FromToolInput(tt.toolName, tt.input, []string{dir})
+			if !ok {
+				t.Fatal("FromToolInput() ok = false")
+			}
+			if !strings.HasPrefix(got.Text, tt.wantHeader+"\n") {
+				t.Errorf("FromToolInput() text =\n%s\nwant header %s", got.Text, tt.wantHeader)
+			}
+			if got.Added != tt.wantAdded || got.Removed != tt.wantRemoved {
+				t.Errorf("FromToolInput() counts = +%d -%d, want +%d -%d", got.Added, got.Removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestFromToolInputUnsupportedTool(t *testing.T) {
+	if _, ok := FromToolInput("Bash", map[string]interface{}{"command": "ls"}, nil); ok {
+		t.Error("FromToolInput() ok = true for Bash")
+	}
+}
+
+func TestFromToolInputOutsideDirs(t *testing.T) {
+	workDir := t.TempDir()
+	secretDir := t.TempDir()
+	secretPath := filepath.Join(secretDir, "id_rsa")
+	if err := os.WriteFile(secretPath, []byte("PRIVATE KEY\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	linkPath := filepath.Join(workDir, "key")
+	if err := os.Symlink(secretPath, linkPath); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		toolName string
+		input    map[string]interface{}
+		want     string
+	}{
+		{
+			name:     "write outside the working directory shows the new content only",
+			toolName: "Write",
+			input:    map[string]interface{}{"file_path": secretPath, "content": "replaced\n"},
+			want:     "@@ -0,0 +1 @@\n+replaced\n",
+		},
+		{
+			name:     "edit outside the working directory diffs the strings",
+			toolName: "Edit",
+			input:    map[string]interface{}{"file_path": secretPath, "old_string": "PRIVATE", "new_string": "PUBLIC"},
+			want:     "@@ -1 +1 @@\n-PRIVATE\n+PUBLIC\n",
+		},
+		{
+			name:     "symlink out of the working directory is not followed",
+			toolName: "Write",
+			input:    map[string]interface{}{"file_path": linkPath, "content": "replaced\n"},
+			want:     "@@ -0,0 +1 @@\n+replaced\n",
+		},
+		{
+			name:     "relative path is not read",
+			toolName: "Write",
+			input:    map[string]interface{}{"file_path": "key", "content": "replaced\n"},
+			want:     "@@ -0,0 +1 @@\n+replaced\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FromToolInput(tt.toolName, tt.input, []string{workDir})
+			if !ok {
+				t.Fatal("FromToolInput() ok = false")
+			}
+			if got.Text != tt.want {
+				t.Errorf("FromToolInput() text =\n%s\nwant\n%s", got.Text, tt.want)
+			}
+			if strings.Contains(got.Text, "PRIVATE KEY") {
+				t.Error("FromToolInput() leaked the contents of a file outside the working directory")
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Fake is a Forge that records pull requests instead of opening them, for tests
+type Fake struct {
+	mu           sync.Mutex
+	PullRequests []PullRequest
+	Err          error // Returned by CreatePullRequest when set
+}
+
+// CreatePullRequest records the pull request and returns a URL numbered after it
+func (f *Fake) CreatePullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return "", f.Err
+	}
+	f.PullRequests = append(f.PullRequests, pr)
+	return fmt.Sprintf("https://forge.example.com/pull/%d", len(f.PullRequests)), nil
+}
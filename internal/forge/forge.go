@@ -0,0 +1,18 @@
+package forge
+
+import "context"
+
+// PullRequest describes a pull request to open
+type PullRequest struct {
+	RemoteURL string // URL of the git remote the branch was pushed to
+	Branch    string // Branch with the changes
+	Base      string // Branch the changes are merged into, the repository's default branch when empty
+	Title     string
+	Body      string
+}
+
+// Forge opens pull requests on a code hosting service
+type Forge interface {
+	// CreatePullRequest opens a pull request and returns its URL
+	CreatePullRequest(ctx context.Context, pr PullRequest) (string, error)
+}
@@ -0,0 +1,124 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the URL of the GitHub REST API
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHub opens pull requests through the GitHub REST API
+type GitHub struct {
+	token  string
+	apiURL string
+	client *http.Client
+}
+
+// NewGitHub creates a GitHub forge
+// apiURL can point to a GitHub Enterprise Server API; the public API is used when it is empty.
+func NewGitHub(token, apiURL string) *GitHub {
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+	return &GitHub{
+		token:  token,
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// CreatePullRequest opens a pull request in the repository of the remote URL
+func (g *GitHub) CreatePullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	repo, err := parseGitHubRepository(pr.RemoteURL)
+	if err != nil {
+		return "", err
+	}
+
+	base := pr.Base
+	if base == "" {
+		var repository struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := g.do(ctx, http.MethodGet, "/repos/"+repo, nil, &repository); err != nil {
+			return "", fmt.Errorf("failed to get repository: %w", err)
+		}
+		base = repository.DefaultBranch
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	body := map[string]string{
+		"title": pr.Title,
+		"head":  pr.Branch,
+		"base":  base,
+		"body":  pr.Body,
+	}
+	if err := g.do(ctx, http.MethodPost, "/repos/"+repo+"/pulls", body, &created); err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// do sends a request to the GitHub API and decodes the JSON response into out
+func (g *GitHub) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("GitHub API returned %s: %s", resp.Status, apiErr.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// parseGitHubRepository returns "owner/repo" of an SSH or HTTPS remote URL
+func parseGitHubRepository(remoteURL string) (string, error) {
+	path := remoteURL
+	if u, err := url.Parse(remoteURL); err == nil && u.Scheme != "" {
+		path = u.Path
+	} else if _, after, ok := strings.Cut(remoteURL, ":"); ok {
+		// scp-like syntax: git@github.com:owner/repo.git
+		path = after
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("not a GitHub repository URL: %s", remoteURL)
+	}
+	return path, nil
+}
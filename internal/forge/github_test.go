@@ -0,0 +1,86 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseGitHubRepository(t *testing.T) {
+	tests := []struct {
+		remoteURL string
+		want      string
+		wantErr   bool
+	}{
+		{remoteURL: "git@github.com:yuya-takeyama/cc-slack.git", want: "yuya-takeyama/cc-slack"},
+		{remoteURL: "https://github.com/yuya-takeyama/cc-slack.git", want: "yuya-takeyama/cc-slack"},
+		{remoteURL: "https://github.com/yuya-takeyama/cc-slack", want: "yuya-takeyama/cc-slack"},
+		{remoteURL: "ssh://git@github.com/yuya-takeyama/cc-slack.git", want: "yuya-takeyama/cc-slack"},
+		{remoteURL: "/srv/git/cc-slack.git", wantErr: true},
+		{remoteURL: "https://github.com/yuya-takeyama", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remoteURL, func(t *testing.T) {
+			got, err := parseGitHubRepository(tt.remoteURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGitHubRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseGitHubRepository() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubCreatePullRequest(t *testing.T) {
+	var created map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer test-token")
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo":
+			json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/pulls":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]string{"html_url": "https://github.com/owner/repo/pull/1"})
+		default:
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGitHub("test-token", server.URL)
+	got, err := g.CreatePullRequest(context.Background(), PullRequest{
+		RemoteURL: "git@github.com:owner/repo.git",
+		Branch:    "cc-slack/C123-1",
+		Title:     "Fix the bug",
+		Body:      "Details",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	if got != "https://github.com/owner/repo/pull/1" {
+		t.Errorf("CreatePullRequest() = %q, want %q", got, "https://github.com/owner/repo/pull/1")
+	}
+	want := map[string]string{"title": "Fix the bug", "head": "cc-slack/C123-1", "base": "main", "body": "Details"}
+	for k, v := range want {
+		if created[k] != v {
+			t.Errorf("request %s = %q, want %q", k, created[k], v)
+		}
+	}
+
+	if _, err := g.CreatePullRequest(context.Background(), PullRequest{
+		RemoteURL: "git@github.com:owner/missing.git",
+		Branch:    "cc-slack/C123-1",
+		Base:      "main",
+	}); err == nil {
+		t.Error("CreatePullRequest() expected error for missing repository")
+	}
+}
@@ -0,0 +1,198 @@
+package gitchanges
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileChange is a file changed in a working directory
+type FileChange struct {
+	Path      string
+	Added     int
+	Deleted   int
+	Binary    bool
+	Untracked bool // The file is not tracked by git yet
+}
+
+// Summary describes the changes made in a working directory since a snapshot
+type Summary struct {
+	Branch   string
+	BaseTree string // Tree of the working directory when the snapshot was taken
+	Tree     string // Tree of the working directory when the summary was made
+	Files    []FileChange
+}
+
+// Added returns the number of lines added across all files
+func (s *Summary) Added() int {
+	total := 0
+	for _, f := range s.Files {
+		total += f.Added
+	}
+	return total
+}
+
+// Deleted returns the number of lines deleted across all files
+func (s *Summary) Deleted() int {
+	total := 0
+	for _, f := range s.Files {
+		total += f.Deleted
+	}
+	return total
+}
+
+// treePattern matches git object names, so that names coming back from Slack cannot inject options
+var treePattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// IsRepository reports whether dir is inside a git working tree
+func IsRepository(ctx context.Context, dir string) bool {
+	out, err := git(ctx, dir, nil, "rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// Snapshot records the state of all files in a working directory and returns its tree
+// Untracked files that are not ignored are included, and neither the index nor the
+// working directory is modified: the files are added to a copy of the index.
+func Snapshot(ctx context.Context, dir string) (string, error) {
+	indexPath, err := git(ctx, dir, nil, "rev-parse", "--path-format=absolute", "--git-path", "index")
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp("", "cc-slack-index-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	// Starting from the real index keeps git from hashing files that did not change
+	if index, err := os.Open(strings.TrimSpace(indexPath)); err == nil {
+		_, err = io.Copy(tmp, index)
+		index.Close()
+		if err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("failed to copy index: %w", err)
+		}
+	} else if err := os.Remove(tmpPath); err != nil {
+		// git refuses an empty index file, so a repository without an index starts without one
+		tmp.Close()
+		return "", fmt.Errorf("failed to remove temporary index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temporary index: %w", err)
+	}
+
+	env := []string{"GIT_INDEX_FILE=" + tmpPath}
+	if _, err := git(ctx, dir, env, "add", "--all", "."); err != nil {
+		return "", err
+	}
+	tree, err := git(ctx, dir, env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(tree), nil
+}
+
+// Summarize returns the changes made in a working directory since the snapshot baseTree
+func Summarize(ctx context.Context, dir, baseTree string) (*Summary, error) {
+	tree, err := Snapshot(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Branch:   branch(ctx, dir),
+		BaseTree: baseTree,
+		Tree:     tree,
+	}
+	if tree == baseTree {
+		return summary, nil
+	}
+
+	numstat, err := git(ctx, dir, nil, "diff", "--numstat", "--no-renames", "-z", baseTree, tree)
+	if err != nil {
+		return nil, err
+	}
+	summary.Files = parseNumstat(numstat)
+
+	// Paths are relative to the top of the repository, like those of git diff, even when dir is a subdirectory
+	untracked, err := git(ctx, dir, nil, "ls-files", "--others", "--exclude-standard", "--full-name", "-z")
+	if err != nil {
+		return nil, err
+	}
+	isUntracked := make(map[string]bool)
+	for _, path := range strings.Split(untracked, "\x00") {
+		if path != "" {
+			isUntracked[filepath.ToSlash(path)] = true
+		}
+	}
+	for i := range summary.Files {
+		summary.Files[i].Untracked = isUntracked[summary.Files[i].Path]
+	}
+
+	return summary, nil
+}
+
+// Patch returns the changes between two snapshots of the repository at dir as a patch
+func Patch(ctx context.Context, dir, baseTree, tree string) (string, error) {
+	if !treePattern.MatchString(baseTree) || !treePattern.MatchString(tree) {
+		return "", fmt.Errorf("invalid tree: %q..%q", baseTree, tree)
+	}
+	return git(ctx, dir, nil, "diff", "--binary", baseTree, tree)
+}
+
+// parseNumstat parses the output of git diff --numstat -z
+// Each file is "<added>\t<deleted>\t<path>\x00", and binary files have "-" as their counts.
+func parseNumstat(out string) []FileChange {
+	var files []FileChange
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(record, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		file := FileChange{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			file.Binary = true
+		} else {
+			file.Added, _ = strconv.Atoi(fields[0])
+			file.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// branch returns the checked out branch, or the abbreviated commit when HEAD is detached
+func branch(ctx context.Context, dir string) string {
+	if out, err := git(ctx, dir, nil, "symbolic-ref", "--short", "HEAD"); err == nil {
+		return strings.TrimSpace(out)
+	}
+	if out, err := git(ctx, dir, nil, "rev-parse", "--short", "HEAD"); err == nil {
+		return strings.TrimSpace(out)
+	}
+	return ""
+}
+
+// git runs a git command in dir with additional environment variables and returns its output
+func git(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
@@ -0,0 +1,173 @@
+package gitchanges
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []FileChange
+	}{
+		{
+			name: "empty",
+			out:  "",
+			want: nil,
+		},
+		{
+			name: "text and binary files",
+			out:  "10\t2\tinternal/main.go\x00-\t-\tlogo.png\x000\t5\tdocs/old name.md\x00",
+			want: []FileChange{
+				{Path: "internal/main.go", Added: 10, Deleted: 2},
+				{Path: "logo.png", Binary: true},
+				{Path: "docs/old name.md", Deleted: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNumstat(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNumstat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("main.go", "package main\n")
+	writeFile(".gitignore", "*.log\n")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		if _, err := git(ctx, dir, nil, args...); err != nil {
+			t.Fatalf("failed to set up repository: %v", err)
+		}
+	}
+	// Files that were untracked before the snapshot are not reported as changes
+	writeFile("notes.txt", "draft\n")
+
+	if !IsRepository(ctx, dir) {
+		t.Fatal("IsRepository() = false, want true")
+	}
+	if IsRepository(ctx, t.TempDir()) {
+		t.Error("IsRepository() = true for a directory outside of a repository")
+	}
+
+	base, err := Snapshot(ctx, dir)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	writeFile("main.go", "package main\n\nfunc main() {}\n")
+	writeFile("new.go", "package main\n")
+	writeFile("debug.log", "ignored\n")
+
+	summary, err := Summarize(ctx, dir, base)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if summary.Branch != "main" {
+		t.Errorf("Branch = %q, want %q", summary.Branch, "main")
+	}
+	want := []FileChange{
+		{Path: "main.go", Added: 2},
+		{Path: "new.go", Added: 1, Untracked: true},
+	}
+	if !reflect.DeepEqual(summary.Files, want) {
+		t.Errorf("Files = %+v, want %+v", summary.Files, want)
+	}
+
+	// The snapshots leave the index alone
+	status, err := git(ctx, dir, nil, "status", "--porcelain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(status, " M main.go") || !strings.Contains(status, "?? new.go") {
+		t.Errorf("git status = %q, want main.go unstaged and new.go untracked", status)
+	}
+
+	patch, err := Patch(ctx, dir, summary.BaseTree, summary.Tree)
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if !strings.Contains(patch, "+func main() {}") || !strings.Contains(patch, "new file mode") {
+		t.Errorf("Patch() = %q, want changes of main.go and new.go", patch)
+	}
+
+	if _, err := Patch(ctx, dir, "--output=/tmp/x", summary.Tree); err == nil {
+		t.Error("Patch() expected error for invalid tree")
+	}
+}
+
+func TestSummarizeSubdirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	ctx := context.Background()
+	root := t.TempDir()
+	dir := filepath.Join(root, "app")
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile("main.go", "package main\n")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		if _, err := git(ctx, root, nil, args...); err != nil {
+			t.Fatalf("failed to set up repository: %v", err)
+		}
+	}
+
+	base, err := Snapshot(ctx, dir)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	writeFile("main.go", "package main\n\nfunc main() {}\n")
+	writeFile("new.go", "package main\n")
+
+	summary, err := Summarize(ctx, dir, base)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	want := []FileChange{
+		{Path: "app/main.go", Added: 2},
+		{Path: "app/new.go", Added: 1, Untracked: true},
+	}
+	if !reflect.DeepEqual(summary.Files, want) {
+		t.Errorf("Files = %+v, want %+v", summary.Files, want)
+	}
+}
@@ -7,24 +7,39 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/jsonschema"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rs/zerolog"
+	"github.com/yuya-takeyama/cc-slack/internal/approval"
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/diff"
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
 )
 
+// defaultApprovalTimeout is used when no approval timeout is configured
+const defaultApprovalTimeout = 5 * time.Minute
+
 // SlackPoster interface for posting to Slack
 type SlackPoster interface {
-	PostApprovalRequest(channelID, threadTS, message, requestID, userID string) error
+	PostApprovalRequest(channelID, threadTS, message, requestID, userID string) (string, error)
+	PostApprovalNotice(channelID, threadTS, text string) error
+	UpdateApprovalExpired(channelID, messageTS, message, userID string, timeout time.Duration) error
+	PostDiffSnippet(channelID, threadTS, title string, d diff.Result) error
 }
 
 // SessionInfo represents information about a session
 type SessionInfo struct {
+	SessionID string
 	ChannelID string
 	ThreadTS  string
 	UserID    string
+	WorkDir   string
+	Worktree  string // git worktree the session runs in, empty when it runs in WorkDir
 }
 
 // SessionLookup interface for finding session information
@@ -32,6 +47,11 @@ type SessionLookup interface {
 	GetSessionInfoByToolUseID(toolUseID string) (*SessionInfo, error)
 }
 
+// ApprovalRecorder interface for recording approval decisions
+type ApprovalRecorder interface {
+	RecordApproval(toolUseID, toolName string, response ApprovalResponse)
+}
+
 // Server wraps the MCP server and HTTP handler
 type Server struct {
 	mcp     *mcpsdk.Server
@@ -40,17 +60,45 @@ type Server struct {
 	// Approval requests waiting for response
 	approvalRequests map[string]chan ApprovalResponse
 	approvalInputs   map[string]map[string]interface{} // Store original inputs by requestID
+	pendingApprovals map[string]pendingApproval        // Requests posted to Slack by requestID
+	sessionGrants    map[string][]approval.Grant       // Approvals granted for the rest of a session by session ID
 	approvalMu       sync.Mutex
 
 	// Slack integration
-	slackPoster   SlackPoster
-	sessionLookup SessionLookup
+	slackPoster      SlackPoster
+	sessionLookup    SessionLookup
+	approvalRecorder ApprovalRecorder
+
+	// Policy deciding requests before asking in Slack
+	approvalPolicy *approval.Policy
+
+	// Timeout, reminder and escalation settings for requests asked in Slack
+	approvalConfig config.ApprovalConfig
+	workingDirs    []config.WorkingDirectoryConfig
 
 	// Logger
 	logger  zerolog.Logger
 	logFile *os.File
 }
 
+// pendingApproval is an approval request waiting for an answer in Slack
+type pendingApproval struct {
+	request     ApprovalRequest
+	sessionInfo *SessionInfo
+	requestedAt time.Time
+}
+
+// PendingApproval describes an approval request waiting for an answer in Slack
+type PendingApproval struct {
+	RequestID   string
+	ToolName    string
+	SessionID   string
+	ChannelID   string
+	ThreadTS    string
+	UserID      string // User who started the session
+	RequestedAt time.Time
+}
+
 // ApprovalRequest represents a request for user approval
 // According to Claude Code docs, permission prompt receives:
 // - tool_name: Name of the tool requesting permission
@@ -64,9 +112,10 @@ type ApprovalRequest struct {
 
 // ApprovalResponse represents the approval response
 type ApprovalResponse struct {
-	Behavior     string                 `json:"behavior"` // "allow" or "deny"
+	Behavior     string                 `json:"behavior"` // "allow" or "deny", "rejected" is only recorded for unauthorized approvers
 	Message      string                 `json:"message,omitempty"`
 	UpdatedInput map[string]interface{} `json:"updatedInput,omitempty"`
+	GrantScope   string                 `json:"grantScope,omitempty"` // approval.Scope* to approve matching requests for the rest of the session
 }
 
 // generateLogFileName generates a log file name with prefix and timestamp
@@ -106,6 +155,9 @@ func NewServer() (*Server, error) {
 		mcp:              mcp,
 		approvalRequests: make(map[string]chan ApprovalResponse),
 		approvalInputs:   make(map[string]map[string]interface{}),
+		pendingApprovals: make(map[string]pendingApproval),
+		sessionGrants:    make(map[string][]approval.Grant),
+		approvalConfig:   config.ApprovalConfig{Timeout: defaultApprovalTimeout},
 		logger:           logger,
 		logFile:          logFile,
 	}
@@ -151,6 +203,48 @@ func (s *Server) SetSlackIntegration(poster SlackPoster, lookup SessionLookup) {
 	s.sessionLookup = lookup
 }
 
+// SetApprovalRecorder sets the recorder notified of every approval decision
+func (s *Server) SetApprovalRecorder(recorder ApprovalRecorder) {
+	s.approvalRecorder = recorder
+}
+
+// SetApprovalPolicy sets the policy evaluated before posting approval requests to Slack
+func (s *Server) SetApprovalPolicy(policy *approval.Policy) {
+	s.approvalPolicy = policy
+}
+
+// SetApprovalConfig sets the timeout, reminder and escalation settings for approval requests
+// Working directories can override the timeout for their sessions.
+func (s *Server) SetApprovalConfig(cfg config.ApprovalConfig, workingDirs []config.WorkingDirectoryConfig) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultApprovalTimeout
+	}
+	s.approvalConfig = cfg
+	s.workingDirs = workingDirs
+}
+
+// approvalTimeout returns the approval timeout for a session's working directory
+func (s *Server) approvalTimeout(sessionInfo *SessionInfo) time.Duration {
+	if sessionInfo != nil && sessionInfo.WorkDir != "" {
+		workDir, _ := filepath.Abs(sessionInfo.WorkDir)
+		for _, wd := range s.workingDirs {
+			path, _ := filepath.Abs(wd.Path)
+			if path == workDir && wd.ApprovalTimeout > 0 {
+				return wd.ApprovalTimeout
+			}
+		}
+	}
+	return s.approvalConfig.Timeout
+}
+
+// recordApproval notifies the approval recorder if one is configured
+func (s *Server) recordApproval(req ApprovalRequest, response ApprovalResponse) {
+	if s.approvalRecorder == nil || req.ToolUseID == "" {
+		return
+	}
+	s.approvalRecorder.RecordApproval(req.ToolUseID, req.ToolName, response)
+}
+
 // Handle processes MCP requests
 func (s *Server) Handle(w http.ResponseWriter, r *http.Request) {
 	s.handler.ServeHTTP(w, r)
@@ -186,6 +280,9 @@ func (s *Server) HandleApprovalPrompt(ctx context.Context, session *mcpsdk.Serve
 	s.approvalMu.Unlock()
 
 	// Send approval request to Slack
+	var sessionInfo *SessionInfo
+	var approvalMessage, approvalMessageTS string
+	var approvalDiff *diff.Result
 	if s.slackPoster != nil && s.sessionLookup != nil {
 		// tool_use_id is required for proper session identification
 		if params.Arguments.ToolUseID == "" {
@@ -211,7 +308,8 @@ func (s *Server) HandleApprovalPrompt(ctx context.Context, session *mcpsdk.Serve
 		}
 
 		// Get session info using tool_use_id
-		sessionInfo, err := s.sessionLookup.GetSessionInfoByToolUseID(params.Arguments.ToolUseID)
+		var err error
+		sessionInfo, err = s.sessionLookup.GetSessionInfoByToolUseID(params.Arguments.ToolUseID)
 		if err != nil {
 			s.logger.Error().
 				Err(err).
@@ -237,6 +335,23 @@ func (s *Server) HandleApprovalPrompt(ctx context.Context, session *mcpsdk.Serve
 		}
 
 		if sessionInfo != nil {
+			// Decide by policy without asking in Slack when a rule allows or denies the request
+			decision := s.approvalPolicy.Evaluate(approval.Request{
+				ToolName:  params.Arguments.ToolName,
+				Input:     params.Arguments.Input,
+				WorkDir:   sessionInfo.WorkDir,
+				Worktree:  sessionInfo.Worktree,
+				ChannelID: sessionInfo.ChannelID,
+			})
+			if decision.Action != approval.ActionAsk {
+				return s.applyPolicyDecision(requestID, params.Arguments, sessionInfo, decision)
+			}
+
+			// Approve without asking in Slack when an earlier approval was granted for the session
+			if grant, ok := s.findSessionGrant(sessionInfo.SessionID, params.Arguments); ok {
+				return s.applySessionGrant(requestID, params.Arguments, sessionInfo, grant)
+			}
+
 			// Build approval message based on tool name and input
 			message := fmt.Sprintf("🔐 **Tool execution permission required**\n\n**Tool**: %s", params.Arguments.ToolName)
 
@@ -264,9 +379,36 @@ func (s *Server) HandleApprovalPrompt(ctx context.Context, session *mcpsdk.Serve
 				if filePath, ok := params.Arguments.Input["file_path"].(string); ok {
 					message += fmt.Sprintf("\n\n**File path**: %s", filePath)
 				}
+
+				// Handle Edit, MultiEdit and Write tools
+				dir := sessionInfo.WorkDir
+				if sessionInfo.Worktree != "" {
+					dir = sessionInfo.Worktree
+				}
+				if d, ok := diff.FromToolInput(params.Arguments.ToolName, params.Arguments.Input, []string{dir}); ok {
+					if rel, err := filepath.Rel(dir, d.Path); err == nil && !strings.HasPrefix(rel, "..") {
+						d.Path = rel
+					}
+					approvalDiff = &d
+
+					if d.Text == "" || messages.IsInlineDiff(d) {
+						message += fmt.Sprintf("\n**Changes**: %s", messages.FormatDiffSummary(d))
+						if d.Text != "" {
+							// The diff is kept last because it spans multiple lines
+							message += "\n**Diff**:\n" + messages.FormatDiffCodeBlock(d)
+						}
+					} else {
+						message += fmt.Sprintf("\n**Changes**: %s (full diff attached below)", messages.FormatDiffSummary(d))
+					}
+				}
 			}
 
-			err := s.slackPoster.PostApprovalRequest(sessionInfo.ChannelID, sessionInfo.ThreadTS, message, requestID, sessionInfo.UserID)
+			approvalMessage = message
+			s.approvalMu.Lock()
+			s.pendingApprovals[requestID] = pendingApproval{request: params.Arguments, sessionInfo: sessionInfo, requestedAt: time.Now()}
+			s.approvalMu.Unlock()
+
+			approvalMessageTS, err = s.slackPoster.PostApprovalRequest(sessionInfo.ChannelID, sessionInfo.ThreadTS, message, requestID, sessionInfo.UserID)
 			if err != nil {
 				// Log error but continue with timeout fallback
 				s.logger.Error().
@@ -276,101 +418,389 @@ func (s *Server) HandleApprovalPrompt(ctx context.Context, session *mcpsdk.Serve
 					Str("channel_id", sessionInfo.ChannelID).
 					Str("thread_ts", sessionInfo.ThreadTS).
 					Msg("Failed to post approval request to Slack")
+			} else if approvalDiff != nil && approvalDiff.Text != "" && !messages.IsInlineDiff(*approvalDiff) {
+				if err := s.slackPoster.PostDiffSnippet(sessionInfo.ChannelID, sessionInfo.ThreadTS, approvalDiff.Path, *approvalDiff); err != nil {
+					s.logger.Error().
+						Err(err).
+						Str("method", "HandleApprovalPrompt").
+						Str("request_id", requestID).
+						Str("channel_id", sessionInfo.ChannelID).
+						Str("thread_ts", sessionInfo.ThreadTS).
+						Msg("Failed to upload approval diff to Slack")
+				}
 			}
 		}
 	}
 
-	// Wait for response or timeout
-	select {
-	case resp := <-respChan:
-		// Clean up and get original input
-		s.approvalMu.Lock()
-		delete(s.approvalRequests, requestID)
-		originalInput := s.approvalInputs[requestID]
-		delete(s.approvalInputs, requestID)
-		s.approvalMu.Unlock()
-
-		// Create permission prompt response
-		promptResp := PermissionPromptResponse{
-			Behavior:     resp.Behavior,
-			Message:      resp.Message,
-			UpdatedInput: resp.UpdatedInput,
+	// Wait for response or timeout, reminding and escalating while the request is pending in Slack
+	timeout := s.approvalTimeout(sessionInfo)
+	timeoutTimer := time.NewTimer(timeout)
+	defer timeoutTimer.Stop()
+
+	var reminderC, escalationC <-chan time.Time
+	if approvalMessageTS != "" {
+		if s.approvalConfig.ReminderInterval > 0 {
+			reminder := time.NewTicker(s.approvalConfig.ReminderInterval)
+			defer reminder.Stop()
+			reminderC = reminder.C
 		}
-
-		// Ensure updatedInput is set for allow behavior
-		// If Slack sent empty map or nil, use the original input
-		if promptResp.Behavior == "allow" && (promptResp.UpdatedInput == nil || len(promptResp.UpdatedInput) == 0) {
-			promptResp.UpdatedInput = originalInput
+		if s.approvalConfig.Escalation.After > 0 {
+			escalation := time.NewTimer(s.approvalConfig.Escalation.After)
+			defer escalation.Stop()
+			escalationC = escalation.C
 		}
+	}
+	startedAt := time.Now()
 
-		// Marshal response to JSON
-		jsonData, err := json.Marshal(promptResp)
-		if err != nil {
-			s.logger.Error().
-				Err(err).
+	for {
+		select {
+		case resp := <-respChan:
+			// Clean up and get original input
+			originalInput := s.removeApprovalRequest(requestID)
+
+			// Create permission prompt response
+			promptResp := PermissionPromptResponse{
+				Behavior:     resp.Behavior,
+				Message:      resp.Message,
+				UpdatedInput: resp.UpdatedInput,
+			}
+
+			// Ensure updatedInput is set for allow behavior
+			// If Slack sent empty map or nil, use the original input
+			if promptResp.Behavior == "allow" && (promptResp.UpdatedInput == nil || len(promptResp.UpdatedInput) == 0) {
+				promptResp.UpdatedInput = originalInput
+			}
+
+			if promptResp.Behavior == "allow" && resp.GrantScope != "" && sessionInfo != nil {
+				s.addSessionGrant(sessionInfo.SessionID, resp.GrantScope, params.Arguments.ToolName, originalInput)
+			}
+
+			s.recordApproval(params.Arguments, resp)
+
+			// Marshal response to JSON
+			jsonData, err := json.Marshal(promptResp)
+			if err != nil {
+				s.logger.Error().
+					Err(err).
+					Str("method", "HandleApprovalPrompt").
+					Msg("Failed to marshal approval response")
+				return nil, fmt.Errorf("failed to marshal approval response: %w", err)
+			}
+			// Log approval response
+			s.logger.Info().
 				Str("method", "HandleApprovalPrompt").
-				Msg("Failed to marshal approval response")
-			return nil, fmt.Errorf("failed to marshal approval response: %w", err)
+				Str("request_id", requestID).
+				Str("behavior", promptResp.Behavior).
+				Str("json_response", string(jsonData)).
+				Interface("updated_input", promptResp.UpdatedInput).
+				Msg("Returning approval response")
+
+			result := &mcpsdk.CallToolResultFor[PermissionPromptResponse]{
+				Content: []mcpsdk.Content{
+					&mcpsdk.TextContent{
+						Text: string(jsonData),
+					},
+				},
+			}
+
+			s.logger.Debug().
+				Str("method", "HandleApprovalPrompt").
+				Str("request_id", requestID).
+				Str("content_type", "text/json").
+				Int("content_length", len(jsonData)).
+				Msg("CallToolResultFor created with Content only")
+
+			return result, nil
+
+		case <-reminderC:
+			elapsed := time.Since(startedAt)
+			text := messages.FormatApprovalReminderMessage(sessionInfo.UserID, params.Arguments.ToolName, elapsed, timeout-elapsed)
+			s.postApprovalNotice(requestID, sessionInfo, text)
+
+		case <-escalationC:
+			escalationC = nil
+			escalation := s.approvalConfig.Escalation
+			text := messages.FormatApprovalEscalationMessage(escalation.Users, escalation.UserGroup, params.Arguments.ToolName, time.Since(startedAt))
+			s.postApprovalNotice(requestID, sessionInfo, text)
+
+		case <-timeoutTimer.C:
+			// Timeout - return deny
+			s.removeApprovalRequest(requestID)
+
+			// Replace the buttons so the request can no longer be answered
+			if approvalMessageTS != "" {
+				err := s.slackPoster.UpdateApprovalExpired(sessionInfo.ChannelID, approvalMessageTS, approvalMessage, sessionInfo.UserID, timeout)
+				if err != nil {
+					s.logger.Error().
+						Err(err).
+						Str("method", "HandleApprovalPrompt").
+						Str("request_id", requestID).
+						Str("channel_id", sessionInfo.ChannelID).
+						Str("message_ts", approvalMessageTS).
+						Msg("Failed to mark approval request as expired")
+				}
+			}
+
+			// Create deny response for timeout
+			promptResp := PermissionPromptResponse{
+				Behavior: "deny",
+				Message:  "Approval request timed out",
+			}
+
+			s.recordApproval(params.Arguments, ApprovalResponse{
+				Behavior: promptResp.Behavior,
+				Message:  promptResp.Message,
+			})
+
+			// Convert to JSON for Content field
+			jsonData, _ := json.Marshal(promptResp)
+
+			return &mcpsdk.CallToolResultFor[PermissionPromptResponse]{
+				Content: []mcpsdk.Content{
+					&mcpsdk.TextContent{
+						Text: string(jsonData),
+					},
+				},
+			}, nil
+
+		case <-ctx.Done():
+			// Context cancelled
+			s.removeApprovalRequest(requestID)
+
+			return nil, ctx.Err()
 		}
-		// Log approval response
-		s.logger.Info().
+	}
+}
+
+// applyPolicyDecision responds to an approval request decided by the approval policy
+func (s *Server) applyPolicyDecision(requestID string, req ApprovalRequest, sessionInfo *SessionInfo, decision approval.Decision) (*mcpsdk.CallToolResultFor[PermissionPromptResponse], error) {
+	allowed := decision.Action == approval.ActionAllow
+	resp := ApprovalResponse{
+		Behavior: "deny",
+		Message:  fmt.Sprintf("Denied by approval policy rule %s", decision.Rule),
+	}
+	if allowed {
+		resp.Behavior = "allow"
+		resp.Message = fmt.Sprintf("Auto-approved by approval policy rule %s", decision.Rule)
+		resp.UpdatedInput = req.Input
+	}
+
+	// Log policy decision
+	s.logger.Info().
+		Str("method", "HandleApprovalPrompt").
+		Str("request_id", requestID).
+		Str("tool_name", req.ToolName).
+		Str("tool_use_id", req.ToolUseID).
+		Str("rule", decision.Rule).
+		Str("behavior", resp.Behavior).
+		Msg("Approval request decided by policy")
+
+	text := messages.FormatPolicyDecisionMessage(req.ToolName, decision.Rule, allowed)
+	return s.respondWithoutSlack(requestID, req, sessionInfo, resp, text)
+}
+
+// applySessionGrant approves a request covered by an approval granted earlier in the session
+func (s *Server) applySessionGrant(requestID string, req ApprovalRequest, sessionInfo *SessionInfo, grant approval.Grant) (*mcpsdk.CallToolResultFor[PermissionPromptResponse], error) {
+	resp := ApprovalResponse{
+		Behavior:     "allow",
+		Message:      "Approved for this session via Slack",
+		UpdatedInput: req.Input,
+	}
+
+	// Log session grant
+	s.logger.Info().
+		Str("method", "HandleApprovalPrompt").
+		Str("request_id", requestID).
+		Str("tool_name", req.ToolName).
+		Str("tool_use_id", req.ToolUseID).
+		Str("grant_scope", grant.Scope).
+		Str("grant_value", grant.Value).
+		Msg("Approval request approved by session grant")
+
+	text := messages.FormatSessionGrantMessage(req.ToolName, grant.Description())
+	return s.respondWithoutSlack(requestID, req, sessionInfo, resp, text)
+}
+
+// respondWithoutSlack answers an approval request that is decided without asking in Slack
+// and posts a compact line about the decision to the thread
+func (s *Server) respondWithoutSlack(requestID string, req ApprovalRequest, sessionInfo *SessionInfo, resp ApprovalResponse, text string) (*mcpsdk.CallToolResultFor[PermissionPromptResponse], error) {
+	// The request will not be answered from Slack
+	s.removeApprovalRequest(requestID)
+
+	promptResp := PermissionPromptResponse{
+		Behavior:     resp.Behavior,
+		Message:      resp.Message,
+		UpdatedInput: resp.UpdatedInput,
+	}
+
+	s.recordApproval(req, resp)
+
+	jsonData, err := json.Marshal(promptResp)
+	if err != nil {
+		s.logger.Error().
+			Err(err).
 			Str("method", "HandleApprovalPrompt").
-			Str("request_id", requestID).
-			Str("behavior", promptResp.Behavior).
-			Str("json_response", string(jsonData)).
-			Interface("updated_input", promptResp.UpdatedInput).
-			Msg("Returning approval response")
-
-		result := &mcpsdk.CallToolResultFor[PermissionPromptResponse]{
-			Content: []mcpsdk.Content{
-				&mcpsdk.TextContent{
-					Text: string(jsonData),
-				},
+			Msg("Failed to marshal approval response")
+		return nil, fmt.Errorf("failed to marshal approval response: %w", err)
+	}
+
+	s.postApprovalNotice(requestID, sessionInfo, text)
+
+	return &mcpsdk.CallToolResultFor[PermissionPromptResponse]{
+		Content: []mcpsdk.Content{
+			&mcpsdk.TextContent{
+				Text: string(jsonData),
 			},
-		}
+		},
+	}, nil
+}
+
+// removeApprovalRequest forgets a request that has been answered, timed out or cancelled
+// It returns the original tool input of the request.
+func (s *Server) removeApprovalRequest(requestID string) map[string]interface{} {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+
+	input := s.approvalInputs[requestID]
+	delete(s.approvalRequests, requestID)
+	delete(s.approvalInputs, requestID)
+	delete(s.pendingApprovals, requestID)
+	return input
+}
 
-		s.logger.Debug().
+// postApprovalNotice posts a compact approval-related line to the session's thread
+func (s *Server) postApprovalNotice(requestID string, sessionInfo *SessionInfo, text string) {
+	if err := s.slackPoster.PostApprovalNotice(sessionInfo.ChannelID, sessionInfo.ThreadTS, text); err != nil {
+		s.logger.Error().
+			Err(err).
 			Str("method", "HandleApprovalPrompt").
 			Str("request_id", requestID).
-			Str("content_type", "text/json").
-			Int("content_length", len(jsonData)).
-			Msg("CallToolResultFor created with Content only")
-
-		return result, nil
-
-	case <-time.After(5 * time.Minute):
-		// Timeout - return deny
-		s.approvalMu.Lock()
-		delete(s.approvalRequests, requestID)
-		delete(s.approvalInputs, requestID)
-		s.approvalMu.Unlock()
-
-		// Create deny response for timeout
-		promptResp := PermissionPromptResponse{
-			Behavior: "deny",
-			Message:  "Approval request timed out",
+			Str("channel_id", sessionInfo.ChannelID).
+			Str("thread_ts", sessionInfo.ThreadTS).
+			Msg("Failed to post approval notice to Slack")
+	}
+}
+
+// addSessionGrant records an approval granted for the rest of a session
+func (s *Server) addSessionGrant(sessionID, scope, toolName string, input map[string]interface{}) {
+	if sessionID == "" {
+		return
+	}
+
+	grant, err := approval.NewGrant(scope, toolName, input)
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("method", "addSessionGrant").
+			Str("session_id", sessionID).
+			Str("tool_name", toolName).
+			Msg("Failed to create session grant")
+		return
+	}
+
+	s.approvalMu.Lock()
+	s.sessionGrants[sessionID] = append(s.sessionGrants[sessionID], grant)
+	s.approvalMu.Unlock()
+
+	s.logger.Info().
+		Str("method", "addSessionGrant").
+		Str("session_id", sessionID).
+		Str("tool_name", toolName).
+		Str("grant_scope", grant.Scope).
+		Str("grant_value", grant.Value).
+		Msg("Recorded session grant")
+}
+
+// ClearSessionGrants drops the approvals granted for the rest of a session once it has ended
+func (s *Server) ClearSessionGrants(sessionID string) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	delete(s.sessionGrants, sessionID)
+}
+
+// findSessionGrant returns the session grant covering a request, if any
+func (s *Server) findSessionGrant(sessionID string, req ApprovalRequest) (approval.Grant, bool) {
+	if sessionID == "" {
+		return approval.Grant{}, false
+	}
+
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+
+	for _, grant := range s.sessionGrants[sessionID] {
+		if grant.Matches(req.ToolName, req.Input) {
+			return grant, true
 		}
+	}
+	return approval.Grant{}, false
+}
 
-		// Convert to JSON for Content field
-		jsonData, _ := json.Marshal(promptResp)
+// ApprovalInitiator returns the user who started the session of a pending approval request
+func (s *Server) ApprovalInitiator(requestID string) (string, bool) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
 
-		return &mcpsdk.CallToolResultFor[PermissionPromptResponse]{
-			Content: []mcpsdk.Content{
-				&mcpsdk.TextContent{
-					Text: string(jsonData),
-				},
-			},
-		}, nil
+	pending, exists := s.pendingApprovals[requestID]
+	if !exists {
+		return "", false
+	}
+	return pending.sessionInfo.UserID, true
+}
+
+// ApprovalInput returns the original tool input of a pending approval request
+func (s *Server) ApprovalInput(requestID string) (string, map[string]interface{}, bool) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+
+	pending, exists := s.pendingApprovals[requestID]
+	if !exists {
+		return "", nil, false
+	}
+	return pending.request.ToolName, s.approvalInputs[requestID], true
+}
+
+// PendingApprovals returns the approval requests waiting for an answer in Slack, oldest first
+func (s *Server) PendingApprovals() []PendingApproval {
+	s.approvalMu.Lock()
+	approvals := make([]PendingApproval, 0, len(s.pendingApprovals))
+	for requestID, pending := range s.pendingApprovals {
+		approvals = append(approvals, PendingApproval{
+			RequestID:   requestID,
+			ToolName:    pending.request.ToolName,
+			SessionID:   pending.sessionInfo.SessionID,
+			ChannelID:   pending.sessionInfo.ChannelID,
+			ThreadTS:    pending.sessionInfo.ThreadTS,
+			UserID:      pending.sessionInfo.UserID,
+			RequestedAt: pending.requestedAt,
+		})
+	}
+	s.approvalMu.Unlock()
+
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].RequestedAt.Before(approvals[j].RequestedAt)
+	})
+	return approvals
+}
 
-	case <-ctx.Done():
-		// Context cancelled
-		s.approvalMu.Lock()
-		delete(s.approvalRequests, requestID)
-		delete(s.approvalInputs, requestID)
-		s.approvalMu.Unlock()
+// RecordRejectedApprover records that a user who is not allowed to answer a pending approval request tried to
+func (s *Server) RecordRejectedApprover(requestID, userID string) {
+	s.approvalMu.Lock()
+	pending, exists := s.pendingApprovals[requestID]
+	s.approvalMu.Unlock()
 
-		return nil, ctx.Err()
+	s.logger.Warn().
+		Str("method", "RecordRejectedApprover").
+		Str("request_id", requestID).
+		Str("user_id", userID).
+		Msg("Rejected approval response from unauthorized user")
+
+	if !exists {
+		return
 	}
+	s.recordApproval(pending.request, ApprovalResponse{
+		Behavior: "rejected",
+		Message:  fmt.Sprintf("<@%s> is not allowed to answer this approval request", userID),
+	})
 }
 
 // SendApprovalResponse sends an approval response for a request
@@ -4,15 +4,28 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/diff"
+	"github.com/yuya-takeyama/cc-slack/internal/gitchanges"
+	"github.com/yuya-takeyama/cc-slack/internal/tools"
 )
 
 // FormatSessionStartMessage formats the session start message
-func FormatSessionStartMessage(sessionID, cwd, model string) string {
-	return fmt.Sprintf("✨ Claude Code session started\n"+
+// The permission mode is shown unless it is Claude Code's default.
+func FormatSessionStartMessage(sessionID, cwd, model, permissionMode string, extendedThinking bool) string {
+	text := fmt.Sprintf("✨ Claude Code session started\n"+
 		"Session ID: `%s`\n"+
 		"Working directory: `%s`\n"+
 		"Model: `%s`",
 		sessionID, cwd, model)
+
+	if permissionMode != "" && permissionMode != "default" {
+		text += fmt.Sprintf("\nPermission mode: `%s`", permissionMode)
+	}
+	if extendedThinking {
+		text += "\nExtended thinking: on"
+	}
+	return text
 }
 
 // FormatSessionCompleteMessage formats the session completion message
@@ -89,6 +102,84 @@ func FormatWriteToolMessage(filePath string) string {
 	return fmt.Sprintf("Writing `%s`", filePath)
 }
 
+// MaxInlineDiffLength is the longest diff shown inline in a message; longer diffs are uploaded as a snippet
+const MaxInlineDiffLength = 1500
+
+// IsInlineDiff reports whether a diff is short enough to be shown inline
+func IsInlineDiff(d diff.Result) bool {
+	return len(d.Text) <= MaxInlineDiffLength
+}
+
+// FormatDiffSummary formats the line counts of a diff, e.g. "+3 -1"
+func FormatDiffSummary(d diff.Result) string {
+	return fmt.Sprintf("+%d -%d", d.Added, d.Removed)
+}
+
+// FormatDiffCodeBlock formats a diff as a code block
+func FormatDiffCodeBlock(d diff.Result) string {
+	// Escape triple backticks so the diff cannot close the code block
+	escapedDiff := strings.ReplaceAll(d.Text, "```", "\\`\\`\\`")
+	return fmt.Sprintf("```\n%s```", escapedDiff)
+}
+
+// FormatFileChangeToolMessage adds the line counts and, when short enough, the diff to an Edit/MultiEdit/Write tool message
+func FormatFileChangeToolMessage(message string, d diff.Result) string {
+	text := fmt.Sprintf("%s (%s)", message, FormatDiffSummary(d))
+	if d.Text == "" {
+		return text
+	}
+	if !IsInlineDiff(d) {
+		return text + "\nThe full diff is attached below."
+	}
+	return text + "\n" + FormatDiffCodeBlock(d)
+}
+
+// Tool result previews are limited to keep threads readable
+const (
+	ToolResultPreviewLines = 10
+	ToolResultPreviewChars = 1500
+)
+
+// FormatToolResultMessage formats a truncated preview of a tool result
+// It returns whether the output was truncated.
+func FormatToolResultMessage(output string, isError bool) (string, bool) {
+	output = strings.TrimRight(output, "\n")
+	lines := strings.Split(output, "\n")
+
+	preview := output
+	truncated := false
+	remaining := 0
+	if len(lines) > ToolResultPreviewLines {
+		preview = strings.Join(lines[:ToolResultPreviewLines], "\n")
+		remaining = len(lines) - ToolResultPreviewLines
+		truncated = true
+	}
+	if len(preview) > ToolResultPreviewChars {
+		// Drop a multi-byte character cut in half
+		preview = strings.ToValidUTF8(preview[:ToolResultPreviewChars], "")
+		remaining = len(lines) - strings.Count(preview, "\n") - 1
+		truncated = true
+	}
+
+	// Escape triple backticks so the output cannot close the code block
+	preview = strings.ReplaceAll(preview, "```", "\\`\\`\\`")
+
+	var text strings.Builder
+	if isError {
+		text.WriteString(":x: *Failed*\n")
+	}
+	text.WriteString(fmt.Sprintf("```\n%s\n```", preview))
+	if truncated {
+		if remaining > 0 {
+			text.WriteString(fmt.Sprintf("\n_… %d more lines_", remaining))
+		} else {
+			text.WriteString("\n_… truncated_")
+		}
+	}
+
+	return text.String(), truncated
+}
+
 // FormatLSToolMessage formats the LS tool message
 func FormatLSToolMessage(path string) string {
 	return fmt.Sprintf("Listing `%s`", path)
@@ -153,12 +244,257 @@ func FormatCompletionMessage(sessionID string, turns int, cost float64) string {
 	return text
 }
 
+// SessionStatus is the progress of a session shown in the compact mode status message
+type SessionStatus struct {
+	Activity   string // What Claude is currently doing, e.g. "Running `go test ./...`"
+	ToolUses   int
+	TodosDone  int
+	TodosTotal int
+	Todo       string // The todo in progress
+	Elapsed    time.Duration
+	Outcome    string // Empty while running, otherwise the final state such as "✅ Finished"
+}
+
+// toolActivityVerbs describe what a tool is doing in the compact mode status message
+var toolActivityVerbs = map[string]string{
+	"Bash":      "Running",
+	"Read":      "Reading",
+	"Edit":      "Editing",
+	"MultiEdit": "Editing",
+	"Write":     "Writing",
+	"LS":        "Listing",
+	"Glob":      "Finding",
+	"Grep":      "Searching for",
+	"WebFetch":  "Fetching",
+	"WebSearch": "Searching the web for",
+	"Task":      "Running task",
+}
+
+// FormatToolActivity formats what a tool is doing for the compact mode status message
+// target is the command, path, pattern or URL the tool works on, truncated to its first line.
+func FormatToolActivity(toolName, target string) string {
+	const maxTargetLength = 80
+
+	if i := strings.Index(target, "\n"); i >= 0 {
+		target = target[:i] + "…"
+	}
+	if len(target) > maxTargetLength {
+		target = strings.ToValidUTF8(target[:maxTargetLength], "") + "…"
+	}
+
+	emoji := tools.GetToolInfo(toolName).Emoji
+	verb, ok := toolActivityVerbs[toolName]
+	if !ok || target == "" {
+		return fmt.Sprintf("%s Using `%s`", emoji, toolName)
+	}
+	return fmt.Sprintf("%s %s `%s`", emoji, verb, target)
+}
+
+// FormatSessionStatusMessage formats the compact mode status message of a session
+func FormatSessionStatusMessage(status SessionStatus) string {
+	var lines []string
+
+	elapsed := FormatDuration(status.Elapsed.Round(time.Second))
+	if status.Outcome == "" {
+		lines = append(lines, fmt.Sprintf("⏳ Working… (%s)", elapsed))
+		if status.Activity != "" {
+			lines = append(lines, status.Activity)
+		}
+	} else {
+		lines = append(lines, fmt.Sprintf("%s in %s", status.Outcome, elapsed))
+	}
+
+	if status.TodosTotal > 0 {
+		todos := fmt.Sprintf("📋 Todos: %d/%d", status.TodosDone, status.TodosTotal)
+		if status.Todo != "" && status.Outcome == "" {
+			todos += " — " + status.Todo
+		}
+		lines = append(lines, todos)
+	}
+
+	if status.ToolUses == 1 {
+		lines = append(lines, "🔧 1 tool use")
+	} else {
+		lines = append(lines, fmt.Sprintf("🔧 %d tool uses", status.ToolUses))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // FormatErrorMessage formats the error completion message
 func FormatErrorMessage(sessionID string) string {
 	return fmt.Sprintf("❌ Session ended with error\n"+
 		"Session ID: `%s`", sessionID)
 }
 
+// FormatSessionInterruptedMessage formats the message posted when a session is stopped from Slack.
+// Turn statistics are omitted when Claude Code did not report a result (turns == 0).
+func FormatSessionInterruptedMessage(sessionID, stoppedBy string, duration time.Duration, turns int, cost float64) string {
+	text := "⏹️ Session interrupted\n"
+	if stoppedBy != "" {
+		text += fmt.Sprintf("Stopped by: <@%s>\n", stoppedBy)
+	}
+	text += fmt.Sprintf("Session ID: `%s`", sessionID)
+
+	if turns > 0 {
+		text += fmt.Sprintf("\n"+
+			"Duration: %s\n"+
+			"Turns: %d\n"+
+			"Cost: $%.6f USD",
+			FormatDuration(duration),
+			turns,
+			cost)
+	}
+
+	return text + "\n\nReply in this thread to resume the session."
+}
+
+// FormatQueuedMessagesDeliveredMessage formats the message posted when messages queued during a turn are sent to Claude
+func FormatQueuedMessagesDeliveredMessage(count int, previousSessionID string) string {
+	text := "📨 Sending the message received during the last turn"
+	if count > 1 {
+		text = fmt.Sprintf("📨 Sending %d messages received during the last turn as one prompt", count)
+	}
+	if previousSessionID != "" {
+		text += fmt.Sprintf("\nPrevious session: `%s`", previousSessionID)
+	}
+	return text
+}
+
+// maxGitChangeFiles is the number of files listed in a git change summary
+const maxGitChangeFiles = 15
+
+// FormatGitChangeSummary formats the changes a session made in its git repository
+// Changed files are listed with their line counts, followed by files that are not tracked yet.
+func FormatGitChangeSummary(summary *gitchanges.Summary) string {
+	var b strings.Builder
+	if summary.Branch != "" {
+		b.WriteString(fmt.Sprintf("🌿 Branch: `%s`\n", summary.Branch))
+	}
+	if len(summary.Files) == 0 {
+		b.WriteString("No changes in the repository")
+		return b.String()
+	}
+
+	files := "files"
+	if len(summary.Files) == 1 {
+		files = "file"
+	}
+	b.WriteString(fmt.Sprintf("📝 %d %s changed (+%d -%d)", len(summary.Files), files, summary.Added(), summary.Deleted()))
+
+	var tracked, untracked []gitchanges.FileChange
+	for _, f := range summary.Files {
+		if f.Untracked {
+			untracked = append(untracked, f)
+		} else {
+			tracked = append(tracked, f)
+		}
+	}
+
+	listed := 0
+	writeFiles := func(files []gitchanges.FileChange) {
+		for _, f := range files {
+			if listed == maxGitChangeFiles {
+				return
+			}
+			listed++
+			if f.Binary {
+				b.WriteString(fmt.Sprintf("\n• `%s` (binary)", f.Path))
+			} else {
+				b.WriteString(fmt.Sprintf("\n• `%s` +%d -%d", f.Path, f.Added, f.Deleted))
+			}
+		}
+	}
+	writeFiles(tracked)
+	if len(untracked) > 0 && listed < maxGitChangeFiles {
+		b.WriteString("\nUntracked files:")
+		writeFiles(untracked)
+	}
+	if rest := len(summary.Files) - listed; rest > 0 {
+		b.WriteString(fmt.Sprintf("\n…and %d more", rest))
+	}
+	return b.String()
+}
+
+// FormatPullRequestMessage formats the message posted when the changes of a thread are pushed for a pull request
+func FormatPullRequestMessage(url, branch string, created, committed bool, userID string) string {
+	var text string
+	switch {
+	case created:
+		text = fmt.Sprintf("🚀 Pull request opened: %s", url)
+	case committed:
+		text = fmt.Sprintf("🚀 New changes pushed to %s", url)
+	default:
+		text = fmt.Sprintf("🚀 Branch pushed to %s", url)
+	}
+	text += fmt.Sprintf("\nBranch: `%s`", branch)
+	if userID != "" {
+		text += fmt.Sprintf("\nRequested by <@%s>", userID)
+	}
+	return text
+}
+
+// FormatSessionResumedMessage formats the message posted when a thread is resumed with a slash command
+func FormatSessionResumedMessage(userID, prompt string) string {
+	return fmt.Sprintf("▶️ <@%s> resumed the session\nPrompt:\n%s", userID, prompt)
+}
+
+// FormatThreadArchivedMessage formats the message posted when a thread is archived
+// The worktree of the thread is mentioned so that its removal can be offered.
+func FormatThreadArchivedMessage(worktreePath, branch string) string {
+	text := "🗄️ Thread archived"
+	if worktreePath != "" {
+		text += fmt.Sprintf("\nWorktree `%s` on branch `%s` is kept until it is removed", worktreePath, branch)
+	}
+	return text
+}
+
+// FormatWorktreeRemovedMessage formats the message shown once the worktree of an archived thread is removed
+func FormatWorktreeRemovedMessage(worktreePath, branch string) string {
+	return fmt.Sprintf("🗄️ Thread archived\n🧹 Worktree `%s` removed, branch `%s` is kept", worktreePath, branch)
+}
+
+// FormatPolicyDecisionMessage formats the compact line posted when the approval policy decides a request
+func FormatPolicyDecisionMessage(toolName, ruleName string, allowed bool) string {
+	if allowed {
+		return fmt.Sprintf("✅ `%s` auto-approved by rule `%s`", toolName, ruleName)
+	}
+	return fmt.Sprintf("🚫 `%s` auto-denied by rule `%s`", toolName, ruleName)
+}
+
+// FormatSessionGrantMessage formats the compact line posted when a request is approved by an approval granted for the session
+func FormatSessionGrantMessage(toolName, grantDescription string) string {
+	return fmt.Sprintf("✅ `%s` auto-approved for this session (%s)", toolName, grantDescription)
+}
+
+// FormatApprovalReminderMessage formats the reminder posted while an approval request is waiting
+func FormatApprovalReminderMessage(userID, toolName string, elapsed, remaining time.Duration) string {
+	text := fmt.Sprintf("⏰ Approval for `%s` is still waiting (%s elapsed, expires in %s)",
+		toolName, FormatDuration(elapsed.Round(time.Second)), FormatDuration(remaining.Round(time.Second)))
+	if userID != "" {
+		text = fmt.Sprintf("<@%s> %s", userID, text)
+	}
+	return text
+}
+
+// FormatApprovalEscalationMessage formats the message escalating a waiting approval request to backup approvers
+func FormatApprovalEscalationMessage(userIDs []string, userGroupID, toolName string, elapsed time.Duration) string {
+	var mentions []string
+	for _, userID := range userIDs {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", userID))
+	}
+	if userGroupID != "" {
+		mentions = append(mentions, fmt.Sprintf("<!subteam^%s>", userGroupID))
+	}
+
+	text := fmt.Sprintf("🚨 Approval for `%s` has been waiting for %s. Any of you can approve or deny it above.",
+		toolName, FormatDuration(elapsed.Round(time.Second)))
+	if len(mentions) > 0 {
+		text = strings.Join(mentions, " ") + " " + text
+	}
+	return text
+}
+
 // FormatDuration converts duration to human-readable string
 // Examples:
 //   - 5s -> "5s"
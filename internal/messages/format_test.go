@@ -1,18 +1,24 @@
 package messages
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/diff"
+	"github.com/yuya-takeyama/cc-slack/internal/gitchanges"
 )
 
 func TestFormatSessionStartMessage(t *testing.T) {
 	tests := []struct {
-		name      string
-		sessionID string
-		cwd       string
-		model     string
-		want      string
+		name             string
+		sessionID        string
+		cwd              string
+		model            string
+		permissionMode   string
+		extendedThinking bool
+		want             string
 	}{
 		{
 			name:      "standard session",
@@ -34,11 +40,36 @@ func TestFormatSessionStartMessage(t *testing.T) {
 				"Working directory: `/Users/name/My Documents/project`\n" +
 				"Model: `claude-3.5-sonnet`",
 		},
+		{
+			name:           "default permission mode",
+			sessionID:      "session-789",
+			cwd:            "/home/user/project",
+			model:          "claude-opus-4",
+			permissionMode: "default",
+			want: "✨ Claude Code session started\n" +
+				"Session ID: `session-789`\n" +
+				"Working directory: `/home/user/project`\n" +
+				"Model: `claude-opus-4`",
+		},
+		{
+			name:             "plan mode with extended thinking",
+			sessionID:        "session-789",
+			cwd:              "/home/user/project",
+			model:            "claude-opus-4",
+			permissionMode:   "plan",
+			extendedThinking: true,
+			want: "✨ Claude Code session started\n" +
+				"Session ID: `session-789`\n" +
+				"Working directory: `/home/user/project`\n" +
+				"Model: `claude-opus-4`\n" +
+				"Permission mode: `plan`\n" +
+				"Extended thinking: on",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := FormatSessionStartMessage(tt.sessionID, tt.cwd, tt.model)
+			got := FormatSessionStartMessage(tt.sessionID, tt.cwd, tt.model, tt.permissionMode, tt.extendedThinking)
 			if got != tt.want {
 				t.Errorf("FormatSessionStartMessage() = %v, want %v", got, tt.want)
 			}
@@ -312,6 +343,159 @@ func TestFormatEditToolMessage(t *testing.T) {
 	}
 }
 
+func TestFormatFileChangeToolMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		diff diff.Result
+		want string
+	}{
+		{
+			name: "inline diff",
+			diff: diff.Result{Text: "@@ -1 +1 @@\n-a\n+b\n", Added: 1, Removed: 1},
+			want: "Editing `main.go` (+1 -1)\n```\n@@ -1 +1 @@\n-a\n+b\n```",
+		},
+		{
+			name: "diff with backticks",
+			diff: diff.Result{Text: "@@ -0,0 +1 @@\n+```go\n", Added: 1},
+			want: "Editing `main.go` (+1 -0)\n```\n@@ -0,0 +1 @@\n+\\`\\`\\`go\n```",
+		},
+		{
+			name: "large diff",
+			diff: diff.Result{Text: strings.Repeat("+line\n", 500), Added: 500},
+			want: "Editing `main.go` (+500 -0)\nThe full diff is attached below.",
+		},
+		{
+			name: "no changes",
+			diff: diff.Result{},
+			want: "Editing `main.go` (+0 -0)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatFileChangeToolMessage("Editing `main.go`", tt.diff)
+			if got != tt.want {
+				t.Errorf("FormatFileChangeToolMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatToolResultMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		isError       bool
+		want          string
+		wantTruncated bool
+	}{
+		{
+			name:   "short output",
+			output: "ok\tgithub.com/example/app\n",
+			want:   "```\nok\tgithub.com/example/app\n```",
+		},
+		{
+			name:    "error",
+			output:  "exit status 1",
+			isError: true,
+			want:    ":x: *Failed*\n```\nexit status 1\n```",
+		},
+		{
+			name:          "many lines",
+			output:        strings.Repeat("line\n", 15),
+			want:          "```\n" + strings.TrimSuffix(strings.Repeat("line\n", 10), "\n") + "\n```\n_… 5 more lines_",
+			wantTruncated: true,
+		},
+		{
+			name:          "long line",
+			output:        strings.Repeat("x", 2000),
+			want:          "```\n" + strings.Repeat("x", 1500) + "\n```\n_… truncated_",
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := FormatToolResultMessage(tt.output, tt.isError)
+			if got != tt.want {
+				t.Errorf("FormatToolResultMessage() = %q, want %q", got, tt.want)
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("FormatToolResultMessage() truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestFormatToolActivity(t *testing.T) {
+	tests := []struct {
+		name     string
+		toolName string
+		target   string
+		want     string
+	}{
+		{name: "bash", toolName: "Bash", target: "go test ./...", want: "💻 Running `go test ./...`"},
+		{name: "multiline command", toolName: "Bash", target: "cat <<EOF\nhello\nEOF", want: "💻 Running `cat <<EOF…`"},
+		{name: "unknown tool", toolName: "mcp__github__create_issue", target: "", want: "🔧 Using `mcp__github__create_issue`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatToolActivity(tt.toolName, tt.target); got != tt.want {
+				t.Errorf("FormatToolActivity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSessionStatusMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		status SessionStatus
+		want   string
+	}{
+		{
+			name: "running",
+			status: SessionStatus{
+				Activity:   "Running `go test ./...`",
+				ToolUses:   3,
+				TodosDone:  1,
+				TodosTotal: 4,
+				Todo:       "Add tests",
+				Elapsed:    80 * time.Second,
+			},
+			want: "⏳ Working… (1m20s)\nRunning `go test ./...`\n📋 Todos: 1/4 — Add tests\n🔧 3 tool uses",
+		},
+		{
+			name:   "just started",
+			status: SessionStatus{Elapsed: 2 * time.Second},
+			want:   "⏳ Working… (2s)\n🔧 0 tool uses",
+		},
+		{
+			name: "finished",
+			status: SessionStatus{
+				Activity:   "Running `go test ./...`",
+				ToolUses:   1,
+				TodosDone:  4,
+				TodosTotal: 4,
+				Todo:       "Add tests",
+				Elapsed:    3 * time.Minute,
+				Outcome:    "✅ Finished",
+			},
+			want: "✅ Finished in 3m0s\n📋 Todos: 4/4\n🔧 1 tool use",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatSessionStatusMessage(tt.status)
+			if got != tt.want {
+				t.Errorf("FormatSessionStatusMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatWriteToolMessage(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -555,6 +739,302 @@ func TestFormatErrorMessage(t *testing.T) {
 	}
 }
 
+func TestFormatSessionInterruptedMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		sessionID string
+		stoppedBy string
+		duration  time.Duration
+		turns     int
+		cost      float64
+		want      string
+	}{
+		{
+			name:      "with result statistics",
+			sessionID: "session-123",
+			stoppedBy: "U123",
+			duration:  65 * time.Second,
+			turns:     3,
+			cost:      0.0123,
+			want:      "⏹️ Session interrupted\nStopped by: <@U123>\nSession ID: `session-123`\nDuration: 1m5s\nTurns: 3\nCost: $0.012300 USD\n\nReply in this thread to resume the session.",
+		},
+		{
+			name:      "without result statistics",
+			sessionID: "session-123",
+			stoppedBy: "U123",
+			want:      "⏹️ Session interrupted\nStopped by: <@U123>\nSession ID: `session-123`\n\nReply in this thread to resume the session.",
+		},
+		{
+			name:      "unknown user",
+			sessionID: "session-123",
+			want:      "⏹️ Session interrupted\nSession ID: `session-123`\n\nReply in this thread to resume the session.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatSessionInterruptedMessage(tt.sessionID, tt.stoppedBy, tt.duration, tt.turns, tt.cost)
+			if got != tt.want {
+				t.Errorf("FormatSessionInterruptedMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatPolicyDecisionMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		toolName string
+		ruleName string
+		allowed  bool
+		want     string
+	}{
+		{
+			name:     "allowed",
+			toolName: "Bash",
+			ruleName: "git-read",
+			allowed:  true,
+			want:     "✅ `Bash` auto-approved by rule `git-read`",
+		},
+		{
+			name:     "denied",
+			toolName: "Write",
+			ruleName: "protect-env",
+			allowed:  false,
+			want:     "🚫 `Write` auto-denied by rule `protect-env`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatPolicyDecisionMessage(tt.toolName, tt.ruleName, tt.allowed)
+			if got != tt.want {
+				t.Errorf("FormatPolicyDecisionMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSessionGrantMessage(t *testing.T) {
+	got := FormatSessionGrantMessage("Bash", "`go test *`")
+	want := "✅ `Bash` auto-approved for this session (`go test *`)"
+	if got != want {
+		t.Errorf("FormatSessionGrantMessage() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatApprovalReminderMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		elapsed   time.Duration
+		remaining time.Duration
+		want      string
+	}{
+		{
+			name:      "with initiator",
+			userID:    "U123",
+			elapsed:   2*time.Minute + 300*time.Millisecond,
+			remaining: 3 * time.Minute,
+			want:      "<@U123> ⏰ Approval for `Bash` is still waiting (2m0s elapsed, expires in 3m0s)",
+		},
+		{
+			name:      "without initiator",
+			elapsed:   90 * time.Second,
+			remaining: 30 * time.Second,
+			want:      "⏰ Approval for `Bash` is still waiting (1m30s elapsed, expires in 30s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatApprovalReminderMessage(tt.userID, "Bash", tt.elapsed, tt.remaining)
+			if got != tt.want {
+				t.Errorf("FormatApprovalReminderMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatApprovalEscalationMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		userIDs     []string
+		userGroupID string
+		want        string
+	}{
+		{
+			name:        "users and group",
+			userIDs:     []string{"U1", "U2"},
+			userGroupID: "S1",
+			want:        "<@U1> <@U2> <!subteam^S1> 🚨 Approval for `Write` has been waiting for 5m0s. Any of you can approve or deny it above.",
+		},
+		{
+			name:        "group only",
+			userGroupID: "S1",
+			want:        "<!subteam^S1> 🚨 Approval for `Write` has been waiting for 5m0s. Any of you can approve or deny it above.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatApprovalEscalationMessage(tt.userIDs, tt.userGroupID, "Write", 5*time.Minute)
+			if got != tt.want {
+				t.Errorf("FormatApprovalEscalationMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatQueuedMessagesDeliveredMessage(t *testing.T) {
+	tests := []struct {
+		name              string
+		count             int
+		previousSessionID string
+		want              string
+	}{
+		{
+			name:              "single message",
+			count:             1,
+			previousSessionID: "abc123",
+			want:              "📨 Sending the message received during the last turn\nPrevious session: `abc123`",
+		},
+		{
+			name:  "several messages",
+			count: 3,
+			want:  "📨 Sending 3 messages received during the last turn as one prompt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatQueuedMessagesDeliveredMessage(tt.count, tt.previousSessionID)
+			if got != tt.want {
+				t.Errorf("FormatQueuedMessagesDeliveredMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatGitChangeSummary(t *testing.T) {
+	manyFiles := make([]gitchanges.FileChange, 17)
+	for i := range manyFiles {
+		manyFiles[i] = gitchanges.FileChange{Path: fmt.Sprintf("f%d.go", i), Added: 1}
+	}
+
+	tests := []struct {
+		name    string
+		summary *gitchanges.Summary
+		want    string
+	}{
+		{
+			name:    "no changes",
+			summary: &gitchanges.Summary{Branch: "main"},
+			want:    "🌿 Branch: `main`\nNo changes in the repository",
+		},
+		{
+			name: "changed and untracked files",
+			summary: &gitchanges.Summary{
+				Branch: "cc-slack/C123-1",
+				Files: []gitchanges.FileChange{
+					{Path: "new.go", Added: 3, Untracked: true},
+					{Path: "main.go", Added: 10, Deleted: 2},
+					{Path: "logo.png", Binary: true},
+				},
+			},
+			want: "🌿 Branch: `cc-slack/C123-1`\n" +
+				"📝 3 files changed (+13 -2)\n" +
+				"• `main.go` +10 -2\n" +
+				"• `logo.png` (binary)\n" +
+				"Untracked files:\n" +
+				"• `new.go` +3 -0",
+		},
+		{
+			name:    "too many files",
+			summary: &gitchanges.Summary{Files: manyFiles},
+			want: "📝 17 files changed (+17 -0)\n" +
+				"• `f0.go` +1 -0\n• `f1.go` +1 -0\n• `f2.go` +1 -0\n• `f3.go` +1 -0\n• `f4.go` +1 -0\n" +
+				"• `f5.go` +1 -0\n• `f6.go` +1 -0\n• `f7.go` +1 -0\n• `f8.go` +1 -0\n• `f9.go` +1 -0\n" +
+				"• `f10.go` +1 -0\n• `f11.go` +1 -0\n• `f12.go` +1 -0\n• `f13.go` +1 -0\n• `f14.go` +1 -0\n" +
+				"…and 2 more",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatGitChangeSummary(tt.summary)
+			if got != tt.want {
+				t.Errorf("FormatGitChangeSummary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatPullRequestMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		created   bool
+		committed bool
+		userID    string
+		want      string
+	}{
+		{
+			name:      "opened",
+			created:   true,
+			committed: true,
+			userID:    "U123",
+			want:      "🚀 Pull request opened: https://github.com/owner/repo/pull/1\nBranch: `cc-slack/C123-1`\nRequested by <@U123>",
+		},
+		{
+			name:      "pushed to existing pull request",
+			committed: true,
+			want:      "🚀 New changes pushed to https://github.com/owner/repo/pull/1\nBranch: `cc-slack/C123-1`",
+		},
+		{
+			name: "nothing new to commit",
+			want: "🚀 Branch pushed to https://github.com/owner/repo/pull/1\nBranch: `cc-slack/C123-1`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatPullRequestMessage("https://github.com/owner/repo/pull/1", "cc-slack/C123-1", tt.created, tt.committed, tt.userID)
+			if got != tt.want {
+				t.Errorf("FormatPullRequestMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatThreadArchivedMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		worktreePath string
+		branch       string
+		want         string
+	}{
+		{
+			name: "without worktree",
+			want: "🗄️ Thread archived",
+		},
+		{
+			name:         "with worktree",
+			worktreePath: "/repo-worktrees/C123-1",
+			branch:       "cc-slack/C123-1",
+			want:         "🗄️ Thread archived\nWorktree `/repo-worktrees/C123-1` on branch `cc-slack/C123-1` is kept until it is removed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatThreadArchivedMessage(tt.worktreePath, tt.branch)
+			if got != tt.want {
+				t.Errorf("FormatThreadArchivedMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatDuration(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -109,6 +112,9 @@ type ResultMessage struct {
 	} `json:"usage"`
 }
 
+// ExtendedThinkingTokens is the thinking budget of sessions with extended thinking
+const ExtendedThinkingTokens = 31999
+
 // Options for creating a new Claude process
 type Options struct {
 	WorkDir              string
@@ -119,6 +125,17 @@ type Options struct {
 	ExecutablePath  string // Path to Claude executable (default: claude)
 	InitialPrompt   string // Initial prompt to send after process starts
 	Handlers        MessageHandlers
+
+	// Agent profile of the working directory; zero values leave Claude Code's defaults
+	Model              string
+	AllowedTools       []string
+	DisallowedTools    []string
+	AppendSystemPrompt string
+	PermissionMode     string
+	MaxTurns           int
+	MaxThinkingTokens  int                    // Extended thinking budget, 0 leaves thinking off
+	MCPServers         map[string]interface{} // Additional MCP servers by name, in Claude Code MCP config format
+	ExtraArgs          []string               // Passed as is, such as claude.default_options
 }
 
 // NewClaudeProcess creates and starts a new Claude Code process
@@ -161,7 +178,7 @@ func NewClaudeProcess(ctx context.Context, opts Options) (*ClaudeProcess, error)
 		Logger()
 
 	// Create MCP config file
-	configPath, err := createMCPConfig(opts.MCPBaseURL)
+	configPath, err := createMCPConfig(opts.MCPBaseURL, opts.MCPServers)
 	if err != nil {
 		logFile.Close()
 		return nil, fmt.Errorf("failed to create MCP config: %w", err)
@@ -175,18 +192,12 @@ func NewClaudeProcess(ctx context.Context, opts Options) (*ClaudeProcess, error)
 		Msg("Created MCP configuration")
 
 	// Prepare command
-	args := []string{
-		"--mcp-config", configPath,
-		"--permission-prompt-tool", opts.PermissionPromptTool,
-		"--print",
-		"--output-format", "stream-json",
-		"--input-format", "stream-json",
-		"--verbose",
-	}
+	args := buildArgs(opts, configPath)
+	logger.Info().
+		Strs("args", args).
+		Msg("Prepared Claude Code arguments")
 
-	// Add resume option if specified
 	if opts.ResumeSessionID != "" {
-		args = append(args, "--resume", opts.ResumeSessionID)
 		logger.Info().
 			Str("resume_session_id", opts.ResumeSessionID).
 			Msg("Resuming previous session")
@@ -194,6 +205,9 @@ func NewClaudeProcess(ctx context.Context, opts Options) (*ClaudeProcess, error)
 
 	cmd := exec.CommandContext(ctx, opts.ExecutablePath, args...)
 	cmd.Dir = opts.WorkDir // Set working directory
+	if opts.MaxThinkingTokens > 0 {
+		cmd.Env = append(os.Environ(), fmt.Sprintf("MAX_THINKING_TOKENS=%d", opts.MaxThinkingTokens))
+	}
 
 	// Set up pipes
 	stdin, err := cmd.StdinPipe()
@@ -298,6 +312,40 @@ func (p *ClaudeProcess) SendMessage(message string) error {
 	return nil
 }
 
+// Interrupt asks Claude Code to stop the current turn.
+// Claude Code responds with a result message once the turn has been aborted.
+func (p *ClaudeProcess) Interrupt() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	input := map[string]interface{}{
+		"type":       "control_request",
+		"request_id": fmt.Sprintf("interrupt_%d", time.Now().UnixNano()),
+		"request": map[string]interface{}{
+			"subtype": "interrupt",
+		},
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal interrupt request: %w", err)
+	}
+
+	// Log outgoing control request
+	p.logger.Info().
+		Str("type", "control_request").
+		Str("direction", "sent").
+		RawJSON("raw", data).
+		Msg("Sent interrupt request to Claude")
+
+	_, err = p.stdin.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write to stdin: %w", err)
+	}
+
+	return nil
+}
+
 // readStdout reads and processes stdout messages
 func (p *ClaudeProcess) readStdout() {
 	defer p.wg.Done()
@@ -310,7 +358,8 @@ func (p *ClaudeProcess) readStdout() {
 		}
 	}
 
-	if err := p.stdout.Err(); err != nil && p.handlers.OnError != nil {
+	// Close stops reading once the process has exited, which is not an error
+	if err := p.stdout.Err(); err != nil && !errors.Is(err, os.ErrClosed) && p.handlers.OnError != nil {
 		p.handlers.OnError(fmt.Errorf("stdout scanner error: %w", err))
 	}
 }
@@ -393,6 +442,13 @@ func (p *ClaudeProcess) processJSONLine(line []byte) error {
 			return p.handlers.OnResult(msg)
 		}
 
+	case "control_response":
+		// Acknowledgement of a control request such as interrupt
+		p.logger.Info().
+			Str("type", "control_response").
+			Bytes("raw", line).
+			Msg("Received control response from Claude")
+
 	default:
 		// Unknown message type, log it
 		p.logger.Warn().
@@ -427,14 +483,66 @@ func (p *ClaudeProcess) Close() error {
 	return err
 }
 
+// Kill forcibly terminates the Claude process and cleans up resources.
+// Use it when the process does not respond to Interrupt.
+func (p *ClaudeProcess) Kill() error {
+	if p.cmd.Process != nil {
+		if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return fmt.Errorf("failed to kill claude process: %w", err)
+		}
+	}
+
+	// Wait reports the kill signal as an exit error, which is expected here
+	_ = p.Close()
+	return nil
+}
+
 // SessionID returns the session ID assigned by Claude Code
 func (p *ClaudeProcess) SessionID() string {
 	return p.sessionID
 }
 
+// buildArgs builds the Claude Code command line arguments
+func buildArgs(opts Options, configPath string) []string {
+	args := []string{
+		"--mcp-config", configPath,
+		"--permission-prompt-tool", opts.PermissionPromptTool,
+		"--print",
+		"--output-format", "stream-json",
+		"--input-format", "stream-json",
+		"--verbose",
+	}
+
+	// Add resume option if specified
+	if opts.ResumeSessionID != "" {
+		args = append(args, "--resume", opts.ResumeSessionID)
+	}
+
+	if opts.Model != "" {
+		args = append(args, "--model", opts.Model)
+	}
+	if len(opts.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(opts.AllowedTools, ","))
+	}
+	if len(opts.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(opts.DisallowedTools, ","))
+	}
+	if opts.AppendSystemPrompt != "" {
+		args = append(args, "--append-system-prompt", opts.AppendSystemPrompt)
+	}
+	if opts.PermissionMode != "" {
+		args = append(args, "--permission-mode", opts.PermissionMode)
+	}
+	if opts.MaxTurns > 0 {
+		args = append(args, "--max-turns", strconv.Itoa(opts.MaxTurns))
+	}
+
+	return append(args, opts.ExtraArgs...)
+}
+
 // createMCPConfig creates a temporary MCP configuration file
-func createMCPConfig(baseURL string) (string, error) {
-	config := buildMCPConfig(baseURL)
+func createMCPConfig(baseURL string, servers map[string]interface{}) (string, error) {
+	config := buildMCPConfig(baseURL, servers)
 
 	// Create temp directory if needed
 	tmpDir := filepath.Join(os.TempDir(), "cc-slack")
@@ -464,13 +572,18 @@ func generateLogFileName(t time.Time) string {
 }
 
 // buildMCPConfig builds the MCP configuration object
-func buildMCPConfig(baseURL string) map[string]interface{} {
+// Additional servers are merged in, but never replace the cc-slack server answering permission prompts.
+func buildMCPConfig(baseURL string, servers map[string]interface{}) map[string]interface{} {
+	mcpServers := make(map[string]interface{}, len(servers)+1)
+	for name, server := range servers {
+		mcpServers[name] = server
+	}
+	mcpServers["cc-slack"] = map[string]interface{}{
+		"type": "http",
+		"url":  fmt.Sprintf("%s/mcp", baseURL),
+	}
+
 	return map[string]interface{}{
-		"mcpServers": map[string]interface{}{
-			"cc-slack": map[string]interface{}{
-				"type": "http",
-				"url":  fmt.Sprintf("%s/mcp", baseURL),
-			},
-		},
+		"mcpServers": mcpServers,
 	}
 }
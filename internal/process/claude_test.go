@@ -1,8 +1,13 @@
 package process
 
 import (
+	"bytes"
+	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 func TestGenerateLogFileName(t *testing.T) {
@@ -59,7 +64,7 @@ func TestBuildMCPConfig(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := buildMCPConfig(tt.baseURL)
+			config := buildMCPConfig(tt.baseURL, nil)
 
 			// Check structure
 			mcpServers, ok := config["mcpServers"].(map[string]interface{})
@@ -85,3 +90,120 @@ func TestBuildMCPConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildMCPConfigMergesServers(t *testing.T) {
+	config := buildMCPConfig("http://localhost:8080", map[string]interface{}{
+		"github":   map[string]interface{}{"command": "github-mcp-server"},
+		"cc-slack": map[string]interface{}{"command": "impostor"},
+	})
+
+	mcpServers := config["mcpServers"].(map[string]interface{})
+	if len(mcpServers) != 2 {
+		t.Errorf("mcpServers has %d servers, want 2", len(mcpServers))
+	}
+	if _, ok := mcpServers["github"]; !ok {
+		t.Error("github server should be merged")
+	}
+	ccSlack := mcpServers["cc-slack"].(map[string]interface{})
+	if ccSlack["url"] != "http://localhost:8080/mcp" {
+		t.Errorf("cc-slack url = %v, want the cc-slack server", ccSlack["url"])
+	}
+}
+
+func TestBuildArgs(t *testing.T) {
+	base := []string{
+		"--mcp-config", "/tmp/config.json",
+		"--permission-prompt-tool", "mcp__cc-slack__approval_prompt",
+		"--print",
+		"--output-format", "stream-json",
+		"--input-format", "stream-json",
+		"--verbose",
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: Options{PermissionPromptTool: "mcp__cc-slack__approval_prompt"},
+			want: base,
+		},
+		{
+			name: "agent profile",
+			opts: Options{
+				PermissionPromptTool: "mcp__cc-slack__approval_prompt",
+				ResumeSessionID:      "session-1",
+				Model:                "opus",
+				AllowedTools:         []string{"Read", "Bash(git status:*)"},
+				DisallowedTools:      []string{"WebFetch"},
+				AppendSystemPrompt:   "Answer in Japanese.",
+				PermissionMode:       "acceptEdits",
+				MaxTurns:             20,
+				ExtraArgs:            []string{"--debug"},
+			},
+			want: append(append([]string{}, base...),
+				"--resume", "session-1",
+				"--model", "opus",
+				"--allowedTools", "Read,Bash(git status:*)",
+				"--disallowedTools", "WebFetch",
+				"--append-system-prompt", "Answer in Japanese.",
+				"--permission-mode", "acceptEdits",
+				"--max-turns", "20",
+				"--debug",
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildArgs(tt.opts, "/tmp/config.json")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type bufferWriteCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferWriteCloser) Close() error { return nil }
+
+func TestInterrupt(t *testing.T) {
+	stdin := &bufferWriteCloser{}
+	p := &ClaudeProcess{
+		stdin:  stdin,
+		logger: zerolog.Nop(),
+	}
+
+	if err := p.Interrupt(); err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+
+	var req struct {
+		Type      string `json:"type"`
+		RequestID string `json:"request_id"`
+		Request   struct {
+			Subtype string `json:"subtype"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(stdin.Bytes(), &req); err != nil {
+		t.Fatalf("failed to parse control request: %v", err)
+	}
+
+	if req.Type != "control_request" {
+		t.Errorf("type = %q, want control_request", req.Type)
+	}
+	if req.RequestID == "" {
+		t.Error("request_id is empty")
+	}
+	if req.Request.Subtype != "interrupt" {
+		t.Errorf("request.subtype = %q, want interrupt", req.Request.Subtype)
+	}
+	if !bytes.HasSuffix(stdin.Bytes(), []byte("\n")) {
+		t.Error("control request is not newline terminated")
+	}
+}
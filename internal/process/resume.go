@@ -20,7 +20,7 @@ func NewResumeManager(queries *db.Queries) *ResumeManager {
 	}
 }
 
-// GetLatestSessionID returns the latest completed session ID for a given channel and thread
+// GetLatestSessionID returns the latest completed or interrupted session ID for a given channel and thread
 func (rm *ResumeManager) GetLatestSessionID(ctx context.Context, channelID, threadTS string) (string, error) {
 	// 1. Get thread_id from threads table
 	thread, err := rm.queries.GetThread(ctx, db.GetThreadParams{
@@ -34,7 +34,7 @@ func (rm *ResumeManager) GetLatestSessionID(ctx context.Context, channelID, thre
 		return "", fmt.Errorf("failed to get thread: %w", err)
 	}
 
-	// 2. Get latest completed session from sessions table
+	// 2. Get latest completed or interrupted session from sessions table
 	session, err := rm.queries.GetLatestSessionByThread(ctx, thread.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	"github.com/yuya-takeyama/cc-slack/internal/gitchanges"
+	"github.com/yuya-takeyama/cc-slack/internal/worktree"
+)
+
+// gitTimeout bounds the git commands summarizing the changes of a session
+const gitTimeout = 30 * time.Second
+
+// snapshotGitChanges records the state of a working directory before Claude starts working in it
+// It returns an empty tree when the directory is not a git repository or the snapshot fails.
+func snapshotGitChanges(ctx context.Context, dir string) string {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+
+	if !gitchanges.IsRepository(ctx, dir) {
+		return ""
+	}
+	tree, err := gitchanges.Snapshot(ctx, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to snapshot git changes: %v\n", err)
+		return ""
+	}
+	return tree
+}
+
+// summarizeGitChanges returns the changes made in a working directory since its snapshot, or nil without one
+func summarizeGitChanges(dir, baseTree string) *gitchanges.Summary {
+	if baseTree == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	summary, err := gitchanges.Summarize(ctx, dir, baseTree)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to summarize git changes: %v\n", err)
+		return nil
+	}
+	return summary
+}
+
+// GitPatch returns the changes between two snapshots of a thread's repository as a patch (for slack.SessionManager interface)
+func (m *Manager) GitPatch(channelID, threadTS, baseTree, tree string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	thread, err := m.queries.GetThread(ctx, db.GetThreadParams{
+		ChannelID: channelID,
+		ThreadTs:  threadTS,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("no session has run in this thread")
+		}
+		return "", fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	// A removed worktree shares its objects with the repository it was created from
+	dir := thread.WorkingDirectory
+	if thread.WorktreePath.Valid && worktree.Exists(thread.WorktreePath.String) {
+		dir = thread.WorktreePath.String
+	}
+
+	return gitchanges.Patch(ctx, dir, baseTree, tree)
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+)
+
+// refreshHome refreshes the App Home tab of the user who started a session after its state changed
+func (m *Manager) refreshHome(userID string) {
+	if m.slackHandler == nil || userID == "" {
+		return
+	}
+	m.slackHandler.RefreshHome(userID)
+}
+
+// ListSessionsByUser returns the active sessions started by a user, most recently active first (for slack.SessionManager interface)
+func (m *Manager) ListSessionsByUser(userID string) ([]*ccslack.Session, error) {
+	m.mu.RLock()
+	var sessions []*ccslack.Session
+	for _, session := range m.sessions {
+		if session.InitiatorUserID == userID {
+			sessions = append(sessions, slackSessionLocked(session))
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActive.After(sessions[j].LastActive)
+	})
+	return sessions, nil
+}
+
+// ListRecentSessionsByUser returns the finished sessions started by a user, most recently ended first (for slack.SessionManager interface)
+func (m *Manager) ListRecentSessionsByUser(userID string, limit int) ([]*ccslack.SessionSummary, error) {
+	rows, err := m.queries.ListRecentSessionsByUser(context.Background(), db.ListRecentSessionsByUserParams{
+		UserID: sql.NullString{String: userID, Valid: true},
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent sessions: %w", err)
+	}
+
+	sessions := make([]*ccslack.SessionSummary, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, &ccslack.SessionSummary{
+			SessionID: row.SessionID,
+			ChannelID: row.ChannelID,
+			ThreadTS:  row.ThreadTs,
+			WorkDir:   row.WorkingDirectory,
+			Status:    row.Status.String,
+			Cost:      row.TotalCostUsd.Float64,
+			EndedAt:   row.EndedAt.Time,
+		})
+	}
+	return sessions, nil
+}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/database"
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+)
+
+func TestListRecentSessionsByUser(t *testing.T) {
+	ctx := context.Background()
+	sqlDB, err := database.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+	if err := database.Migrate(sqlDB, "../../migrations"); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	queries := db.New(sqlDB)
+	thread, err := queries.CreateThread(ctx, db.CreateThreadParams{
+		ChannelID:        "C123",
+		ThreadTs:         "1",
+		WorkingDirectory: "/src/api",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sessions := []struct {
+		sessionID string
+		userID    string
+		ended     bool
+	}{
+		{"session-1", "U_ME", true},
+		{"session-2", "U_ME", true},
+		{"session-3", "U_OTHER", true},
+		{"session-4", "U_ME", false},
+	}
+	for i, s := range sessions {
+		if _, err := queries.CreateSessionWithInitialPrompt(ctx, db.CreateSessionWithInitialPromptParams{
+			ThreadID:  thread.ID,
+			SessionID: s.sessionID,
+			UserID:    sql.NullString{String: s.userID, Valid: true},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !s.ended {
+			continue
+		}
+		if err := queries.UpdateSessionOnComplete(ctx, db.UpdateSessionOnCompleteParams{
+			Status:       sql.NullString{String: "completed", Valid: true},
+			EndedAt:      sql.NullTime{Time: endedAt.Add(time.Duration(i) * time.Minute), Valid: true},
+			TotalCostUsd: sql.NullFloat64{Float64: 0.25, Valid: true},
+			SessionID:    s.sessionID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := &Manager{
+		sessions:        make(map[string]*Session),
+		threadToSession: make(map[string]string),
+		queries:         queries,
+		config:          &config.Config{},
+	}
+
+	recent, err := manager.ListRecentSessionsByUser("U_ME", 10)
+	if err != nil {
+		t.Fatalf("ListRecentSessionsByUser() error = %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("ListRecentSessionsByUser() returned %d sessions, want 2", len(recent))
+	}
+	if recent[0].SessionID != "session-2" || recent[1].SessionID != "session-1" {
+		t.Errorf("ListRecentSessionsByUser() = %s, %s, want session-2, session-1", recent[0].SessionID, recent[1].SessionID)
+	}
+	if got := recent[0]; got.ChannelID != "C123" || got.ThreadTS != "1" || got.WorkDir != "/src/api" || got.Status != "completed" || got.Cost != 0.25 {
+		t.Errorf("ListRecentSessionsByUser()[0] = %+v", got)
+	}
+	if !recent[0].EndedAt.Equal(endedAt.Add(time.Minute)) {
+		t.Errorf("EndedAt = %v, want %v", recent[0].EndedAt, endedAt.Add(time.Minute))
+	}
+
+	recent, err = manager.ListRecentSessionsByUser("U_ME", 1)
+	if err != nil {
+		t.Fatalf("ListRecentSessionsByUser() error = %v", err)
+	}
+	if len(recent) != 1 {
+		t.Errorf("ListRecentSessionsByUser() with limit 1 returned %d sessions", len(recent))
+	}
+
+	// Active sessions are listed from memory
+	manager.sessions["temp_1"] = &Session{ID: "temp_1", ChannelID: "C123", ThreadTS: "2", InitiatorUserID: "U_ME", LastActive: endedAt}
+	manager.sessions["temp_2"] = &Session{ID: "temp_2", ChannelID: "C123", ThreadTS: "3", InitiatorUserID: "U_OTHER", LastActive: endedAt}
+	active, err := manager.ListSessionsByUser("U_ME")
+	if err != nil {
+		t.Fatalf("ListSessionsByUser() error = %v", err)
+	}
+	if len(active) != 1 || active[0].SessionID != "temp_1" || active[0].UserID != "U_ME" {
+		t.Errorf("ListSessionsByUser() = %+v, want temp_1", active)
+	}
+}
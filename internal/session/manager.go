@@ -13,17 +13,24 @@ import (
 	"github.com/slack-go/slack"
 	"github.com/yuya-takeyama/cc-slack/internal/config"
 	"github.com/yuya-takeyama/cc-slack/internal/db"
+	"github.com/yuya-takeyama/cc-slack/internal/diff"
+	"github.com/yuya-takeyama/cc-slack/internal/forge"
 	"github.com/yuya-takeyama/cc-slack/internal/mcp"
 	"github.com/yuya-takeyama/cc-slack/internal/messages"
 	"github.com/yuya-takeyama/cc-slack/internal/process"
 	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
 )
 
+// interruptTimeout is how long StopSession waits for Claude Code to report the
+// interrupted turn before killing the process
+const interruptTimeout = 10 * time.Second
+
 // Manager manages Claude sessions with database persistence
 type Manager struct {
 	sessions         map[string]*Session
 	threadToSession  map[string]string
 	toolUseToSession map[string]string // tool_use_id -> session_id
+	toolUseToName    map[string]string // tool_use_id -> tool name, until the tool result arrives
 	lastActiveID     string
 	mu               sync.RWMutex
 
@@ -32,18 +39,49 @@ type Manager struct {
 	config       *config.Config
 	slackHandler *ccslack.Handler
 	mcpBaseURL   string
-	imagesDir    string // Directory for storing uploaded images
+	imagesDir    string      // Directory for storing uploaded images
+	forge        forge.Forge // Opens pull requests, nil when no forge is configured
+	grants       SessionGrants
+}
+
+// SessionGrants holds the approvals granted for the rest of a session
+type SessionGrants interface {
+	ClearSessionGrants(sessionID string)
+}
+
+// SetSessionGrants sets where approvals granted for the rest of a session are held,
+// so that they are dropped when the session ends
+func (m *Manager) SetSessionGrants(grants SessionGrants) {
+	m.grants = grants
+}
+
+// clearSessionGrants drops the approvals granted for the rest of an ended session
+func (m *Manager) clearSessionGrants(sessionID string) {
+	if m.grants != nil && sessionID != "" {
+		m.grants.ClearSessionGrants(sessionID)
+	}
 }
 
 // Session represents an active Claude session
 type Session struct {
-	ID              string
-	Process         *process.ClaudeProcess
-	ChannelID       string
-	ThreadTS        string
-	WorkDir         string
-	LastActive      time.Time
-	InitiatorUserID string
+	ID               string
+	DBID             int64 // sessions.id, used to attach transcript messages
+	Process          *process.ClaudeProcess
+	ChannelID        string
+	ThreadTS         string
+	WorkDir          string
+	Worktree         string // git worktree Claude runs in, empty when worktrees are disabled for WorkDir
+	GitBaseTree      string // Snapshot of the git repository taken before Claude started, empty outside of git repositories
+	LastActive       time.Time
+	InitiatorUserID  string
+	Interrupted      bool            // Set once StopSession has been requested
+	InterruptedBy    string          // Slack user who stopped the session
+	Status           *statusMessage  // Compact mode status message, nil when compact mode is disabled
+	TurnRunning      bool            // Claude is working on a prompt; set when a prompt is sent, cleared by the result
+	Queued           []queuedMessage // Slack messages received during the running turn
+	HandingOff       bool            // The turn has ended and its queued messages are starting the next session
+	PromptMessageTSs []string        // Slack messages whose prompts the current turn works on
+	PromptReaction   string          // Reaction currently shown on the prompt messages
 }
 
 // NewManager creates a new session manager
@@ -54,6 +92,7 @@ func NewManager(database *sql.DB, cfg *config.Config, slackHandler *ccslack.Hand
 		sessions:         make(map[string]*Session),
 		threadToSession:  make(map[string]string),
 		toolUseToSession: make(map[string]string),
+		toolUseToName:    make(map[string]string),
 		db:               database,
 		queries:          queries,
 		config:           cfg,
@@ -64,8 +103,35 @@ func NewManager(database *sql.DB, cfg *config.Config, slackHandler *ccslack.Hand
 }
 
 // CreateSession creates a new session or resumes an existing one
+// messageTS is the Slack message the initial prompt was posted in, or empty when the prompt did not
+// come from a message; the message is marked with reactions as the turn progresses.
 // Returns: resumed, previousSessionID, error
-func (m *Manager) CreateSession(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID string) (bool, string, error) {
+func (m *Manager) CreateSession(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID, messageTS string, opts ccslack.SessionOptions) (bool, string, error) {
+	m.mu.RLock()
+	current, exists := m.sessions[m.threadToSession[formatThreadKey(channelID, threadTS)]]
+	handingOff := exists && current.HandingOff
+	m.mu.RUnlock()
+	if handingOff {
+		return false, "", fmt.Errorf("the thread is starting its next turn with the messages sent during the last one")
+	}
+
+	var messageTSs []string
+	if messageTS != "" {
+		messageTSs = []string{messageTS}
+		m.replaceReactions(channelID, messageTSs, "", reactionReceived)
+	}
+	return m.createSession(ctx, channelID, threadTS, workDir, initialPrompt, userID, messageTSs, opts)
+}
+
+// createSession creates or resumes a session for prompt messages already marked as received
+// Options not chosen for a resumed session are inherited from the session it resumes.
+func (m *Manager) createSession(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID string, messageTSs []string, opts ccslack.SessionOptions) (resumed bool, previousSessionID string, err error) {
+	defer func() {
+		if err != nil {
+			m.replaceReactions(channelID, messageTSs, reactionReceived, reactionError)
+		}
+	}()
+
 	// Check if thread exists and get working directory
 	thread, err := m.queries.GetThread(ctx, db.GetThreadParams{
 		ChannelID: channelID,
@@ -97,27 +163,58 @@ func (m *Manager) CreateSession(ctx context.Context, channelID, threadTS, workDi
 		return false, "", fmt.Errorf("already has an active session for this thread")
 	}
 
-	resumed, err := m.createSessionInternal(ctx, channelID, threadTS, workDir, initialPrompt, userID, shouldResume, previousSessionID)
+	if shouldResume {
+		opts = m.inheritSessionOptions(ctx, previousSessionID, workDir, opts)
+	}
+
+	resumed, err = m.createSessionInternal(ctx, channelID, threadTS, workDir, initialPrompt, userID, shouldResume, previousSessionID, messageTSs, opts)
 	return resumed, previousSessionID, err
 }
 
 // createSessionInternal handles the actual session creation
-func (m *Manager) createSessionInternal(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID string, shouldResume bool, previousSessionID string) (bool, error) {
+func (m *Manager) createSessionInternal(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID string, shouldResume bool, previousSessionID string, messageTSs []string, sessionOpts ccslack.SessionOptions) (bool, error) {
 	// Get or create thread ID
 	threadID, err := m.getOrCreateThread(ctx, channelID, threadTS, workDir)
 	if err != nil {
 		return false, fmt.Errorf("failed to get or create thread: %w", err)
 	}
 
+	// Threads of working directories with worktrees enabled run in their own checkout
+	worktreePath, err := m.prepareWorktree(ctx, threadID, channelID, threadTS, workDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to prepare worktree: %w", err)
+	}
+	processDir := workDir
+	if worktreePath != "" {
+		processDir = worktreePath
+	}
+
+	if err := m.unarchiveThread(ctx, threadID); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to unarchive thread: %v\n", err)
+	}
+
+	opts := process.Options{
+		WorkDir:              processDir,
+		MCPBaseURL:           m.mcpBaseURL,
+		ExecutablePath:       m.config.Claude.Executable,
+		PermissionPromptTool: m.config.Claude.PermissionPromptTool,
+		InitialPrompt:        initialPrompt,
+	}
+	m.applyClaudeProfile(&opts, workDir)
+	applySessionOptions(&opts, sessionOpts)
+
 	// Generate temporary session ID
 	tempSessionID := fmt.Sprintf("temp_%d", time.Now().UnixNano())
 
 	// Create session in database (model will be updated from SystemMessage)
-	_, err = m.queries.CreateSessionWithInitialPrompt(ctx, db.CreateSessionWithInitialPromptParams{
-		ThreadID:      threadID,
-		SessionID:     tempSessionID,
-		Model:         sql.NullString{Valid: false}, // Will be set from SystemMessage
-		InitialPrompt: sql.NullString{String: initialPrompt, Valid: initialPrompt != ""},
+	dbSession, err := m.queries.CreateSessionWithInitialPrompt(ctx, db.CreateSessionWithInitialPromptParams{
+		ThreadID:         threadID,
+		SessionID:        tempSessionID,
+		Model:            sql.NullString{Valid: false}, // Will be set from SystemMessage
+		InitialPrompt:    sql.NullString{String: initialPrompt, Valid: initialPrompt != ""},
+		PermissionMode:   sql.NullString{String: opts.PermissionMode, Valid: opts.PermissionMode != ""},
+		ExtendedThinking: sql.NullBool{Bool: opts.MaxThinkingTokens > 0, Valid: true},
+		UserID:           sql.NullString{String: userID, Valid: userID != ""},
 	})
 
 	if err != nil {
@@ -130,23 +227,30 @@ func (m *Manager) createSessionInternal(ctx context.Context, channelID, threadTS
 		resumeSessionID = previousSessionID
 	}
 
-	claudeProcess, err := process.NewClaudeProcess(ctx, process.Options{
-		WorkDir:              workDir,
-		MCPBaseURL:           m.mcpBaseURL,
-		ExecutablePath:       m.config.Claude.Executable,
-		PermissionPromptTool: m.config.Claude.PermissionPromptTool,
-		InitialPrompt:        initialPrompt,
-		Handlers: process.MessageHandlers{
-			OnSystem:    m.createSystemHandler(channelID, threadTS, tempSessionID),
-			OnAssistant: m.createAssistantHandler(channelID, threadTS),
-			OnUser:      m.createUserHandler(channelID, threadTS),
-			OnResult:    m.createResultHandler(channelID, threadTS, tempSessionID),
-			OnError:     m.createErrorHandler(channelID, threadTS),
-		},
-		ResumeSessionID: resumeSessionID,
-	})
+	// Taken before the process starts so the summary only covers changes made during the session
+	gitBaseTree := snapshotGitChanges(ctx, processDir)
 
+	// In compact mode progress is shown in a single status message
+	var status *statusMessage
+	if m.config.Slack.CompactMode.Enabled && m.slackHandler != nil {
+		status = newStatusMessage(m.slackHandler, channelID, threadTS, m.config.Slack.CompactMode.UpdateInterval)
+	}
+
+	opts.Handlers = process.MessageHandlers{
+		OnSystem:    m.createSystemHandler(channelID, threadTS, tempSessionID, dbSession.ID, opts.MaxThinkingTokens > 0),
+		OnAssistant: m.createAssistantHandler(channelID, threadTS, dbSession.ID, status),
+		OnUser:      m.createUserHandler(channelID, threadTS, dbSession.ID, status),
+		OnResult:    m.createResultHandler(channelID, threadTS, tempSessionID, dbSession.ID, status),
+		OnError:     m.createErrorHandler(channelID, threadTS),
+	}
+	opts.ResumeSessionID = resumeSessionID
+
+	claudeProcess, err := process.NewClaudeProcess(ctx, opts)
 	if err != nil {
+		if status != nil {
+			status.finish(statusOutcomeFailed)
+		}
+
 		// Clean up database record on failure
 		_ = m.queries.UpdateSessionEndTime(ctx, db.UpdateSessionEndTimeParams{
 			Status:    sql.NullString{String: "failed", Valid: true},
@@ -157,26 +261,52 @@ func (m *Manager) createSessionInternal(ctx context.Context, channelID, threadTS
 
 	// Create session object
 	session := &Session{
-		ID:              tempSessionID,
-		Process:         claudeProcess,
-		ChannelID:       channelID,
-		ThreadTS:        threadTS,
-		WorkDir:         workDir,
-		LastActive:      time.Now(),
-		InitiatorUserID: userID,
+		ID:               tempSessionID,
+		DBID:             dbSession.ID,
+		Process:          claudeProcess,
+		ChannelID:        channelID,
+		ThreadTS:         threadTS,
+		WorkDir:          workDir,
+		Worktree:         worktreePath,
+		GitBaseTree:      gitBaseTree,
+		LastActive:       time.Now(),
+		InitiatorUserID:  userID,
+		Status:           status,
+		TurnRunning:      initialPrompt != "",
+		PromptMessageTSs: messageTSs,
+		PromptReaction:   reactionReceived,
 	}
 
-	// Store session
-	m.mu.Lock()
-	m.sessions[tempSessionID] = session
-	key := formatThreadKey(channelID, threadTS)
-	m.threadToSession[key] = tempSessionID
-	m.lastActiveID = tempSessionID
-	m.mu.Unlock()
+	m.registerSession(session)
+
+	if initialPrompt != "" {
+		m.recordMessages(promptTranscript(dbSession.ID, initialPrompt))
+	}
+
+	m.refreshHome(userID)
 
 	return shouldResume, nil
 }
 
+// registerSession stores a new session as the active session of its thread
+// A session handing its thread over to this one is dropped, and the messages sent to it while this
+// session was starting wait for this session's turn to end.
+func (m *Manager) registerSession(session *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := formatThreadKey(session.ChannelID, session.ThreadTS)
+	if previous, ok := m.sessions[m.threadToSession[key]]; ok && previous.HandingOff {
+		delete(m.sessions, previous.ID)
+		session.Queued = append(session.Queued, previous.Queued...)
+		previous.Queued = nil
+	}
+
+	m.sessions[session.ID] = session
+	m.threadToSession[key] = session.ID
+	m.lastActiveID = session.ID
+}
+
 // getOrCreateThread gets or creates a thread record
 func (m *Manager) getOrCreateThread(ctx context.Context, channelID, threadTS, workDir string) (int64, error) {
 	// Try to get existing thread
@@ -249,8 +379,10 @@ func (m *Manager) updateSessionID(channelID, threadTS string, newSessionID strin
 }
 
 // Message handlers
-func (m *Manager) createSystemHandler(channelID, threadTS, tempSessionID string) func(process.SystemMessage) error {
+func (m *Manager) createSystemHandler(channelID, threadTS, tempSessionID string, sessionRowID int64, extendedThinking bool) func(process.SystemMessage) error {
 	return func(msg process.SystemMessage) error {
+		m.recordMessages(systemTranscript(sessionRowID, msg))
+
 		if msg.Subtype == "init" {
 			// Update session ID if it was temporary
 			if msg.SessionID != "" && msg.SessionID != tempSessionID {
@@ -274,27 +406,45 @@ func (m *Manager) createSystemHandler(channelID, threadTS, tempSessionID string)
 				}
 			}
 
-			text := messages.FormatSessionStartMessage(msg.SessionID, msg.CWD, msg.Model)
-			return m.slackHandler.PostToThread(channelID, threadTS, text)
+			text := messages.FormatSessionStartMessage(msg.SessionID, msg.CWD, msg.Model, msg.PermissionMode, extendedThinking)
+			return m.slackHandler.PostSessionStartMessage(channelID, threadTS, text)
 		}
 		return nil
 	}
 }
 
-func (m *Manager) createAssistantHandler(channelID, threadTS string) func(process.AssistantMessage) error {
+func (m *Manager) createAssistantHandler(channelID, threadTS string, sessionRowID int64, status *statusMessage) func(process.AssistantMessage) error {
 	return func(msg process.AssistantMessage) error {
+		m.recordMessages(assistantTranscript(sessionRowID, msg))
+
+		m.mu.Lock()
+		var promptTSs []string
+		var previousReaction string
+		if session, ok := m.sessions[m.threadToSession[formatThreadKey(channelID, threadTS)]]; ok {
+			session.TurnRunning = true
+			promptTSs, previousReaction = setPromptReactionLocked(session, reactionRunning)
+		}
+		m.mu.Unlock()
+		m.replaceReactions(channelID, promptTSs, previousReaction, reactionRunning)
+
 		// Store tool_use_id to sessionID mapping
 		sessionID := msg.SessionID
-		if sessionID != "" {
-			for _, content := range msg.Message.Content {
-				if content.Type == "tool_use" && content.ID != "" {
-					m.mu.Lock()
+		for _, content := range msg.Message.Content {
+			if content.Type == "tool_use" && content.ID != "" {
+				m.mu.Lock()
+				if sessionID != "" {
 					m.toolUseToSession[content.ID] = sessionID
-					m.mu.Unlock()
 				}
+				m.toolUseToName[content.ID] = content.Name
+				m.mu.Unlock()
 			}
 		}
 
+		if status != nil {
+			m.updateStatus(status, channelID, threadTS, msg)
+			return nil
+		}
+
 		var text string
 
 		for _, content := range msg.Message.Content {
@@ -444,10 +594,7 @@ func (m *Manager) createAssistantHandler(channelID, threadTS string) func(proces
 						// Get relative path from work directory
 						relPath := m.getRelativePath(channelID, threadTS, filePath)
 						message := messages.FormatEditToolMessage(relPath)
-						// Post using tool-specific icon and username
-						if err := m.slackHandler.PostToolMessage(channelID, threadTS, message, ccslack.ToolEdit); err != nil {
-							fmt.Printf("Failed to post Edit tool to Slack: %v\n", err)
-						}
+						m.postFileChangeToolMessage(channelID, threadTS, ccslack.ToolEdit, relPath, message, content.Input)
 					}
 				} else if content.Name == "MultiEdit" && content.Input != nil {
 					// Handle MultiEdit tool
@@ -455,10 +602,7 @@ func (m *Manager) createAssistantHandler(channelID, threadTS string) func(proces
 						// Get relative path from work directory
 						relPath := m.getRelativePath(channelID, threadTS, filePath)
 						message := messages.FormatEditToolMessage(relPath)
-						// Post using tool-specific icon and username
-						if err := m.slackHandler.PostToolMessage(channelID, threadTS, message, ccslack.ToolMultiEdit); err != nil {
-							fmt.Printf("Failed to post MultiEdit tool to Slack: %v\n", err)
-						}
+						m.postFileChangeToolMessage(channelID, threadTS, ccslack.ToolMultiEdit, relPath, message, content.Input)
 					}
 				} else if content.Name == "Write" && content.Input != nil {
 					// Handle Write tool
@@ -466,10 +610,7 @@ func (m *Manager) createAssistantHandler(channelID, threadTS string) func(proces
 						// Get relative path from work directory
 						relPath := m.getRelativePath(channelID, threadTS, filePath)
 						message := messages.FormatWriteToolMessage(relPath)
-						// Post using tool-specific icon and username
-						if err := m.slackHandler.PostToolMessage(channelID, threadTS, message, ccslack.ToolWrite); err != nil {
-							fmt.Printf("Failed to post Write tool to Slack: %v\n", err)
-						}
+						m.postFileChangeToolMessage(channelID, threadTS, ccslack.ToolWrite, relPath, message, content.Input)
 					}
 				} else if content.Name == "LS" && content.Input != nil {
 					// Handle LS tool
@@ -527,8 +668,10 @@ func (m *Manager) createAssistantHandler(channelID, threadTS string) func(proces
 	}
 }
 
-func (m *Manager) createUserHandler(channelID, threadTS string) func(process.UserMessage) error {
+func (m *Manager) createUserHandler(channelID, threadTS string, sessionRowID int64, status *statusMessage) func(process.UserMessage) error {
 	return func(msg process.UserMessage) error {
+		m.recordMessages(userTranscript(sessionRowID, msg))
+
 		// Update last active time
 		m.mu.Lock()
 		key := formatThreadKey(channelID, threadTS)
@@ -538,30 +681,152 @@ func (m *Manager) createUserHandler(channelID, threadTS string) func(process.Use
 			}
 		}
 		m.mu.Unlock()
+
+		m.postToolResults(channelID, threadTS, msg, status != nil)
 		return nil
 	}
 }
 
-func (m *Manager) createResultHandler(channelID, threadTS, tempSessionID string) func(process.ResultMessage) error {
-	return func(msg process.ResultMessage) error {
-		// Update database
-		if msg.SessionID != "" {
-			ctx := context.Background()
+// updateStatus reflects an assistant message in the compact mode status message
+// Assistant text is held back until it is known to be the final answer rather than a remark before a tool use.
+func (m *Manager) updateStatus(status *statusMessage, channelID, threadTS string, msg process.AssistantMessage) {
+	var text string
+	hasToolUse := false
+
+	for _, content := range msg.Message.Content {
+		switch content.Type {
+		case "text":
+			text += content.Text + "\n"
+		case "thinking":
+			status.setActivity("💭 Thinking")
+		case "tool_use":
+			hasToolUse = true
+			if content.Name == ccslack.ToolTodoWrite {
+				status.setTodos(todoProgress(content.Input))
+			}
+			status.toolUse(messages.FormatToolActivity(content.Name, m.toolTarget(channelID, threadTS, content.Input)))
+		}
+	}
 
-			// If session ID changed from temp, update it first
-			if msg.SessionID != tempSessionID && strings.HasPrefix(tempSessionID, "temp_") {
-				m.updateSessionID(channelID, threadTS, msg.SessionID)
+	if text != "" && !hasToolUse {
+		status.appendText(text)
+	}
+}
+
+// toolTarget returns the command, path, pattern or URL a tool works on
+func (m *Manager) toolTarget(channelID, threadTS string, input map[string]interface{}) string {
+	for _, key := range []string{"command", "file_path", "path", "pattern", "url", "query", "description"} {
+		if value, ok := input[key].(string); ok && value != "" {
+			if key == "file_path" || key == "path" {
+				return m.getRelativePath(channelID, threadTS, value)
 			}
+			return value
+		}
+	}
+	return ""
+}
 
-			// Update session completion status
-			if err := m.UpdateSessionOnComplete(ctx, msg.SessionID, msg); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to update session on complete: %v\n", err)
+// todoProgress counts completed todos in a TodoWrite input and returns the todo in progress
+func todoProgress(input map[string]interface{}) (done, total int, current string) {
+	todos, _ := input["todos"].([]interface{})
+	for _, todoInterface := range todos {
+		todo, ok := todoInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		total++
+		switch todo["status"] {
+		case "completed":
+			done++
+		case "in_progress":
+			if current == "" {
+				current, _ = todo["content"].(string)
+			}
+		}
+	}
+	return done, total, current
+}
+
+// quietToolResults are tools whose successful results are not posted because their tool post already shows the outcome
+var quietToolResults = map[string]bool{
+	ccslack.ToolTodoWrite: true,
+	ccslack.ToolRead:      true,
+	ccslack.ToolEdit:      true,
+	ccslack.ToolMultiEdit: true,
+	ccslack.ToolWrite:     true,
+}
+
+// postToolResults posts previews of the tool results in a user message according to the channel's verbosity
+// Long outputs are uploaded as a snippet for failed tools, and for every tool with full verbosity.
+// In compact mode only failed results are posted.
+func (m *Manager) postToolResults(channelID, threadTS string, msg process.UserMessage, compact bool) {
+	verbosity := config.ToolResultsOff
+	if m.config != nil && m.slackHandler != nil {
+		verbosity = m.config.Slack.ToolResults.VerbosityFor(channelID)
+	}
+	if compact && verbosity != config.ToolResultsOff {
+		verbosity = config.ToolResultsErrors
+	}
+
+	for _, content := range msg.Message.Content {
+		if content.Type != "tool_result" {
+			continue
+		}
+
+		m.mu.Lock()
+		toolName := m.toolUseToName[content.ToolUseID]
+		delete(m.toolUseToName, content.ToolUseID)
+		m.mu.Unlock()
+		if toolName == "" {
+			toolName = "Tool"
+		}
+
+		if verbosity == config.ToolResultsOff {
+			continue
+		}
+		if !content.IsError && (verbosity == config.ToolResultsErrors || quietToolResults[toolName]) {
+			continue
+		}
+
+		output := toolResultText(content.Content)
+		if strings.TrimSpace(output) == "" {
+			continue
+		}
+
+		message, truncated := messages.FormatToolResultMessage(output, content.IsError)
+		// Post using tool-specific icon and username so the result reads as part of the tool post
+		if err := m.slackHandler.PostToolMessage(channelID, threadTS, message, toolName); err != nil {
+			fmt.Printf("Failed to post %s tool result to Slack: %v\n", toolName, err)
+			continue
+		}
+
+		if truncated && (content.IsError || verbosity == config.ToolResultsFull) {
+			title := fmt.Sprintf("%s output", toolName)
+			if err := m.slackHandler.PostSnippet(channelID, threadTS, "output.txt", title, output, "text"); err != nil {
+				fmt.Printf("Failed to upload %s tool result to Slack: %v\n", toolName, err)
 			}
 		}
+	}
+}
+
+func (m *Manager) createResultHandler(channelID, threadTS, tempSessionID string, sessionRowID int64, status *statusMessage) func(process.ResultMessage) error {
+	return func(msg process.ResultMessage) error {
+		m.recordMessages(resultTranscript(sessionRowID, msg))
+
+		// If session ID changed from temp, update it first
+		if msg.SessionID != "" && msg.SessionID != tempSessionID && strings.HasPrefix(tempSessionID, "temp_") {
+			m.updateSessionID(channelID, threadTS, msg.SessionID)
+		}
 
 		// Get session info and ensure cleanup
 		var userID string
 		var processToClose *process.ClaudeProcess
+		var found, interrupted bool
+		var interruptedBy, worktreePath, gitDir, gitBaseTree string
+		var handingOff *Session
+		var promptTSs []string
+		var previousReaction string
+		var endedSessionID string
 
 		// Critical section: get session info and remove from maps
 		func() {
@@ -571,10 +836,33 @@ func (m *Manager) createResultHandler(channelID, threadTS, tempSessionID string)
 			key := formatThreadKey(channelID, threadTS)
 			sessionID := m.threadToSession[key]
 			session, _ := m.sessions[sessionID]
+			if session != nil && session.HandingOff {
+				// Already finalized by forceStopSession
+				return
+			}
 
 			if session != nil {
+				found = true
 				userID = session.InitiatorUserID
 				processToClose = session.Process
+				interrupted = session.Interrupted
+				interruptedBy = session.InterruptedBy
+				worktreePath = session.Worktree
+				gitDir = session.WorkDir
+				if session.Worktree != "" {
+					gitDir = session.Worktree
+				}
+				gitBaseTree = session.GitBaseTree
+				promptTSs, previousReaction = setPromptReactionLocked(session, turnReaction(session.Interrupted, msg.IsError))
+
+				// Messages sent during the turn start the next one. Until then the session stays
+				// registered with its turn running, so messages sent in the meantime are queued as well.
+				if len(session.Queued) > 0 {
+					session.HandingOff = true
+					handingOff = session
+				} else {
+					session.TurnRunning = false
+				}
 			}
 
 			// Clean up tool_use_id mappings for this session
@@ -584,21 +872,56 @@ func (m *Manager) createResultHandler(channelID, threadTS, tempSessionID string)
 				}
 			}
 
-			delete(m.sessions, sessionID)
-			delete(m.threadToSession, key)
+			if handingOff == nil {
+				delete(m.sessions, sessionID)
+				delete(m.threadToSession, key)
+			}
+			endedSessionID = sessionID
 		}()
+		m.clearSessionGrants(endedSessionID)
 
-		// Ensure process cleanup outside of mutex lock
-		if processToClose != nil {
-			defer processToClose.Close()
+		// A session force-stopped after an interrupt has already been finalized and reported
+		if !found {
+			sessionID := msg.SessionID
+			if sessionID == "" {
+				sessionID = tempSessionID
+			}
+			if m.isInterruptedInDB(sessionID) {
+				return nil
+			}
 		}
 
+		// Update database
+		if msg.SessionID != "" {
+			ctx := context.Background()
+
+			if interrupted {
+				if err := m.UpdateSessionOnInterrupt(ctx, msg.SessionID, msg); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to update session on interrupt: %v\n", err)
+				}
+			} else if err := m.UpdateSessionOnComplete(ctx, msg.SessionID, msg); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to update session on complete: %v\n", err)
+			}
+		}
+		m.refreshHome(userID)
+
 		// Clean up uploaded images
-		if m.imagesDir != "" && threadTS != "" {
-			imageDir := filepath.Join(m.imagesDir, strings.ReplaceAll(threadTS, ".", "_"))
-			if err := os.RemoveAll(imageDir); err != nil {
-				// Log error but don't fail the session completion
-				fmt.Fprintf(os.Stderr, "Failed to remove image directory %s: %v\n", imageDir, err)
+		m.removeImageDir(threadTS)
+
+		m.replaceReactions(channelID, promptTSs, previousReaction, turnReaction(interrupted, msg.IsError))
+
+		// In compact mode the final answer is posted once the session is done
+		if status != nil {
+			outcome := statusOutcomeFinished
+			if interrupted {
+				outcome = statusOutcomeStopped
+			} else if msg.IsError {
+				outcome = statusOutcomeFailed
+			}
+			if text := status.finish(outcome); text != "" {
+				if err := m.slackHandler.PostAssistantMessage(channelID, threadTS, text); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to post final answer: %v\n", err)
+				}
 			}
 		}
 
@@ -610,7 +933,10 @@ func (m *Manager) createResultHandler(channelID, threadTS, tempSessionID string)
 			actualSessionID = tempSessionID
 		}
 
-		if msg.IsError {
+		if interrupted {
+			duration := time.Duration(msg.DurationMS) * time.Millisecond
+			text = messages.FormatSessionInterruptedMessage(actualSessionID, interruptedBy, duration, msg.NumTurns, msg.TotalCostUSD)
+		} else if msg.IsError {
 			text = messages.FormatErrorMessage(actualSessionID)
 		} else {
 			// Convert milliseconds to time.Duration
@@ -623,7 +949,44 @@ func (m *Manager) createResultHandler(channelID, threadTS, tempSessionID string)
 			text = fmt.Sprintf("<@%s> %s", userID, text)
 		}
 
-		return m.slackHandler.PostToThread(channelID, threadTS, text)
+		// Summarize what the session changed in its git repository, with buttons uploading the full patch
+		// and opening a pull request from the thread's worktree
+		var err error
+		if summary := summarizeGitChanges(gitDir, gitBaseTree); summary != nil {
+			text += "\n\n" + messages.FormatGitChangeSummary(summary)
+			var patch string
+			if len(summary.Files) > 0 {
+				patch = summary.BaseTree + ".." + summary.Tree
+			}
+			pullRequest := len(summary.Files) > 0 && m.canCreatePullRequest(worktreePath)
+			err = m.slackHandler.PostResultMessage(channelID, threadTS, text, patch, pullRequest)
+		} else {
+			err = m.slackHandler.PostToThread(channelID, threadTS, text)
+		}
+
+		// Closing the process waits for its output to be read, which this handler is part of, so it is
+		// closed in the background. Messages sent during the turn start the next one once it has exited.
+		go func() {
+			if processToClose != nil {
+				processToClose.Close()
+			}
+			if handingOff != nil {
+				m.handOff(handingOff)
+			}
+		}()
+		return err
+	}
+}
+
+// turnReaction returns the reaction shown on the prompt messages of a finished turn
+func turnReaction(interrupted, isError bool) string {
+	switch {
+	case interrupted:
+		return reactionStopped
+	case isError:
+		return reactionError
+	default:
+		return reactionDone
 	}
 }
 
@@ -634,13 +997,24 @@ func (m *Manager) createErrorHandler(channelID, threadTS string) func(error) {
 	}
 }
 
-// SendMessage sends a message to a specific session
-func (m *Manager) SendMessage(sessionID string, message string) error {
+// SendMessage sends a message posted by userID in a Slack thread to a specific session
+// While a turn is running the message is queued and marked with a reaction, and all queued
+// messages are sent as one prompt when the turn ends.
+func (m *Manager) SendMessage(sessionID, userID, messageTS, message string) error {
 	m.mu.Lock()
 	session, exists := m.sessions[sessionID]
+	var queue bool
 	if exists {
 		session.LastActive = time.Now()
 		m.lastActiveID = sessionID
+		queue = session.TurnRunning
+		if queue {
+			session.Queued = append(session.Queued, queuedMessage{UserID: userID, MessageTS: messageTS, Text: message})
+		} else {
+			session.TurnRunning = true
+			session.PromptMessageTSs = []string{messageTS}
+			session.PromptReaction = reactionReceived
+		}
 	}
 	m.mu.Unlock()
 
@@ -648,7 +1022,143 @@ func (m *Manager) SendMessage(sessionID string, message string) error {
 		return fmt.Errorf("session not found: %s", sessionID)
 	}
 
-	return session.Process.SendMessage(message)
+	if queue {
+		m.replaceReactions(session.ChannelID, []string{messageTS}, "", reactionQueued)
+		return nil
+	}
+
+	m.replaceReactions(session.ChannelID, []string{messageTS}, "", reactionReceived)
+	if err := session.Process.SendMessage(message); err != nil {
+		m.mu.Lock()
+		session.TurnRunning = false
+		m.mu.Unlock()
+		m.replaceReactions(session.ChannelID, []string{messageTS}, reactionReceived, reactionError)
+		return err
+	}
+
+	m.recordMessages(promptTranscript(session.DBID, message))
+	return nil
+}
+
+// StopSession interrupts the current turn of the session in a thread.
+// The session is finalized as interrupted when Claude Code reports the result,
+// or by killing the process if it does not respond within interruptTimeout.
+func (m *Manager) StopSession(channelID, threadTS, userID string) error {
+	m.mu.Lock()
+	key := formatThreadKey(channelID, threadTS)
+	session, exists := m.sessions[m.threadToSession[key]]
+	if !exists || session.HandingOff {
+		m.mu.Unlock()
+		return fmt.Errorf("session not found for thread %s:%s", channelID, threadTS)
+	}
+	if session.Interrupted {
+		// Already stopping
+		m.mu.Unlock()
+		return nil
+	}
+	session.Interrupted = true
+	session.InterruptedBy = userID
+	m.mu.Unlock()
+
+	if err := session.Process.Interrupt(); err != nil {
+		// The process is not accepting input, so there is no point in waiting for it
+		fmt.Fprintf(os.Stderr, "Failed to interrupt session, killing process: %v\n", err)
+		m.forceStopSession(session)
+		return nil
+	}
+
+	time.AfterFunc(interruptTimeout, func() {
+		m.forceStopSession(session)
+	})
+	return nil
+}
+
+// forceStopSession kills the process of an interrupted session and finalizes it
+// unless the result handler has already done so
+func (m *Manager) forceStopSession(session *Session) {
+	m.mu.Lock()
+	sessionID := session.ID
+	if m.sessions[sessionID] != session || session.HandingOff {
+		// Already finalized by the result handler
+		m.mu.Unlock()
+		return
+	}
+
+	// Clean up tool_use_id mappings for this session
+	for toolUseID, sid := range m.toolUseToSession {
+		if sid == sessionID {
+			delete(m.toolUseToSession, toolUseID)
+		}
+	}
+
+	// Messages sent during the turn start the next one, and the session stays registered until then
+	handingOff := len(session.Queued) > 0
+	if handingOff {
+		session.HandingOff = true
+	} else {
+		delete(m.sessions, sessionID)
+		delete(m.threadToSession, formatThreadKey(session.ChannelID, session.ThreadTS))
+		if m.lastActiveID == sessionID {
+			m.lastActiveID = ""
+		}
+	}
+	promptTSs, previousReaction := setPromptReactionLocked(session, reactionStopped)
+	m.mu.Unlock()
+	m.clearSessionGrants(sessionID)
+
+	m.replaceReactions(session.ChannelID, promptTSs, previousReaction, reactionStopped)
+
+	if err := session.Process.Kill(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to kill claude process: %v\n", err)
+	}
+
+	if session.Status != nil {
+		session.Status.finish(statusOutcomeStopped)
+	}
+
+	// Update database
+	err := m.queries.UpdateSessionEndTime(context.Background(), db.UpdateSessionEndTimeParams{
+		Status:    sql.NullString{String: "interrupted", Valid: true},
+		SessionID: sessionID,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update session on interrupt: %v\n", err)
+	}
+	m.refreshHome(session.InitiatorUserID)
+
+	m.removeImageDir(session.ThreadTS)
+
+	// Claude Code did not report a result, so no turn statistics are available
+	text := messages.FormatSessionInterruptedMessage(sessionID, session.InterruptedBy, 0, 0, 0)
+	if session.InitiatorUserID != "" {
+		text = fmt.Sprintf("<@%s> %s", session.InitiatorUserID, text)
+	}
+	if err := m.slackHandler.PostToThread(session.ChannelID, session.ThreadTS, text); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to post interrupted message: %v\n", err)
+	}
+
+	if handingOff {
+		m.handOff(session)
+	}
+}
+
+// isInterruptedInDB reports whether a session has been recorded as interrupted
+func (m *Manager) isInterruptedInDB(sessionID string) bool {
+	session, err := m.queries.GetSession(context.Background(), sessionID)
+	return err == nil && session.Status.String == "interrupted"
+}
+
+// removeImageDir removes images uploaded to a thread
+func (m *Manager) removeImageDir(threadTS string) {
+	if m.imagesDir == "" || threadTS == "" {
+		return
+	}
+
+	imageDir := filepath.Join(m.imagesDir, strings.ReplaceAll(threadTS, ".", "_"))
+	if err := os.RemoveAll(imageDir); err != nil {
+		// Log error but don't fail the session cleanup
+		fmt.Fprintf(os.Stderr, "Failed to remove image directory %s: %v\n", imageDir, err)
+	}
 }
 
 // GetSession returns a session by ID
@@ -680,6 +1190,10 @@ func (m *Manager) CleanupIdleSessions(maxIdleTime time.Duration) {
 	now := time.Now()
 
 	for sessionID, session := range sessions {
+		if session.HandingOff {
+			// The session has ended and the next one is starting
+			continue
+		}
 		if now.Sub(session.LastActive) > maxIdleTime {
 			// Notify Slack about timeout
 			if m.slackHandler != nil {
@@ -694,17 +1208,16 @@ func (m *Manager) CleanupIdleSessions(maxIdleTime time.Duration) {
 				SessionID: sessionID,
 			})
 
+			m.refreshHome(session.InitiatorUserID)
+
 			// Close process and clean up
 			session.Process.Close()
+			if session.Status != nil {
+				session.Status.finish(statusOutcomeTimedOut)
+			}
 
 			// Clean up uploaded images
-			if m.imagesDir != "" && session.ThreadTS != "" {
-				imageDir := filepath.Join(m.imagesDir, strings.ReplaceAll(session.ThreadTS, ".", "_"))
-				if err := os.RemoveAll(imageDir); err != nil {
-					// Log error but don't fail the cleanup
-					fmt.Fprintf(os.Stderr, "Failed to remove image directory %s: %v\n", imageDir, err)
-				}
-			}
+			m.removeImageDir(session.ThreadTS)
 
 			m.mu.Lock()
 			key := fmt.Sprintf("%s:%s", session.ChannelID, session.ThreadTS)
@@ -721,7 +1234,15 @@ func (m *Manager) CleanupIdleSessions(maxIdleTime time.Duration) {
 			if m.lastActiveID == sessionID {
 				m.lastActiveID = ""
 			}
+			queued := session.Queued
+			session.Queued = nil
+			promptTSs, previousReaction := setPromptReactionLocked(session, reactionError)
 			m.mu.Unlock()
+			m.clearSessionGrants(sessionID)
+
+			// The turn never finished, so messages waiting for it are not sent
+			m.replaceReactions(session.ChannelID, promptTSs, previousReaction, reactionError)
+			m.replaceReactions(session.ChannelID, queuedMessageTSs(queued), reactionQueued, reactionError)
 		}
 	}
 }
@@ -740,7 +1261,7 @@ func (m *Manager) ShouldResume(ctx context.Context, channelID, threadTS string)
 		return false, "", fmt.Errorf("failed to get thread: %w", err)
 	}
 
-	// Get latest completed session
+	// Get latest completed or interrupted session
 	session, err := m.queries.GetLatestSessionByThread(ctx, thread.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -797,19 +1318,52 @@ func (m *Manager) UpdateSessionOnComplete(ctx context.Context, sessionID string,
 	})
 }
 
+// UpdateSessionOnInterrupt records the result of a turn that was stopped from Slack
+func (m *Manager) UpdateSessionOnInterrupt(ctx context.Context, sessionID string, result process.ResultMessage) error {
+	return m.queries.UpdateSessionStatus(ctx, db.UpdateSessionStatusParams{
+		Status:       sql.NullString{String: "interrupted", Valid: true},
+		TotalCostUsd: sql.NullFloat64{Float64: result.TotalCostUSD, Valid: true},
+		InputTokens:  sql.NullInt64{Int64: int64(result.Usage.InputTokens), Valid: true},
+		OutputTokens: sql.NullInt64{Int64: int64(result.Usage.OutputTokens), Valid: true},
+		DurationMs:   sql.NullInt64{Int64: int64(result.DurationMS), Valid: true},
+		NumTurns:     sql.NullInt64{Int64: int64(result.NumTurns), Valid: true},
+		SessionID:    sessionID,
+	})
+}
+
 // GetSessionByThread returns a session by channel and thread (for slack.SessionManager interface)
 func (m *Manager) GetSessionByThread(channelID, threadTS string) (*ccslack.Session, error) {
-	session, exists := m.GetSessionByThreadInternal(channelID, threadTS)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, exists := m.sessions[m.threadToSession[formatThreadKey(channelID, threadTS)]]
 	if !exists {
 		return nil, fmt.Errorf("session not found for thread %s:%s", channelID, threadTS)
 	}
 
-	return &ccslack.Session{
-		SessionID: session.ID,
-		ChannelID: session.ChannelID,
-		ThreadTS:  session.ThreadTS,
-		WorkDir:   session.WorkDir,
-	}, nil
+	return slackSessionLocked(session), nil
+}
+
+// GetLatestSessionByChannel returns the most recently active session in a channel (for slack.SessionManager interface)
+func (m *Manager) GetLatestSessionByChannel(channelID string) (*ccslack.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest *Session
+	for _, session := range m.sessions {
+		if session.ChannelID != channelID {
+			continue
+		}
+		if latest == nil || session.LastActive.After(latest.LastActive) {
+			latest = session
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("no active session in channel %s", channelID)
+	}
+
+	return slackSessionLocked(latest), nil
 }
 
 // GetSessionByThreadInternal returns the internal session representation
@@ -827,6 +1381,30 @@ func (m *Manager) GetSessionByThreadInternal(channelID, threadTS string) (*Sessi
 	return session, exists
 }
 
+// RecordApproval records the outcome of an approval request in the session transcript
+func (m *Manager) RecordApproval(toolUseID, toolName string, response mcp.ApprovalResponse) {
+	m.mu.RLock()
+	var sessionRowID int64
+	var userID string
+	if sessionID, exists := m.toolUseToSession[toolUseID]; exists {
+		if session, exists := m.sessions[sessionID]; exists {
+			sessionRowID = session.DBID
+			userID = session.InitiatorUserID
+		}
+	}
+	m.mu.RUnlock()
+
+	// The request no longer waits on the initiator
+	m.refreshHome(userID)
+
+	if sessionRowID == 0 {
+		fmt.Fprintf(os.Stderr, "Failed to record approval: no session for tool_use_id %s\n", toolUseID)
+		return
+	}
+
+	m.recordMessages(approvalTranscript(sessionRowID, toolUseID, toolName, response))
+}
+
 // GetSessionInfoByToolUseID returns session info by tool_use_id
 func (m *Manager) GetSessionInfoByToolUseID(toolUseID string) (*mcp.SessionInfo, error) {
 	m.mu.RLock()
@@ -843,9 +1421,12 @@ func (m *Manager) GetSessionInfoByToolUseID(toolUseID string) (*mcp.SessionInfo,
 	}
 
 	return &mcp.SessionInfo{
+		SessionID: session.ID,
 		ChannelID: session.ChannelID,
 		ThreadTS:  session.ThreadTS,
 		UserID:    session.InitiatorUserID,
+		WorkDir:   session.WorkDir,
+		Worktree:  session.Worktree,
 	}, nil
 }
 
@@ -856,6 +1437,9 @@ func (m *Manager) Cleanup() {
 
 	for _, session := range m.sessions {
 		session.Process.Close()
+		if session.Status != nil {
+			session.Status.finish(statusOutcomeStopped)
+		}
 	}
 
 	m.sessions = make(map[string]*Session)
@@ -863,23 +1447,62 @@ func (m *Manager) Cleanup() {
 	m.lastActiveID = ""
 }
 
+// postFileChangeToolMessage posts an Edit/MultiEdit/Write tool message with the diff of the change
+// Diffs too long to show inline are uploaded as a snippet.
+func (m *Manager) postFileChangeToolMessage(channelID, threadTS, toolName, relPath, message string, input map[string]interface{}) {
+	var dirs []string
+	if dir := m.sessionDir(channelID, threadTS); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	d, ok := diff.FromToolInput(toolName, input, dirs)
+	if ok {
+		d.Path = relPath
+		message = messages.FormatFileChangeToolMessage(message, d)
+	}
+
+	// Post using tool-specific icon and username
+	if err := m.slackHandler.PostToolMessage(channelID, threadTS, message, toolName); err != nil {
+		fmt.Printf("Failed to post %s tool to Slack: %v\n", toolName, err)
+		return
+	}
+
+	if ok && d.Text != "" && !messages.IsInlineDiff(d) {
+		if err := m.slackHandler.PostDiffSnippet(channelID, threadTS, relPath, d); err != nil {
+			fmt.Printf("Failed to upload %s diff to Slack: %v\n", toolName, err)
+		}
+	}
+}
+
 // getRelativePath converts absolute path to relative path from work directory
 func (m *Manager) getRelativePath(channelID, threadTS, absolutePath string) string {
+	dir := m.sessionDir(channelID, threadTS)
+	if dir == "" {
+		return absolutePath
+	}
+	return computeRelativePath(dir, absolutePath)
+}
+
+// sessionDir returns the directory Claude runs in for a thread: its worktree, or its working directory
+// It returns an empty string when the thread has no active session.
+func (m *Manager) sessionDir(channelID, threadTS string) string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	key := formatThreadKey(channelID, threadTS)
 	sessionID, exists := m.threadToSession[key]
 	if !exists {
-		return absolutePath
+		return ""
 	}
 
 	session, exists := m.sessions[sessionID]
 	if !exists {
-		return absolutePath
+		return ""
 	}
 
-	return computeRelativePath(session.WorkDir, absolutePath)
+	if session.Worktree != "" {
+		return session.Worktree
+	}
+	return session.WorkDir
 }
 
 // trimNewlines removes leading and trailing newlines from a string
@@ -2,6 +2,7 @@ package session
 
 import (
 	"testing"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/yuya-takeyama/cc-slack/internal/mcp"
@@ -321,3 +322,57 @@ func TestGetSessionInfoByToolUseID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLatestSessionByChannel(t *testing.T) {
+	now := time.Now()
+	manager := &Manager{
+		sessions: map[string]*Session{
+			"older": {
+				ID:         "older",
+				ChannelID:  "C123456",
+				ThreadTS:   "1111111111.111111",
+				LastActive: now.Add(-time.Minute),
+			},
+			"newer": {
+				ID:         "newer",
+				ChannelID:  "C123456",
+				ThreadTS:   "2222222222.222222",
+				LastActive: now,
+			},
+			"other-channel": {
+				ID:         "other-channel",
+				ChannelID:  "C999999",
+				ThreadTS:   "3333333333.333333",
+				LastActive: now.Add(time.Minute),
+			},
+		},
+	}
+
+	session, err := manager.GetLatestSessionByChannel("C123456")
+	if err != nil {
+		t.Fatalf("GetLatestSessionByChannel() unexpected error: %v", err)
+	}
+	if session.SessionID != "newer" || session.ThreadTS != "2222222222.222222" {
+		t.Errorf("GetLatestSessionByChannel() = %+v, want session newer", session)
+	}
+
+	if _, err := manager.GetLatestSessionByChannel("C000000"); err == nil {
+		t.Error("GetLatestSessionByChannel() expected error for channel without sessions")
+	}
+}
+
+func TestStopSessionNotFound(t *testing.T) {
+	manager := &Manager{
+		sessions:        make(map[string]*Session),
+		threadToSession: make(map[string]string),
+	}
+
+	err := manager.StopSession("C123456", "1234567890.123456", "U987654")
+	if err == nil {
+		t.Fatal("StopSession() expected error but got none")
+	}
+	want := "session not found for thread C123456:1234567890.123456"
+	if err.Error() != want {
+		t.Errorf("StopSession() error = %v, want %v", err.Error(), want)
+	}
+}
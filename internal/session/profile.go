@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/process"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+)
+
+// applyClaudeProfile sets the Claude Code options of a working directory on process options
+// claude.default_options are passed to every session, before the options of the working directory.
+func (m *Manager) applyClaudeProfile(opts *process.Options, workDir string) {
+	opts.ExtraArgs = m.config.Claude.DefaultOptions
+
+	wd, ok := m.config.FindWorkingDir(workDir)
+	if !ok {
+		return
+	}
+	profile := wd.Claude
+	opts.Model = profile.Model
+	opts.AllowedTools = profile.AllowedTools
+	opts.DisallowedTools = profile.DisallowedTools
+	opts.AppendSystemPrompt = profile.AppendSystemPrompt
+	opts.PermissionMode = profile.PermissionMode
+	opts.MaxTurns = profile.MaxTurns
+	if profile.ExtendedThinking {
+		opts.MaxThinkingTokens = process.ExtendedThinkingTokens
+	}
+	if len(profile.MCPServers) > 0 {
+		opts.MCPServers = make(map[string]interface{}, len(profile.MCPServers))
+		for _, s := range profile.MCPServers {
+			opts.MCPServers[s.Name] = mcpServerConfig(s)
+		}
+	}
+}
+
+// applySessionOptions sets the options chosen for a session over the working directory profile
+func applySessionOptions(opts *process.Options, sessionOpts ccslack.SessionOptions) {
+	if sessionOpts.Model != "" {
+		opts.Model = sessionOpts.Model
+	}
+	if sessionOpts.PermissionMode != "" {
+		opts.PermissionMode = sessionOpts.PermissionMode
+	}
+	if sessionOpts.ExtendedThinking != nil {
+		opts.MaxThinkingTokens = 0
+		if *sessionOpts.ExtendedThinking {
+			opts.MaxThinkingTokens = process.ExtendedThinkingTokens
+		}
+	}
+}
+
+// inheritSessionOptions fills the options not chosen for a resumed session from the session it resumes
+// Plan mode is not inherited, so that replying to a plan lets Claude carry it out, and neither is a
+// permission mode the working directory no longer allows.
+func (m *Manager) inheritSessionOptions(ctx context.Context, previousSessionID, workDir string, opts ccslack.SessionOptions) ccslack.SessionOptions {
+	previous, err := m.queries.GetSession(ctx, previousSessionID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			fmt.Fprintf(os.Stderr, "Failed to get previous session options: %v\n", err)
+		}
+		return opts
+	}
+
+	if opts.Model == "" && previous.Model.Valid {
+		opts.Model = previous.Model.String
+	}
+	if opts.PermissionMode == "" && previous.PermissionMode.Valid && previous.PermissionMode.String != "plan" {
+		if wd, _ := m.config.FindWorkingDir(workDir); wd.Claude.AllowsPermissionMode(previous.PermissionMode.String) {
+			opts.PermissionMode = previous.PermissionMode.String
+		}
+	}
+	if opts.ExtendedThinking == nil && previous.ExtendedThinking.Valid {
+		enabled := previous.ExtendedThinking.Bool
+		opts.ExtendedThinking = &enabled
+	}
+	return opts
+}
+
+// mcpServerConfig converts a configured MCP server to the Claude Code MCP config format
+func mcpServerConfig(s config.MCPServerConfig) map[string]interface{} {
+	server := map[string]interface{}{}
+	switch s.Type {
+	case "http", "sse":
+		server["type"] = s.Type
+		server["url"] = s.URL
+		if len(s.Headers) > 0 {
+			headers := make(map[string]string, len(s.Headers))
+			for _, header := range s.Headers {
+				name, value, _ := strings.Cut(header, ":")
+				headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			}
+			server["headers"] = headers
+		}
+	default:
+		server["type"] = "stdio"
+		server["command"] = s.Command
+		if len(s.Args) > 0 {
+			server["args"] = s.Args
+		}
+		if len(s.Env) > 0 {
+			env := make(map[string]string, len(s.Env))
+			for _, e := range s.Env {
+				name, value, _ := strings.Cut(e, "=")
+				env[name] = value
+			}
+			server["env"] = env
+		}
+	}
+	return server
+}
@@ -0,0 +1,206 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/database"
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	"github.com/yuya-takeyama/cc-slack/internal/process"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+)
+
+func TestApplyClaudeProfile(t *testing.T) {
+	manager := &Manager{
+		config: &config.Config{
+			Claude: config.ClaudeConfig{DefaultOptions: []string{"--debug"}},
+			WorkingDirs: []config.WorkingDirectoryConfig{
+				{
+					Name: "api",
+					Path: "/src/api",
+					Claude: config.ClaudeProfileConfig{
+						Model:            "opus",
+						AllowedTools:     []string{"Read"},
+						PermissionMode:   "plan",
+						MaxTurns:         10,
+						ExtendedThinking: true,
+						MCPServers: []config.MCPServerConfig{
+							{Name: "github", Command: "github-mcp-server", Args: []string{"stdio"}, Env: []string{"GITHUB_TOKEN=a=b"}},
+							{Name: "docs", Type: "http", URL: "https://docs.example.com/mcp", Headers: []string{"Authorization: Bearer token"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		workDir string
+		want    process.Options
+	}{
+		{
+			name:    "configured working directory",
+			workDir: "/src/api",
+			want: process.Options{
+				Model:             "opus",
+				AllowedTools:      []string{"Read"},
+				PermissionMode:    "plan",
+				MaxTurns:          10,
+				MaxThinkingTokens: process.ExtendedThinkingTokens,
+				MCPServers: map[string]interface{}{
+					"github": map[string]interface{}{
+						"type":    "stdio",
+						"command": "github-mcp-server",
+						"args":    []string{"stdio"},
+						"env":     map[string]string{"GITHUB_TOKEN": "a=b"},
+					},
+					"docs": map[string]interface{}{
+						"type":    "http",
+						"url":     "https://docs.example.com/mcp",
+						"headers": map[string]string{"Authorization": "Bearer token"},
+					},
+				},
+				ExtraArgs: []string{"--debug"},
+			},
+		},
+		{
+			name:    "directory from command-line flags",
+			workDir: "/src/other",
+			want:    process.Options{ExtraArgs: []string{"--debug"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got process.Options
+			manager.applyClaudeProfile(&got, tt.workDir)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyClaudeProfile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySessionOptions(t *testing.T) {
+	enabled, disabled := true, false
+	profile := process.Options{Model: "sonnet", PermissionMode: "acceptEdits", MaxThinkingTokens: process.ExtendedThinkingTokens}
+
+	tests := []struct {
+		name        string
+		sessionOpts ccslack.SessionOptions
+		want        process.Options
+	}{
+		{
+			name:        "directory defaults",
+			sessionOpts: ccslack.SessionOptions{},
+			want:        profile,
+		},
+		{
+			name:        "chosen options",
+			sessionOpts: ccslack.SessionOptions{Model: "opus", PermissionMode: "plan", ExtendedThinking: &disabled},
+			want:        process.Options{Model: "opus", PermissionMode: "plan"},
+		},
+		{
+			name:        "extended thinking turned on",
+			sessionOpts: ccslack.SessionOptions{ExtendedThinking: &enabled},
+			want:        profile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := profile
+			applySessionOptions(&got, tt.sessionOpts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applySessionOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInheritSessionOptions(t *testing.T) {
+	ctx := context.Background()
+	sqlDB, err := database.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+	if err := database.Migrate(sqlDB, "../../migrations"); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	queries := db.New(sqlDB)
+	thread, err := queries.CreateThread(ctx, db.CreateThreadParams{
+		ChannelID:        "C123",
+		ThreadTs:         "1",
+		WorkingDirectory: "/src/api",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for sessionID, mode := range map[string]string{"session-plan": "plan", "session-edits": "acceptEdits"} {
+		if _, err := queries.CreateSessionWithInitialPrompt(ctx, db.CreateSessionWithInitialPromptParams{
+			ThreadID:       thread.ID,
+			SessionID:      sessionID,
+			Model:          sql.NullString{String: "opus", Valid: true},
+			PermissionMode: sql.NullString{String: mode, Valid: true},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		previous   string
+		configured string
+		opts       ccslack.SessionOptions
+		want       ccslack.SessionOptions
+	}{
+		{
+			name:       "allowed permission mode",
+			previous:   "session-edits",
+			configured: "acceptEdits",
+			want:       ccslack.SessionOptions{Model: "opus", PermissionMode: "acceptEdits"},
+		},
+		{
+			name:       "permission mode no longer allowed",
+			previous:   "session-edits",
+			configured: "default",
+			want:       ccslack.SessionOptions{Model: "opus"},
+		},
+		{
+			name:       "plan mode",
+			previous:   "session-plan",
+			configured: "acceptEdits",
+			want:       ccslack.SessionOptions{Model: "opus"},
+		},
+		{
+			name:       "chosen options",
+			previous:   "session-edits",
+			configured: "acceptEdits",
+			opts:       ccslack.SessionOptions{Model: "haiku", PermissionMode: "default"},
+			want:       ccslack.SessionOptions{Model: "haiku", PermissionMode: "default"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &Manager{
+				queries: queries,
+				config: &config.Config{WorkingDirs: []config.WorkingDirectoryConfig{
+					{Name: "api", Path: "/src/api", Claude: config.ClaudeProfileConfig{PermissionMode: tt.configured}},
+				}},
+			}
+
+			got := manager.inheritSessionOptions(ctx, tt.previous, "/src/api", tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inheritSessionOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	"github.com/yuya-takeyama/cc-slack/internal/forge"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/worktree"
+)
+
+// pullRequestTimeout bounds committing, pushing and opening a pull request
+const pullRequestTimeout = 2 * time.Minute
+
+// maxPullRequestTitleLength is the length commit subjects and pull request titles are truncated to
+const maxPullRequestTitleLength = 72
+
+// slackMentionPattern matches user, channel and group mentions in Slack message text
+var slackMentionPattern = regexp.MustCompile(`<[@#!][^>]*>`)
+
+// SetForge sets the forge pull requests are opened on
+func (m *Manager) SetForge(f forge.Forge) {
+	m.forge = f
+}
+
+// canCreatePullRequest reports whether pull requests can be opened from a session's changes
+// Only sessions running in a worktree have a branch of their own to open a pull request from.
+func (m *Manager) canCreatePullRequest(worktreePath string) bool {
+	return m.forge != nil && worktreePath != ""
+}
+
+// CreatePullRequest commits the changes in a thread's worktree, pushes its branch and opens a pull request (for slack.SessionManager interface)
+// When a pull request was already opened from the thread, the new changes are pushed to it.
+func (m *Manager) CreatePullRequest(channelID, threadTS string) (*ccslack.PullRequest, error) {
+	if m.forge == nil {
+		return nil, fmt.Errorf("pull requests are not configured")
+	}
+	if _, exists := m.GetSessionByThreadInternal(channelID, threadTS); exists {
+		return nil, fmt.Errorf("a session is running in this thread, wait for it to finish")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pullRequestTimeout)
+	defer cancel()
+
+	thread, err := m.queries.GetThread(ctx, db.GetThreadParams{
+		ChannelID: channelID,
+		ThreadTs:  threadTS,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no session has run in this thread")
+		}
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+	if !thread.WorktreePath.Valid || !worktree.Exists(thread.WorktreePath.String) {
+		return nil, fmt.Errorf("this thread has no worktree")
+	}
+	path := thread.WorktreePath.String
+	branch := thread.WorktreeBranch.String
+
+	prompt, err := m.threadInitialPrompt(ctx, thread.ID)
+	if err != nil {
+		return nil, err
+	}
+	title := pullRequestTitle(prompt)
+
+	committed, err := worktree.Commit(ctx, path, commitMessage(title, prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	remote := m.config.GitHub.Remote
+	if err := worktree.Push(ctx, path, remote, branch); err != nil {
+		return nil, err
+	}
+
+	result := &ccslack.PullRequest{Branch: branch, Committed: committed}
+	if thread.PullRequestUrl.Valid {
+		result.URL = thread.PullRequestUrl.String
+		return result, nil
+	}
+
+	remoteURL, err := worktree.RemoteURL(ctx, path, remote)
+	if err != nil {
+		return nil, err
+	}
+	url, err := m.forge.CreatePullRequest(ctx, forge.PullRequest{
+		RemoteURL: remoteURL,
+		Branch:    branch,
+		Title:     title,
+		Body:      pullRequestBody(prompt),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.queries.UpdateThreadPullRequestURL(ctx, db.UpdateThreadPullRequestURLParams{
+		PullRequestUrl: sql.NullString{String: url, Valid: true},
+		ID:             thread.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store pull request: %w", err)
+	}
+
+	result.URL = url
+	result.Created = true
+	return result, nil
+}
+
+// threadInitialPrompt returns the prompt the first session of a thread was started with
+func (m *Manager) threadInitialPrompt(ctx context.Context, threadID int64) (string, error) {
+	sessions, err := m.queries.ListSessionsByThreadID(ctx, threadID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, s := range sessions {
+		if s.InitialPrompt.Valid && strings.TrimSpace(s.InitialPrompt.String) != "" {
+			return s.InitialPrompt.String, nil
+		}
+	}
+	return "", nil
+}
+
+// pullRequestTitle derives a commit subject and pull request title from a thread's initial prompt
+// The first line of the prompt is used without Slack mentions and truncated to a subject line.
+func pullRequestTitle(prompt string) string {
+	for _, line := range strings.Split(prompt, "\n") {
+		line = strings.Join(strings.Fields(slackMentionPattern.ReplaceAllString(line, "")), " ")
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > maxPullRequestTitleLength {
+			line = string(runes[:maxPullRequestTitleLength-1]) + "…"
+		}
+		return line
+	}
+	return "Changes from Slack thread"
+}
+
+// commitMessage returns the message of the commit made from a thread's changes
+// The full prompt follows the title when the title does not already cover it.
+func commitMessage(title, prompt string) string {
+	prompt = strings.TrimSpace(slackMentionPattern.ReplaceAllString(prompt, ""))
+	if prompt == "" || prompt == title {
+		return title
+	}
+	return title + "\n\n" + prompt
+}
+
+// pullRequestBody returns the description of a pull request opened from a thread
+func pullRequestBody(prompt string) string {
+	var b strings.Builder
+	b.WriteString("Opened from a Slack thread by cc-slack.")
+	if prompt = strings.TrimSpace(slackMentionPattern.ReplaceAllString(prompt, "")); prompt != "" {
+		b.WriteString("\n\n## Prompt\n")
+		for _, line := range strings.Split(prompt, "\n") {
+			b.WriteString("\n> " + line)
+		}
+	}
+	return b.String()
+}
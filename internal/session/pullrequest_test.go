@@ -0,0 +1,176 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/database"
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	"github.com/yuya-takeyama/cc-slack/internal/forge"
+	"github.com/yuya-takeyama/cc-slack/internal/worktree"
+)
+
+func TestPullRequestTitle(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{
+			name:   "first line without mentions",
+			prompt: "<@U123> Fix the login bug\nIt fails on empty passwords",
+			want:   "Fix the login bug",
+		},
+		{
+			name:   "skips blank lines",
+			prompt: "<@U123>\n\n  Add   a README  ",
+			want:   "Add a README",
+		},
+		{
+			name:   "truncates long lines",
+			prompt: strings.Repeat("a", 100),
+			want:   strings.Repeat("a", 71) + "…",
+		},
+		{
+			name:   "empty prompt",
+			prompt: "",
+			want:   "Changes from Slack thread",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pullRequestTitle(tt.prompt); got != tt.want {
+				t.Errorf("pullRequestTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+	remoteDir := filepath.Join(tmpDir, "remote.git")
+	runGit := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(tmpDir, "init", "-q", "--bare", remoteDir)
+	runGit(repoDir, "init", "-q")
+	runGit(repoDir, "config", "user.name", "test")
+	runGit(repoDir, "config", "user.email", "test@example.com")
+	runGit(repoDir, "commit", "-q", "--allow-empty", "-m", "initial")
+	runGit(repoDir, "remote", "add", "origin", remoteDir)
+
+	worktreePath := filepath.Join(tmpDir, "worktrees", "C123-1")
+	branch := "cc-slack/C123-1"
+	if err := worktree.Create(ctx, repoDir, worktreePath, branch); err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+
+	sqlDB, err := database.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+	if err := database.Migrate(sqlDB, "../../migrations"); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	queries := db.New(sqlDB)
+	thread, err := queries.CreateThread(ctx, db.CreateThreadParams{
+		ChannelID:        "C123",
+		ThreadTs:         "1",
+		WorkingDirectory: repoDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := queries.UpdateThreadWorktree(ctx, db.UpdateThreadWorktreeParams{
+		WorktreePath:   sql.NullString{String: worktreePath, Valid: true},
+		WorktreeBranch: sql.NullString{String: branch, Valid: true},
+		ID:             thread.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queries.CreateSessionWithInitialPrompt(ctx, db.CreateSessionWithInitialPromptParams{
+		ThreadID:      thread.ID,
+		SessionID:     "session-1",
+		InitialPrompt: sql.NullString{String: "<@UBOT> Fix the login bug\nIt fails on empty passwords", Valid: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &forge.Fake{}
+	manager := &Manager{
+		sessions:        make(map[string]*Session),
+		threadToSession: make(map[string]string),
+		queries:         queries,
+		config:          &config.Config{GitHub: config.GitHubConfig{Remote: "origin"}},
+		forge:           fake,
+	}
+
+	if err := os.WriteFile(filepath.Join(worktreePath, "login.go"), []byte("package login\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := manager.CreatePullRequest("C123", "1")
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	if !pr.Created || !pr.Committed || pr.URL != "https://forge.example.com/pull/1" || pr.Branch != branch {
+		t.Errorf("CreatePullRequest() = %+v, want a created pull request on %s", pr, branch)
+	}
+
+	if len(fake.PullRequests) != 1 {
+		t.Fatalf("forge received %d pull requests, want 1", len(fake.PullRequests))
+	}
+	if got := fake.PullRequests[0]; got.Title != "Fix the login bug" || got.Branch != branch || got.RemoteURL != remoteDir {
+		t.Errorf("forge pull request = %+v", got)
+	}
+	if got := runGit(worktreePath, "log", "-1", "--format=%s"); got != "Fix the login bug" {
+		t.Errorf("commit subject = %q, want %q", got, "Fix the login bug")
+	}
+	if got, want := runGit(remoteDir, "rev-parse", "refs/heads/"+branch), runGit(worktreePath, "rev-parse", "HEAD"); got != want {
+		t.Errorf("pushed commit = %s, want %s", got, want)
+	}
+
+	stored, err := queries.GetThreadByID(ctx, thread.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.PullRequestUrl.String != pr.URL {
+		t.Errorf("stored pull request URL = %q, want %q", stored.PullRequestUrl.String, pr.URL)
+	}
+
+	// A second request pushes to the pull request opened before
+	pr, err = manager.CreatePullRequest("C123", "1")
+	if err != nil {
+		t.Fatalf("second CreatePullRequest() error = %v", err)
+	}
+	if pr.Created || pr.Committed || pr.URL != "https://forge.example.com/pull/1" {
+		t.Errorf("second CreatePullRequest() = %+v, want the existing pull request", pr)
+	}
+	if len(fake.PullRequests) != 1 {
+		t.Errorf("forge received %d pull requests, want 1", len(fake.PullRequests))
+	}
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+)
+
+// reactionQueued marks a Slack message queued while Claude is in the middle of a turn
+const reactionQueued = "hourglass_flowing_sand"
+
+// queuedMessage is a Slack message received while Claude was in the middle of a turn
+type queuedMessage struct {
+	UserID    string
+	MessageTS string
+	Text      string
+}
+
+// combineQueuedMessages joins queued messages into a single prompt
+// A single message is sent as is; several are prefixed with their authors so Claude can tell them apart.
+func combineQueuedMessages(queued []queuedMessage) string {
+	if len(queued) == 1 {
+		return queued[0].Text
+	}
+
+	var b strings.Builder
+	b.WriteString("The following messages were sent in the Slack thread while you were working:")
+	for _, msg := range queued {
+		b.WriteString("\n\n")
+		if msg.UserID != "" {
+			b.WriteString(fmt.Sprintf("<@%s>: ", msg.UserID))
+		}
+		b.WriteString(msg.Text)
+	}
+	return b.String()
+}
+
+// queuedMessageTSs returns the Slack message timestamps of queued messages
+func queuedMessageTSs(queued []queuedMessage) []string {
+	messageTSs := make([]string, 0, len(queued))
+	for _, msg := range queued {
+		messageTSs = append(messageTSs, msg.MessageTS)
+	}
+	return messageTSs
+}
+
+// handOff starts the next turn of a thread with the messages queued during a turn that has ended
+// It is called once the process of the ended session has exited. The ended session stays registered
+// for the thread until the next one takes it over in registerSession, so messages sent in the meantime
+// are queued for the next turn instead of resuming the Claude session a second time.
+func (m *Manager) handOff(ended *Session) {
+	m.mu.Lock()
+	queued := ended.Queued
+	ended.Queued = nil
+	m.mu.Unlock()
+
+	m.deliverQueuedMessages(ended.ChannelID, ended.ThreadTS, ended.WorkDir, ended.InitiatorUserID, queued)
+
+	// The ended session is still registered when the next one could not be started
+	m.mu.Lock()
+	var stranded []queuedMessage
+	if m.sessions[ended.ID] == ended {
+		stranded = ended.Queued
+		ended.Queued = nil
+		delete(m.sessions, ended.ID)
+		key := formatThreadKey(ended.ChannelID, ended.ThreadTS)
+		if m.threadToSession[key] == ended.ID {
+			delete(m.threadToSession, key)
+		}
+		if m.lastActiveID == ended.ID {
+			m.lastActiveID = ""
+		}
+	}
+	m.mu.Unlock()
+
+	m.replaceReactions(ended.ChannelID, queuedMessageTSs(stranded), reactionQueued, reactionError)
+}
+
+// deliverQueuedMessages sends messages queued during a turn that has ended as one prompt
+// The turn's process has exited, so the prompt resumes the session in a new process.
+// The queued messages become the prompt messages of the new turn.
+func (m *Manager) deliverQueuedMessages(channelID, threadTS, workDir, userID string, queued []queuedMessage) {
+	if len(queued) == 0 {
+		return
+	}
+
+	messageTSs := queuedMessageTSs(queued)
+	m.replaceReactions(channelID, messageTSs, reactionQueued, reactionReceived)
+
+	_, previousSessionID, err := m.createSession(context.Background(), channelID, threadTS, workDir, combineQueuedMessages(queued), userID, messageTSs, ccslack.SessionOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to deliver queued messages: %v\n", err)
+		if m.slackHandler != nil {
+			_ = m.slackHandler.PostToThread(channelID, threadTS, fmt.Sprintf("Failed to send queued messages: %v", err))
+		}
+		return
+	}
+
+	if m.slackHandler != nil {
+		text := messages.FormatQueuedMessagesDeliveredMessage(len(queued), previousSessionID)
+		if err := m.slackHandler.PostToThread(channelID, threadTS, text); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to post queued messages notice: %v\n", err)
+		}
+	}
+}
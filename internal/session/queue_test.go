@@ -0,0 +1,113 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+)
+
+func TestCombineQueuedMessages(t *testing.T) {
+	tests := []struct {
+		name   string
+		queued []queuedMessage
+		want   string
+	}{
+		{
+			name:   "single message is sent as is",
+			queued: []queuedMessage{{UserID: "U1", Text: "add tests too"}},
+			want:   "add tests too",
+		},
+		{
+			name: "several messages are prefixed with their authors",
+			queued: []queuedMessage{
+				{UserID: "U1", Text: "add tests too"},
+				{UserID: "U2", Text: "and update the README"},
+			},
+			want: "The following messages were sent in the Slack thread while you were working:\n\n" +
+				"<@U1>: add tests too\n\n" +
+				"<@U2>: and update the README",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := combineQueuedMessages(tt.queued); got != tt.want {
+				t.Errorf("combineQueuedMessages() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendMessageQueuesDuringTurn(t *testing.T) {
+	session := &Session{ID: "session-1", ChannelID: "C123", ThreadTS: "1234567890.123456", TurnRunning: true}
+	manager := &Manager{
+		sessions:        map[string]*Session{"session-1": session},
+		threadToSession: map[string]string{formatThreadKey("C123", "1234567890.123456"): "session-1"},
+	}
+
+	if err := manager.SendMessage("session-1", "U1", "1234567890.000001", "first"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if err := manager.SendMessage("session-1", "U2", "1234567890.000002", "second"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	want := []queuedMessage{
+		{UserID: "U1", MessageTS: "1234567890.000001", Text: "first"},
+		{UserID: "U2", MessageTS: "1234567890.000002", Text: "second"},
+	}
+	if len(session.Queued) != len(want) {
+		t.Fatalf("queued %d messages, want %d", len(session.Queued), len(want))
+	}
+	for i := range want {
+		if session.Queued[i] != want[i] {
+			t.Errorf("Queued[%d] = %+v, want %+v", i, session.Queued[i], want[i])
+		}
+	}
+}
+
+func TestMessageDuringHandOff(t *testing.T) {
+	channelID, threadTS := "C123", "1234567890.123456"
+	ended := &Session{ID: "session-1", ChannelID: channelID, ThreadTS: threadTS, TurnRunning: true, HandingOff: true}
+	manager := &Manager{
+		sessions:        map[string]*Session{"session-1": ended},
+		threadToSession: map[string]string{formatThreadKey(channelID, threadTS): "session-1"},
+	}
+
+	// A reply arriving before the next session has started is queued on the ended session
+	current, err := manager.GetSessionByThread(channelID, threadTS)
+	if err != nil {
+		t.Fatalf("GetSessionByThread() error = %v", err)
+	}
+	if err := manager.SendMessage(current.SessionID, "U2", "1234567890.000003", "one more thing"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	// Nothing else may resume the Claude session while the thread is handed over
+	if _, _, err := manager.CreateSession(context.Background(), channelID, threadTS, "", "resume", "U3", "", ccslack.SessionOptions{}); err == nil {
+		t.Error("CreateSession() error = nil during a handoff")
+	}
+	if err := manager.StopSession(channelID, threadTS, "U3"); err == nil {
+		t.Error("StopSession() error = nil during a handoff")
+	}
+
+	// The next session takes over the thread and the reply waits for its turn to end
+	next := &Session{ID: "temp_1", ChannelID: channelID, ThreadTS: threadTS, TurnRunning: true}
+	manager.registerSession(next)
+
+	if _, exists := manager.sessions["session-1"]; exists {
+		t.Error("ended session is still registered")
+	}
+	current, err = manager.GetSessionByThread(channelID, threadTS)
+	if err != nil || current.SessionID != "temp_1" {
+		t.Fatalf("GetSessionByThread() = %+v, %v, want temp_1", current, err)
+	}
+	want := []queuedMessage{{UserID: "U2", MessageTS: "1234567890.000003", Text: "one more thing"}}
+	if len(next.Queued) != len(want) || next.Queued[0] != want[0] {
+		t.Errorf("Queued = %+v, want %+v", next.Queued, want)
+	}
+	if len(ended.Queued) != 0 {
+		t.Errorf("ended session kept %d queued messages", len(ended.Queued))
+	}
+}
@@ -0,0 +1,49 @@
+package session
+
+import (
+	"fmt"
+	"os"
+)
+
+// Reactions showing the state of the Slack messages a turn works on
+const (
+	reactionReceived = "eyes"
+	reactionRunning  = "hourglass"
+	reactionDone     = "white_check_mark"
+	reactionError    = "x"
+	reactionStopped  = "black_square_for_stop"
+)
+
+// replaceReactions replaces a reaction on Slack messages with another one
+// An empty remove only adds the new reaction.
+func (m *Manager) replaceReactions(channelID string, messageTSs []string, remove, add string) {
+	if m.slackHandler == nil {
+		return
+	}
+
+	for _, messageTS := range messageTSs {
+		if messageTS == "" {
+			continue
+		}
+		if remove != "" {
+			if err := m.slackHandler.RemoveReaction(channelID, messageTS, remove); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to remove reaction: %v\n", err)
+			}
+		}
+		if err := m.slackHandler.AddReaction(channelID, messageTS, add); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to add reaction: %v\n", err)
+		}
+	}
+}
+
+// setPromptReactionLocked records a new reaction for the prompt messages of a session's turn
+// It returns the messages and the reaction to replace, or nil when the reaction is already shown.
+// m.mu must be held; the caller applies the change with replaceReactions after unlocking.
+func setPromptReactionLocked(session *Session, reaction string) ([]string, string) {
+	if session.PromptReaction == reaction || len(session.PromptMessageTSs) == 0 {
+		return nil, ""
+	}
+	previous := session.PromptReaction
+	session.PromptReaction = reaction
+	return session.PromptMessageTSs, previous
+}
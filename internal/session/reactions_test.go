@@ -0,0 +1,64 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTurnReaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		interrupted bool
+		isError     bool
+		want        string
+	}{
+		{name: "completed", want: reactionDone},
+		{name: "error", isError: true, want: reactionError},
+		{name: "interrupted", interrupted: true, isError: true, want: reactionStopped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := turnReaction(tt.interrupted, tt.isError); got != tt.want {
+				t.Errorf("turnReaction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPromptReactionLocked(t *testing.T) {
+	tests := []struct {
+		name         string
+		session      Session
+		reaction     string
+		wantTSs      []string
+		wantPrevious string
+	}{
+		{
+			name:         "moves to the next reaction",
+			session:      Session{PromptMessageTSs: []string{"1.1"}, PromptReaction: reactionReceived},
+			reaction:     reactionRunning,
+			wantTSs:      []string{"1.1"},
+			wantPrevious: reactionReceived,
+		},
+		{
+			name:     "already shown",
+			session:  Session{PromptMessageTSs: []string{"1.1"}, PromptReaction: reactionRunning},
+			reaction: reactionRunning,
+		},
+		{
+			name:     "no prompt messages",
+			session:  Session{PromptReaction: reactionReceived},
+			reaction: reactionDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTSs, gotPrevious := setPromptReactionLocked(&tt.session, tt.reaction)
+			if !reflect.DeepEqual(gotTSs, tt.wantTSs) || gotPrevious != tt.wantPrevious {
+				t.Errorf("setPromptReactionLocked() = %v, %q, want %v, %q", gotTSs, gotPrevious, tt.wantTSs, tt.wantPrevious)
+			}
+		})
+	}
+}
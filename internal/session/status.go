@@ -0,0 +1,183 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
+)
+
+// defaultStatusUpdateInterval is used when no compact mode update interval is configured
+const defaultStatusUpdateInterval = 3 * time.Second
+
+// statusElapsedRefresh is how often the status message is updated only to refresh the elapsed time
+const statusElapsedRefresh = 30 * time.Second
+
+// Outcomes shown in the status message when a session ends
+const (
+	statusOutcomeFinished = "✅ Finished"
+	statusOutcomeStopped  = "⏹️ Stopped"
+	statusOutcomeFailed   = "❌ Failed"
+	statusOutcomeTimedOut = "⌛ Timed out"
+)
+
+// statusPoster posts and updates the compact mode status message
+type statusPoster interface {
+	PostStatusMessage(channelID, threadTS, text string) (string, error)
+	UpdateStatusMessage(channelID, messageTS, text string) error
+}
+
+// statusMessage keeps a single Slack message updated with the progress of a session in compact mode
+// Updates are throttled to one chat.update per interval to stay within Slack rate limits.
+type statusMessage struct {
+	poster    statusPoster
+	channelID string
+	threadTS  string
+	startedAt time.Time
+
+	mu          sync.Mutex
+	status      messages.SessionStatus
+	messageTS   string
+	dirty       bool
+	lastUpdate  time.Time
+	pendingText string // Assistant text since the last tool use, posted as the final answer
+	finished    bool
+
+	flushMu sync.Mutex // Serializes posting and updating the message
+	stop    chan struct{}
+}
+
+// newStatusMessage creates a status message and starts updating it every interval
+// The message is posted once there is something to show.
+func newStatusMessage(poster statusPoster, channelID, threadTS string, interval time.Duration) *statusMessage {
+	if interval <= 0 {
+		interval = defaultStatusUpdateInterval
+	}
+
+	s := &statusMessage{
+		poster:    poster,
+		channelID: channelID,
+		threadTS:  threadTS,
+		startedAt: time.Now(),
+		stop:      make(chan struct{}),
+	}
+	go s.run(interval)
+	return s
+}
+
+// run flushes pending changes until the session finishes
+func (s *statusMessage) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.flush(false)
+		}
+	}
+}
+
+// toolUse records a tool use; assistant text before it was intermediate and is dropped
+func (s *statusMessage) toolUse(activity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.ToolUses++
+	s.status.Activity = activity
+	s.pendingText = ""
+	s.dirty = true
+}
+
+// setActivity updates what Claude is currently doing
+func (s *statusMessage) setActivity(activity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.Activity = activity
+	s.dirty = true
+}
+
+// setTodos updates the todo progress
+func (s *statusMessage) setTodos(done, total int, current string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.TodosDone = done
+	s.status.TodosTotal = total
+	s.status.Todo = current
+	s.dirty = true
+}
+
+// appendText buffers assistant text that may turn out to be the final answer
+func (s *statusMessage) appendText(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pendingText += text
+}
+
+// finish stops the updates and shows the outcome of the session
+// It returns the final assistant answer that has not been posted yet.
+func (s *statusMessage) finish(outcome string) string {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return ""
+	}
+	s.finished = true
+	close(s.stop)
+
+	s.status.Outcome = outcome
+	s.dirty = true
+	// A session that never showed any progress does not need a status message
+	shown := s.messageTS != "" || s.status.ToolUses > 0
+	text := s.pendingText
+	s.pendingText = ""
+	s.mu.Unlock()
+
+	if shown {
+		s.flush(true)
+	}
+	return text
+}
+
+// flush posts or updates the message when something changed or the elapsed time is stale
+func (s *statusMessage) flush(force bool) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	if !force && (s.finished || (!s.dirty && (s.messageTS == "" || time.Since(s.lastUpdate) < statusElapsedRefresh))) {
+		s.mu.Unlock()
+		return
+	}
+	status := s.status
+	status.Elapsed = time.Since(s.startedAt)
+	messageTS := s.messageTS
+	s.dirty = false
+	s.lastUpdate = time.Now()
+	s.mu.Unlock()
+
+	text := messages.FormatSessionStatusMessage(status)
+
+	if messageTS == "" {
+		ts, err := s.poster.PostStatusMessage(s.channelID, s.threadTS, text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to post status message: %v\n", err)
+			return
+		}
+		s.mu.Lock()
+		s.messageTS = ts
+		s.mu.Unlock()
+		return
+	}
+
+	if err := s.poster.UpdateStatusMessage(s.channelID, messageTS, text); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update status message: %v\n", err)
+	}
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeStatusPoster struct {
+	mu      sync.Mutex
+	posted  []string
+	updated []string
+}
+
+func (p *fakeStatusPoster) PostStatusMessage(channelID, threadTS, text string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.posted = append(p.posted, text)
+	return "1234.5678", nil
+}
+
+func (p *fakeStatusPoster) UpdateStatusMessage(channelID, messageTS, text string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updated = append(p.updated, text)
+	return nil
+}
+
+func TestStatusMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		run         func(s *statusMessage)
+		wantText    string
+		wantPosted  int
+		wantUpdated int
+	}{
+		{
+			name: "answer without tool uses posts no status message",
+			run: func(s *statusMessage) {
+				s.appendText("Hello\n")
+			},
+			wantText: "Hello\n",
+		},
+		{
+			name: "text before a tool use is dropped",
+			run: func(s *statusMessage) {
+				s.appendText("Let me check\n")
+				s.toolUse("Running `ls`")
+				s.appendText("Done\n")
+			},
+			wantText:   "Done\n",
+			wantPosted: 1,
+		},
+		{
+			name: "flushed message is updated on finish",
+			run: func(s *statusMessage) {
+				s.toolUse("Running `ls`")
+				s.flush(false)
+				s.setTodos(1, 2, "Write tests")
+			},
+			wantPosted:  1,
+			wantUpdated: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poster := &fakeStatusPoster{}
+			s := newStatusMessage(poster, "C123", "1111.2222", time.Hour)
+			tt.run(s)
+
+			if got := s.finish(statusOutcomeFinished); got != tt.wantText {
+				t.Errorf("finish() = %q, want %q", got, tt.wantText)
+			}
+			if got := s.finish(statusOutcomeStopped); got != "" {
+				t.Errorf("second finish() = %q, want empty", got)
+			}
+
+			if len(poster.posted) != tt.wantPosted {
+				t.Errorf("posted %d messages, want %d", len(poster.posted), tt.wantPosted)
+			}
+			if len(poster.updated) != tt.wantUpdated {
+				t.Errorf("updated %d times, want %d", len(poster.updated), tt.wantUpdated)
+			}
+
+			all := append(poster.posted, poster.updated...)
+			if len(all) > 0 && !strings.Contains(all[len(all)-1], statusOutcomeFinished) {
+				t.Errorf("last status %q does not show the outcome", all[len(all)-1])
+			}
+		})
+	}
+}
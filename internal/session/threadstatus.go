@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+)
+
+// slackSessionLocked converts a session for the slack package
+// m.mu must be held, as the turn state changes while the session runs.
+func slackSessionLocked(session *Session) *ccslack.Session {
+	return &ccslack.Session{
+		SessionID:  session.ID,
+		ChannelID:  session.ChannelID,
+		ThreadTS:   session.ThreadTS,
+		WorkDir:    session.WorkDir,
+		UserID:     session.InitiatorUserID,
+		Running:    session.TurnRunning,
+		LastActive: session.LastActive,
+	}
+}
+
+// ListSessionsByChannel returns the active sessions in a channel, most recently active first (for slack.SessionManager interface)
+func (m *Manager) ListSessionsByChannel(channelID string) ([]*ccslack.Session, error) {
+	m.mu.RLock()
+	var sessions []*ccslack.Session
+	for _, session := range m.sessions {
+		if session.ChannelID == channelID {
+			sessions = append(sessions, slackSessionLocked(session))
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActive.After(sessions[j].LastActive)
+	})
+	return sessions, nil
+}
+
+// GetThreadStatus returns the state and the cost so far of the sessions in a thread (for slack.SessionManager interface)
+// The cost of a running session is only known once its turn has finished, so it is not included yet.
+func (m *Manager) GetThreadStatus(channelID, threadTS string) (*ccslack.ThreadStatus, error) {
+	ctx := context.Background()
+	thread, err := m.queries.GetThread(ctx, db.GetThreadParams{
+		ChannelID: channelID,
+		ThreadTs:  threadTS,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no session has run in this thread")
+		}
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	sessions, err := m.queries.ListSessionsByThreadID(ctx, thread.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	status := &ccslack.ThreadStatus{
+		ChannelID: channelID,
+		ThreadTS:  threadTS,
+		WorkDir:   thread.WorkingDirectory,
+		Sessions:  len(sessions),
+	}
+	var latest *db.Session
+	for i, s := range sessions {
+		status.TotalCost += s.TotalCostUsd.Float64
+		// started_at has a resolution of seconds, so sessions started in the same second are ordered by ID
+		if latest == nil || s.StartedAt.Time.After(latest.StartedAt.Time) || (s.StartedAt.Time.Equal(latest.StartedAt.Time) && s.ID > latest.ID) {
+			latest = &sessions[i]
+		}
+	}
+	if latest != nil {
+		status.SessionID = latest.SessionID
+		status.UserID = latest.UserID.String
+		status.State = latest.Status.String
+	}
+
+	m.mu.RLock()
+	if session, exists := m.sessions[m.threadToSession[formatThreadKey(channelID, threadTS)]]; exists {
+		status.Active = true
+		status.SessionID = session.ID
+		status.UserID = session.InitiatorUserID
+		status.State = ccslack.ThreadStateWaiting
+		if session.TurnRunning {
+			status.State = ccslack.ThreadStateRunning
+		}
+	}
+	m.mu.RUnlock()
+
+	return status, nil
+}
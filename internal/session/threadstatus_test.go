@@ -0,0 +1,91 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/database"
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+)
+
+func TestGetThreadStatus(t *testing.T) {
+	ctx := context.Background()
+	sqlDB, err := database.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+	if err := database.Migrate(sqlDB, "../../migrations"); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	queries := db.New(sqlDB)
+	thread, err := queries.CreateThread(ctx, db.CreateThreadParams{
+		ChannelID:        "C123",
+		ThreadTs:         "1",
+		WorkingDirectory: "/src/api",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, sessionID := range []string{"session-1", "session-2"} {
+		if _, err := queries.CreateSessionWithInitialPrompt(ctx, db.CreateSessionWithInitialPromptParams{
+			ThreadID:  thread.ID,
+			SessionID: sessionID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := queries.UpdateSessionOnComplete(ctx, db.UpdateSessionOnCompleteParams{
+			Status:       sql.NullString{String: "completed", Valid: true},
+			EndedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+			TotalCostUsd: sql.NullFloat64{Float64: 0.25 * float64(i+1), Valid: true},
+			SessionID:    sessionID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := &Manager{
+		sessions:        make(map[string]*Session),
+		threadToSession: make(map[string]string),
+		queries:         queries,
+		config:          &config.Config{},
+	}
+
+	status, err := manager.GetThreadStatus("C123", "1")
+	if err != nil {
+		t.Fatalf("GetThreadStatus() error = %v", err)
+	}
+	want := ccslack.ThreadStatus{
+		ChannelID: "C123",
+		ThreadTS:  "1",
+		WorkDir:   "/src/api",
+		SessionID: "session-2",
+		State:     "completed",
+		Sessions:  2,
+		TotalCost: 0.75,
+	}
+	if *status != want {
+		t.Errorf("GetThreadStatus() = %+v, want %+v", *status, want)
+	}
+
+	// A running session takes over the state of the thread
+	manager.sessions["temp_1"] = &Session{ID: "temp_1", ChannelID: "C123", ThreadTS: "1", TurnRunning: true}
+	manager.threadToSession[formatThreadKey("C123", "1")] = "temp_1"
+	status, err = manager.GetThreadStatus("C123", "1")
+	if err != nil {
+		t.Fatalf("GetThreadStatus() error = %v", err)
+	}
+	if !status.Active || status.SessionID != "temp_1" || status.State != ccslack.ThreadStateRunning {
+		t.Errorf("GetThreadStatus() = %+v, want the running session", *status)
+	}
+
+	if _, err := manager.GetThreadStatus("C123", "2"); err == nil {
+		t.Error("GetThreadStatus() expected error for a thread without sessions")
+	}
+}
@@ -0,0 +1,177 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	"github.com/yuya-takeyama/cc-slack/internal/mcp"
+	"github.com/yuya-takeyama/cc-slack/internal/process"
+)
+
+// Transcript message types stored in the messages table
+// Approval messages store the approval behavior (allow or deny) as their subtype.
+const (
+	transcriptTypePrompt     = "prompt"
+	transcriptTypeSystem     = "system"
+	transcriptTypeText       = "text"
+	transcriptTypeThinking   = "thinking"
+	transcriptTypeToolUse    = "tool_use"
+	transcriptTypeToolResult = "tool_result"
+	transcriptTypeApproval   = "approval"
+	transcriptTypeResult     = "result"
+)
+
+// recordMessages persists transcript entries for a session.
+// Failures are logged and never interrupt message handling.
+func (m *Manager) recordMessages(params []db.CreateMessageParams) {
+	ctx := context.Background()
+	for _, p := range params {
+		if err := m.queries.CreateMessage(ctx, p); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to record %s message: %v\n", p.Type, err)
+		}
+	}
+}
+
+// promptTranscript converts a prompt sent to Claude Code into a transcript entry
+func promptTranscript(sessionRowID int64, prompt string) []db.CreateMessageParams {
+	return []db.CreateMessageParams{{
+		SessionID: sessionRowID,
+		Type:      transcriptTypePrompt,
+		Content:   nullString(prompt),
+	}}
+}
+
+// systemTranscript converts a system message into a transcript entry
+func systemTranscript(sessionRowID int64, msg process.SystemMessage) []db.CreateMessageParams {
+	return []db.CreateMessageParams{{
+		SessionID: sessionRowID,
+		Type:      transcriptTypeSystem,
+		Subtype:   nullString(msg.Subtype),
+		Content:   nullString(marshalContent(msg)),
+	}}
+}
+
+// assistantTranscript converts each content block of an assistant message into a transcript entry
+func assistantTranscript(sessionRowID int64, msg process.AssistantMessage) []db.CreateMessageParams {
+	var params []db.CreateMessageParams
+	for _, content := range msg.Message.Content {
+		switch content.Type {
+		case "text":
+			params = append(params, db.CreateMessageParams{
+				SessionID: sessionRowID,
+				Type:      transcriptTypeText,
+				Content:   nullString(content.Text),
+			})
+		case "thinking":
+			params = append(params, db.CreateMessageParams{
+				SessionID: sessionRowID,
+				Type:      transcriptTypeThinking,
+				Content:   nullString(content.Thinking),
+			})
+		case "tool_use":
+			params = append(params, db.CreateMessageParams{
+				SessionID: sessionRowID,
+				Type:      transcriptTypeToolUse,
+				ToolName:  nullString(content.Name),
+				ToolUseID: nullString(content.ID),
+				Content:   nullString(marshalContent(content.Input)),
+			})
+		}
+	}
+	return params
+}
+
+// userTranscript converts tool results in a user message into transcript entries
+func userTranscript(sessionRowID int64, msg process.UserMessage) []db.CreateMessageParams {
+	var params []db.CreateMessageParams
+	for _, content := range msg.Message.Content {
+		if content.Type != "tool_result" {
+			continue
+		}
+
+		// Tool results are either a plain string or an array of content blocks
+		var text string
+		switch c := content.Content.(type) {
+		case nil:
+		case string:
+			text = c
+		default:
+			text = marshalContent(c)
+		}
+
+		params = append(params, db.CreateMessageParams{
+			SessionID: sessionRowID,
+			Type:      transcriptTypeToolResult,
+			ToolUseID: nullString(content.ToolUseID),
+			Content:   nullString(text),
+			IsError:   content.IsError,
+		})
+	}
+	return params
+}
+
+// toolResultText extracts the text of a tool result
+// Results are either a plain string or an array of content blocks, of which only text blocks are kept.
+func toolResultText(content interface{}) string {
+	switch c := content.(type) {
+	case nil:
+		return ""
+	case string:
+		return c
+	case []interface{}:
+		var texts []string
+		for _, block := range c {
+			if m, ok := block.(map[string]interface{}); ok && m["type"] == "text" {
+				if text, ok := m["text"].(string); ok {
+					texts = append(texts, text)
+				}
+			}
+		}
+		return strings.Join(texts, "\n")
+	default:
+		return marshalContent(c)
+	}
+}
+
+// approvalTranscript converts an approval decision into a transcript entry
+func approvalTranscript(sessionRowID int64, toolUseID, toolName string, response mcp.ApprovalResponse) []db.CreateMessageParams {
+	return []db.CreateMessageParams{{
+		SessionID: sessionRowID,
+		Type:      transcriptTypeApproval,
+		Subtype:   nullString(response.Behavior),
+		ToolName:  nullString(toolName),
+		ToolUseID: nullString(toolUseID),
+		Content:   nullString(response.Message),
+		IsError:   response.Behavior != "allow",
+	}}
+}
+
+// resultTranscript converts a result message into a transcript entry
+func resultTranscript(sessionRowID int64, msg process.ResultMessage) []db.CreateMessageParams {
+	return []db.CreateMessageParams{{
+		SessionID: sessionRowID,
+		Type:      transcriptTypeResult,
+		Subtype:   nullString(msg.Subtype),
+		Content:   nullString(msg.Result),
+		IsError:   msg.IsError,
+	}}
+}
+
+// marshalContent encodes a value as JSON, returning an empty string on failure
+func marshalContent(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// nullString converts an empty string to a NULL value
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
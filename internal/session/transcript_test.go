@@ -0,0 +1,179 @@
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	"github.com/yuya-takeyama/cc-slack/internal/mcp"
+	"github.com/yuya-takeyama/cc-slack/internal/process"
+)
+
+func TestAssistantTranscript(t *testing.T) {
+	var msg process.AssistantMessage
+	raw := `{
+		"type": "assistant",
+		"message": {
+			"content": [
+				{"type": "thinking", "thinking": "Let me check"},
+				{"type": "text", "text": "Reading the file"},
+				{"type": "tool_use", "id": "toolu_1", "name": "Read", "input": {"file_path": "/tmp/a.go"}}
+			]
+		}
+	}`
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	got := assistantTranscript(42, msg)
+	want := []db.CreateMessageParams{
+		{SessionID: 42, Type: "thinking", Content: sql.NullString{String: "Let me check", Valid: true}},
+		{SessionID: 42, Type: "text", Content: sql.NullString{String: "Reading the file", Valid: true}},
+		{
+			SessionID: 42,
+			Type:      "tool_use",
+			ToolName:  sql.NullString{String: "Read", Valid: true},
+			ToolUseID: sql.NullString{String: "toolu_1", Valid: true},
+			Content:   sql.NullString{String: `{"file_path":"/tmp/a.go"}`, Valid: true},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("assistantTranscript() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUserTranscript(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []db.CreateMessageParams
+	}{
+		{
+			name: "string content",
+			raw:  `{"message": {"content": [{"type": "tool_result", "tool_use_id": "toolu_1", "content": "ok"}]}}`,
+			want: []db.CreateMessageParams{{
+				SessionID: 1,
+				Type:      "tool_result",
+				ToolUseID: sql.NullString{String: "toolu_1", Valid: true},
+				Content:   sql.NullString{String: "ok", Valid: true},
+			}},
+		},
+		{
+			name: "array content with error",
+			raw:  `{"message": {"content": [{"type": "tool_result", "tool_use_id": "toolu_2", "content": [{"type": "text", "text": "failed"}], "is_error": true}]}}`,
+			want: []db.CreateMessageParams{{
+				SessionID: 1,
+				Type:      "tool_result",
+				ToolUseID: sql.NullString{String: "toolu_2", Valid: true},
+				Content:   sql.NullString{String: `[{"text":"failed","type":"text"}]`, Valid: true},
+				IsError:   true,
+			}},
+		},
+		{
+			name: "non tool_result content is skipped",
+			raw:  `{"message": {"content": [{"type": "text", "content": "hello"}]}}`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg process.UserMessage
+			if err := json.Unmarshal([]byte(tt.raw), &msg); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			got := userTranscript(1, msg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("userTranscript() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultTranscript(t *testing.T) {
+	msg := process.ResultMessage{Subtype: "error_max_turns", IsError: true}
+
+	got := resultTranscript(7, msg)
+	want := []db.CreateMessageParams{{
+		SessionID: 7,
+		Type:      "result",
+		Subtype:   sql.NullString{String: "error_max_turns", Valid: true},
+		IsError:   true,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resultTranscript() = %+v, want %+v", got, want)
+	}
+}
+
+func TestApprovalTranscript(t *testing.T) {
+	tests := []struct {
+		name     string
+		response mcp.ApprovalResponse
+		want     db.CreateMessageParams
+	}{
+		{
+			name:     "allowed",
+			response: mcp.ApprovalResponse{Behavior: "allow"},
+			want: db.CreateMessageParams{
+				SessionID: 3,
+				Type:      "approval",
+				Subtype:   sql.NullString{String: "allow", Valid: true},
+				ToolName:  sql.NullString{String: "Bash", Valid: true},
+				ToolUseID: sql.NullString{String: "toolu_1", Valid: true},
+			},
+		},
+		{
+			name:     "denied with reason",
+			response: mcp.ApprovalResponse{Behavior: "deny", Message: "not now"},
+			want: db.CreateMessageParams{
+				SessionID: 3,
+				Type:      "approval",
+				Subtype:   sql.NullString{String: "deny", Valid: true},
+				ToolName:  sql.NullString{String: "Bash", Valid: true},
+				ToolUseID: sql.NullString{String: "toolu_1", Valid: true},
+				Content:   sql.NullString{String: "not now", Valid: true},
+				IsError:   true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := approvalTranscript(3, "toolu_1", "Bash", tt.response)
+			if !reflect.DeepEqual(got, []db.CreateMessageParams{tt.want}) {
+				t.Errorf("approvalTranscript() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolResultText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content interface{}
+		want    string
+	}{
+		{name: "nil", content: nil, want: ""},
+		{name: "string", content: "exit status 1", want: "exit status 1"},
+		{
+			name: "content blocks",
+			content: []interface{}{
+				map[string]interface{}{"type": "text", "text": "first"},
+				map[string]interface{}{"type": "image", "source": map[string]interface{}{}},
+				map[string]interface{}{"type": "text", "text": "second"},
+			},
+			want: "first\nsecond",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolResultText(tt.content); got != tt.want {
+				t.Errorf("toolResultText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
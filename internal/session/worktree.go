@@ -0,0 +1,137 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+	ccslack "github.com/yuya-takeyama/cc-slack/internal/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/worktree"
+)
+
+// prepareWorktree returns the git worktree a thread's session runs in, creating it when needed
+// It returns an empty path when worktrees are not enabled for the working directory.
+// A worktree removed after the thread was archived is recreated on the thread's branch.
+func (m *Manager) prepareWorktree(ctx context.Context, threadID int64, channelID, threadTS, workDir string) (string, error) {
+	wd, ok := m.config.FindWorkingDir(workDir)
+	if !ok || !wd.Worktree.Enabled {
+		return "", nil
+	}
+
+	thread, err := m.queries.GetThreadByID(ctx, threadID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	path := thread.WorktreePath.String
+	if path != "" && worktree.Exists(path) {
+		return path, nil
+	}
+	if path == "" {
+		path = filepath.Join(wd.Worktree.DirFor(wd.Path), worktree.Name(channelID, threadTS))
+	}
+	branch := thread.WorktreeBranch.String
+	if branch == "" {
+		branch = worktree.BranchName(wd.Worktree.BranchPrefixOrDefault(), channelID, threadTS)
+	}
+
+	if err := worktree.Create(ctx, wd.Path, path, branch); err != nil {
+		return "", err
+	}
+
+	err = m.queries.UpdateThreadWorktree(ctx, db.UpdateThreadWorktreeParams{
+		WorktreePath:   sql.NullString{String: path, Valid: true},
+		WorktreeBranch: sql.NullString{String: branch, Valid: true},
+		ID:             threadID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store worktree: %w", err)
+	}
+
+	return path, nil
+}
+
+// unarchiveThread clears the archived mark of a thread that is used again
+func (m *Manager) unarchiveThread(ctx context.Context, threadID int64) error {
+	return m.queries.UpdateThreadArchivedAt(ctx, db.UpdateThreadArchivedAtParams{
+		ArchivedAt: sql.NullTime{Valid: false},
+		ID:         threadID,
+	})
+}
+
+// ArchiveThread marks a thread as archived (for slack.SessionManager interface)
+// It returns the thread's worktree so that its cleanup can be offered, or nil when there is none on disk.
+func (m *Manager) ArchiveThread(channelID, threadTS string) (*ccslack.Worktree, error) {
+	if _, exists := m.GetSessionByThreadInternal(channelID, threadTS); exists {
+		return nil, fmt.Errorf("a session is running in this thread, stop it before archiving the thread")
+	}
+
+	ctx := context.Background()
+	thread, err := m.queries.GetThread(ctx, db.GetThreadParams{
+		ChannelID: channelID,
+		ThreadTs:  threadTS,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no session has run in this thread")
+		}
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	err = m.queries.UpdateThreadArchivedAt(ctx, db.UpdateThreadArchivedAtParams{
+		ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		ID:         thread.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive thread: %w", err)
+	}
+
+	if !thread.WorktreePath.Valid || !worktree.Exists(thread.WorktreePath.String) {
+		return nil, nil
+	}
+	return &ccslack.Worktree{
+		Path:   thread.WorktreePath.String,
+		Branch: thread.WorktreeBranch.String,
+	}, nil
+}
+
+// RemoveWorktree removes the git worktree of a thread and keeps its branch (for slack.SessionManager interface)
+// The worktree is recreated on the same branch if the thread is resumed.
+func (m *Manager) RemoveWorktree(channelID, threadTS string) (*ccslack.Worktree, error) {
+	if _, exists := m.GetSessionByThreadInternal(channelID, threadTS); exists {
+		return nil, fmt.Errorf("a session is running in this thread")
+	}
+
+	ctx := context.Background()
+	thread, err := m.queries.GetThread(ctx, db.GetThreadParams{
+		ChannelID: channelID,
+		ThreadTs:  threadTS,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+	if !thread.WorktreePath.Valid {
+		return nil, fmt.Errorf("this thread has no worktree")
+	}
+
+	path := thread.WorktreePath.String
+	if worktree.Exists(path) {
+		if err := worktree.Remove(ctx, thread.WorkingDirectory, path); err != nil {
+			return nil, err
+		}
+	}
+
+	err = m.queries.UpdateThreadWorktree(ctx, db.UpdateThreadWorktreeParams{
+		WorktreePath:   sql.NullString{Valid: false},
+		WorktreeBranch: thread.WorktreeBranch,
+		ID:             thread.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	return &ccslack.Worktree{Path: path, Branch: thread.WorktreeBranch.String}, nil
+}
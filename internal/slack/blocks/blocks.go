@@ -2,10 +2,14 @@ package blocks
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/slack-go/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/approval"
 	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
 	"github.com/yuya-takeyama/cc-slack/internal/tools"
 )
 
@@ -35,6 +39,40 @@ func MultiDirectoryError(slashCommand string) []slack.Block {
 	}
 }
 
+// DirectMessageDirectoryError creates blocks explaining how to start a session in a direct message (multi-directory mode)
+func DirectMessageDirectoryError(workingDirs []config.WorkingDirectoryConfig) []slack.Block {
+	var dirs strings.Builder
+	for _, wd := range workingDirs {
+		dirs.WriteString(fmt.Sprintf("\n• `%s`", wd.Name))
+		if wd.Description != "" {
+			dirs.WriteString(" - " + wd.Description)
+		}
+	}
+
+	example := "my-project"
+	if len(workingDirs) > 0 {
+		example = workingDirs[0].Name
+	}
+
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(
+				slack.MarkdownType,
+				fmt.Sprintf(":warning: *Multiple working directories are configured*\n\nStart your message with the working directory to use, followed by your prompt, e.g. `%s fix the failing tests`", example),
+				false,
+				false,
+			),
+			nil,
+			nil,
+		),
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, "*Working directories:*"+dirs.String(), false, false),
+			nil,
+			nil,
+		),
+	}
+}
+
 // ApprovalRequest creates blocks for tool approval request
 func ApprovalRequest(message, requestID, userID string) []slack.Block {
 	// Parse the message to extract structured information
@@ -44,37 +82,61 @@ func ApprovalRequest(message, requestID, userID string) []slack.Block {
 	// Build markdown text for the approval request
 	markdownText := buildApprovalMarkdownText(info)
 
+	buttons := []slack.BlockElement{
+		slack.NewButtonBlockElement(
+			fmt.Sprintf("approve_%s", requestID),
+			"approve",
+			slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false),
+		).WithStyle(slack.StylePrimary),
+		slack.NewButtonBlockElement(
+			fmt.Sprintf("edit_approve_%s", requestID),
+			"edit_approve",
+			slack.NewTextBlockObject(slack.PlainTextType, "Edit & Approve", false, false),
+		),
+		slack.NewButtonBlockElement(
+			fmt.Sprintf("approve_session_%s", requestID),
+			"approve_session",
+			slack.NewTextBlockObject(slack.PlainTextType, "Approve for Session", false, false),
+		),
+	}
+
+	// Bash commands can also be approved by command pattern, e.g. "go test *"
+	if pattern := approval.CommandPattern(info.Command); pattern != "" {
+		buttons = append(buttons, slack.NewButtonBlockElement(
+			fmt.Sprintf("approve_pattern_%s", requestID),
+			"approve_pattern",
+			slack.NewTextBlockObject(slack.PlainTextType, fmt.Sprintf("Approve \"%s *\" for Session", pattern), false, false),
+		))
+	}
+
+	buttons = append(buttons,
+		slack.NewButtonBlockElement(
+			fmt.Sprintf("deny_%s", requestID),
+			"deny",
+			slack.NewTextBlockObject(slack.PlainTextType, "Deny", false, false),
+		).WithStyle(slack.StyleDanger),
+		slack.NewButtonBlockElement(
+			fmt.Sprintf("deny_with_reason_%s", requestID),
+			"deny_with_reason",
+			slack.NewTextBlockObject(slack.PlainTextType, "Deny with Reason", false, false),
+		),
+	)
+
 	return []slack.Block{
 		slack.NewSectionBlock(
 			slack.NewTextBlockObject(slack.MarkdownType, markdownText, false, false),
 			nil,
 			nil,
 		),
-		slack.NewActionBlock(
-			"approval_actions",
-			slack.NewButtonBlockElement(
-				fmt.Sprintf("approve_%s", requestID),
-				"approve",
-				slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false),
-			).WithStyle(slack.StylePrimary),
-			slack.NewButtonBlockElement(
-				fmt.Sprintf("deny_%s", requestID),
-				"deny",
-				slack.NewTextBlockObject(slack.PlainTextType, "Deny", false, false),
-			).WithStyle(slack.StyleDanger),
-			slack.NewButtonBlockElement(
-				fmt.Sprintf("deny_with_reason_%s", requestID),
-				"deny_with_reason",
-				slack.NewTextBlockObject(slack.PlainTextType, "Deny with Reason", false, false),
-			),
-		),
+		slack.NewActionBlock("approval_actions", buttons...),
 	}
 }
 
 // ApprovalMessageUpdate creates blocks for approval status update
-func ApprovalMessageUpdate(originalText string, userID string, approved bool) []slack.Block {
+// grantScope is the approval.Scope* value for approvals granted for the rest of the session, or empty.
+func ApprovalMessageUpdate(originalText string, userID string, approved bool, grantScope string) []slack.Block {
 	// Create status markdown text
-	statusText := buildStatusMarkdownText(userID, approved)
+	statusText := buildStatusMarkdownText(userID, approved, grantScope)
 
 	// Combine original text with status
 	fullText := originalText + "\n\n" + statusText
@@ -88,12 +150,67 @@ func ApprovalMessageUpdate(originalText string, userID string, approved bool) []
 	}
 }
 
+// ApprovalExpired creates blocks for an approval request that timed out
+func ApprovalExpired(message, userID string, timeout time.Duration) []slack.Block {
+	info := parseApprovalMessage(message)
+	info.UserID = userID
+
+	fullText := buildApprovalMarkdownText(info) + "\n\n" +
+		fmt.Sprintf("────────────────\n:hourglass: *Expired* after %s and was denied", messages.FormatDuration(timeout))
+
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, fullText, false, false),
+			nil,
+			nil,
+		),
+	}
+}
+
+// ApprovalEditedUpdate creates blocks for an approval request that was approved with an edited input
+func ApprovalEditedUpdate(originalText, userID string, editedKeys []string, editedInput map[string]interface{}) []slack.Block {
+	var text strings.Builder
+	text.WriteString(originalText)
+	text.WriteString(fmt.Sprintf("\n\n────────────────\n:pencil2: *Approved with edits* by <@%s>", userID))
+
+	for _, key := range editedKeys {
+		value, _ := editedInput[key].(string)
+		if strings.Contains(value, "\n") || len(value) > maxEditedValueDisplayLength {
+			text.WriteString(fmt.Sprintf("\n*Edited %s*", key))
+			continue
+		}
+		text.WriteString(fmt.Sprintf("\n*Edited %s*: `%s`", key, value))
+	}
+
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text.String(), false, false),
+			nil,
+			nil,
+		),
+	}
+}
+
+// SessionStartPrefill holds the initial values of the session start modals
+type SessionStartPrefill struct {
+	Prompt  string // Initial value of the prompt input
+	WorkDir string // Path of the working directory selected initially (multi-directory mode)
+	Images  int    // Number of images attached from a message, mentioned below the inputs
+
+	// ChooseChannel asks for the channel the session thread is created in,
+	// for modals opened outside of a channel such as from the App Home tab
+	ChooseChannel bool
+}
+
 // SessionStartModal creates a modal for starting a new session (multi-directory mode)
-func SessionStartModal(channelID string, workingDirs []config.WorkingDirectoryConfig) slack.ModalViewRequest {
+// privateMetadata is handed back on submission to tell where the session starts.
+func SessionStartModal(privateMetadata string, workingDirs []config.WorkingDirectoryConfig, prefill SessionStartPrefill) slack.ModalViewRequest {
 	// Build options from configured working directories
 	var options []*slack.OptionBlockObject
+	var profiles []config.ClaudeProfileConfig
 
 	for _, wd := range workingDirs {
+		profiles = append(profiles, wd.Claude)
 		descText := wd.Name
 		if wd.Description != "" {
 			descText = fmt.Sprintf("%s - %s", wd.Name, wd.Description)
@@ -105,65 +222,205 @@ func SessionStartModal(channelID string, workingDirs []config.WorkingDirectoryCo
 		))
 	}
 
+	repoSelect := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeStatic,
+		slack.NewTextBlockObject(slack.PlainTextType, "Choose directory", false, false),
+		"repo_select",
+		options...,
+	)
+	for _, option := range options {
+		if prefill.WorkDir != "" && option.Value == prefill.WorkDir {
+			repoSelect.InitialOption = option
+		}
+	}
+
 	return slack.ModalViewRequest{
 		Type:            slack.VTModal,
 		CallbackID:      "repo_modal",
 		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Start Claude Session", false, false),
 		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Start", false, false),
 		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
-		PrivateMetadata: channelID, // Store channel ID for later use
+		PrivateMetadata: privateMetadata,
 		Blocks: slack.Blocks{
-			BlockSet: []slack.Block{
+			BlockSet: append(append(channelInput(prefill.ChooseChannel),
 				slack.NewInputBlock(
 					"repo_block",
 					slack.NewTextBlockObject(slack.PlainTextType, "Select working directory", false, false),
 					nil,
-					slack.NewOptionsSelectBlockElement(
-						slack.OptTypeStatic,
-						slack.NewTextBlockObject(slack.PlainTextType, "Choose directory", false, false),
-						"repo_select",
-						options...,
-					),
-				),
-				slack.NewInputBlock(
-					"prompt_block",
-					slack.NewTextBlockObject(slack.PlainTextType, "Initial prompt", false, false),
-					nil,
-					slack.NewRichTextInputBlockElement(
-						slack.NewTextBlockObject(slack.PlainTextType, "What would you like to work on? You can use **bold**, `code`, lists, etc.", false, false),
-						"prompt_input",
-					),
+					repoSelect,
 				),
-			},
+				promptInput(prefill.Prompt),
+				modelInput(),
+				permissionModeInput(profiles),
+				thinkingInput(),
+			), imagesContext(prefill.Images)...),
 		},
 	}
 }
 
 // SessionStartModalSingle creates a modal for starting a new session (single-directory mode)
-func SessionStartModalSingle(channelID string) slack.ModalViewRequest {
+// profile is the Claude Code profile of the working directory.
+func SessionStartModalSingle(privateMetadata string, profile config.ClaudeProfileConfig, prefill SessionStartPrefill) slack.ModalViewRequest {
 	return slack.ModalViewRequest{
 		Type:            slack.VTModal,
 		CallbackID:      "repo_modal_single",
 		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Start Claude Session", false, false),
 		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Start", false, false),
 		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
-		PrivateMetadata: channelID, // Store channel ID for later use
+		PrivateMetadata: privateMetadata,
 		Blocks: slack.Blocks{
-			BlockSet: []slack.Block{
-				slack.NewInputBlock(
-					"prompt_block",
-					slack.NewTextBlockObject(slack.PlainTextType, "Initial prompt", false, false),
-					nil,
-					slack.NewRichTextInputBlockElement(
-						slack.NewTextBlockObject(slack.PlainTextType, "What would you like to work on? You can use **bold**, `code`, lists, etc.", false, false),
-						"prompt_input",
-					),
-				),
-			},
+			BlockSet: append(append(channelInput(prefill.ChooseChannel),
+				promptInput(prefill.Prompt),
+				modelInput(),
+				permissionModeInput([]config.ClaudeProfileConfig{profile}),
+				thinkingInput(),
+			), imagesContext(prefill.Images)...),
 		},
 	}
 }
 
+// Block and action IDs of the channel select in the session start modals
+const (
+	ChannelBlockID  = "channel_block"
+	ChannelActionID = "channel_select"
+)
+
+// channelInput creates the channel select of the session start modals when the channel has to be chosen
+func channelInput(choose bool) []slack.Block {
+	if !choose {
+		return nil
+	}
+	element := slack.NewOptionsSelectBlockElement(
+		slack.OptTypeConversations,
+		slack.NewTextBlockObject(slack.PlainTextType, "Choose channel", false, false),
+		ChannelActionID,
+	)
+	element.Filter = &slack.SelectBlockElementFilter{Include: []string{"public", "private"}}
+	return []slack.Block{
+		slack.NewInputBlock(
+			ChannelBlockID,
+			slack.NewTextBlockObject(slack.PlainTextType, "Channel to start the thread in", false, false),
+			nil,
+			element,
+		),
+	}
+}
+
+// promptInput creates the prompt input of the session start modals
+func promptInput(prompt string) *slack.InputBlock {
+	element := slack.NewRichTextInputBlockElement(
+		slack.NewTextBlockObject(slack.PlainTextType, "What would you like to work on? You can use **bold**, `code`, lists, etc.", false, false),
+		"prompt_input",
+	)
+	if prompt != "" {
+		element.InitialValue = slack.NewRichTextBlock("", slack.NewRichTextSection(slack.NewRichTextSectionTextElement(prompt, nil)))
+	}
+	return slack.NewInputBlock(
+		"prompt_block",
+		slack.NewTextBlockObject(slack.PlainTextType, "Initial prompt", false, false),
+		nil,
+		element,
+	)
+}
+
+// imagesContext mentions the images attached to the session from a message
+func imagesContext(images int) []slack.Block {
+	if images == 0 {
+		return nil
+	}
+	text := "📎 1 image from the message is attached to the prompt"
+	if images > 1 {
+		text = fmt.Sprintf("📎 %d images from the message are attached to the prompt", images)
+	}
+	return []slack.Block{
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.PlainTextType, text, false, false)),
+	}
+}
+
+// Block and action IDs of the session option inputs in the session start modals
+const (
+	ModelBlockID           = "model_block"
+	ModelActionID          = "model_select"
+	PermissionModeBlockID  = "permission_mode_block"
+	PermissionModeActionID = "permission_mode_select"
+	ThinkingBlockID        = "thinking_block"
+	ThinkingActionID       = "thinking_radio"
+)
+
+// Values of the session option inputs
+const (
+	OptionDefault = "default" // Leave the option to the working directory profile
+	OptionOn      = "on"
+	OptionOff     = "off"
+
+	// PermissionModeNormal selects Claude Code's "default" permission mode, whose name is taken by OptionDefault
+	PermissionModeNormal = "normal"
+)
+
+// sessionModels are the models offered in the session start modals, as Claude Code model aliases
+var sessionModels = []string{"opus", "sonnet", "haiku"}
+
+// sessionPermissionModes are the permission modes offered in the session start modals, with their Claude Code names
+// bypassPermissions is left out on purpose; it can only be set in the working directory profile.
+// Modes above the permission mode of the working directory profile are not offered.
+var sessionPermissionModes = []struct{ value, mode, label string }{
+	{PermissionModeNormal, "default", "Normal"},
+	{"plan", "plan", "Plan mode"},
+	{"acceptEdits", "acceptEdits", "Accept edits"},
+}
+
+// option creates a plain text option
+func option(value, label string) *slack.OptionBlockObject {
+	return slack.NewOptionBlockObject(value, slack.NewTextBlockObject(slack.PlainTextType, label, false, false), nil)
+}
+
+// optionalInput creates an optional input block
+func optionalInput(blockID, label string, element slack.BlockElement) *slack.InputBlock {
+	block := slack.NewInputBlock(blockID, slack.NewTextBlockObject(slack.PlainTextType, label, false, false), nil, element)
+	block.Optional = true
+	return block
+}
+
+// modelInput creates the model select of the session start modals
+func modelInput() *slack.InputBlock {
+	defaultOption := option(OptionDefault, "Directory default")
+	options := []*slack.OptionBlockObject{defaultOption}
+	for _, model := range sessionModels {
+		options = append(options, option(model, model))
+	}
+
+	element := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, nil, ModelActionID, options...)
+	element.InitialOption = defaultOption
+	return optionalInput(ModelBlockID, "Model", element)
+}
+
+// permissionModeInput creates the permission mode select of the session start modals
+// A mode is offered when one of the profiles of the working directories to choose from allows it.
+func permissionModeInput(profiles []config.ClaudeProfileConfig) *slack.InputBlock {
+	defaultOption := option(OptionDefault, "Directory default")
+	options := []*slack.OptionBlockObject{defaultOption}
+	for _, mode := range sessionPermissionModes {
+		for _, profile := range profiles {
+			if profile.AllowsPermissionMode(mode.mode) {
+				options = append(options, option(mode.value, mode.label))
+				break
+			}
+		}
+	}
+
+	element := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, nil, PermissionModeActionID, options...)
+	element.InitialOption = defaultOption
+	return optionalInput(PermissionModeBlockID, "Permission mode", element)
+}
+
+// thinkingInput creates the extended thinking toggle of the session start modals
+func thinkingInput() *slack.InputBlock {
+	defaultOption := option(OptionDefault, "Directory default")
+	element := slack.NewRadioButtonsBlockElement(ThinkingActionID, defaultOption, option(OptionOn, "On"), option(OptionOff, "Off"))
+	element.InitialOption = defaultOption
+	return optionalInput(ThinkingBlockID, "Extended thinking", element)
+}
+
 // ApprovalRequestOptions returns message options for approval request
 func ApprovalRequestOptions(channelID, threadTS, message, requestID, userID string) []slack.MsgOption {
 	// Get tool display info for permission prompt
@@ -179,6 +436,74 @@ func ApprovalRequestOptions(channelID, threadTS, message, requestID, userID stri
 	}
 }
 
+// SessionStart creates blocks for the session start message with a Stop button
+func SessionStart(text, threadTS string) []slack.Block {
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+			nil,
+			nil,
+		),
+		slack.NewActionBlock(
+			"session_actions",
+			slack.NewButtonBlockElement(
+				"stop_session",
+				threadTS,
+				slack.NewTextBlockObject(slack.PlainTextType, "Stop", false, false),
+			).WithStyle(slack.StyleDanger),
+		),
+	}
+}
+
+// SessionResult creates blocks for the session result message with buttons for the session's changes
+// The Upload patch button value carries the git trees the patch is made from, as "<base tree>..<tree>",
+// and is left out when patch is empty. The Commit & open PR button is added when pullRequest is true.
+func SessionResult(text, threadTS, patch string, pullRequest bool) []slack.Block {
+	var buttons []slack.BlockElement
+	if pullRequest {
+		buttons = append(buttons, slack.NewButtonBlockElement(
+			"create_pull_request",
+			threadTS,
+			slack.NewTextBlockObject(slack.PlainTextType, "Commit & open PR", false, false),
+		).WithStyle(slack.StylePrimary))
+	}
+	if patch != "" {
+		buttons = append(buttons, slack.NewButtonBlockElement(
+			"upload_patch",
+			patch,
+			slack.NewTextBlockObject(slack.PlainTextType, "Upload patch", false, false),
+		))
+	}
+
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+			nil,
+			nil,
+		),
+		slack.NewActionBlock("result_actions", buttons...),
+	}
+}
+
+// ThreadArchived creates blocks for the thread archived message with a button removing the thread's worktree
+func ThreadArchived(text, threadTS string) []slack.Block {
+	return []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+			nil,
+			nil,
+		),
+		slack.NewActionBlock(
+			"worktree_actions",
+			slack.NewButtonBlockElement(
+				"remove_worktree",
+				threadTS,
+				slack.NewTextBlockObject(slack.PlainTextType, "Remove worktree", false, false),
+			).WithStyle(slack.StyleDanger),
+		),
+	}
+}
+
 // Helper functions (from handler.go)
 
 // ApprovalInfo holds structured information about an approval request
@@ -189,6 +514,8 @@ type ApprovalInfo struct {
 	Command     string
 	Description string
 	FilePath    string
+	Changes     string // Diff line counts of file-changing tools
+	Diff        string // Diff code block, empty when uploaded as a snippet
 	UserID      string
 }
 
@@ -196,8 +523,13 @@ type ApprovalInfo struct {
 func parseApprovalMessage(message string) *ApprovalInfo {
 	info := &ApprovalInfo{}
 	lines := strings.Split(message, "\n")
-	for _, line := range lines {
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
+		if line == "**Diff**:" {
+			// The diff code block spans the rest of the message and keeps its indentation
+			info.Diff = strings.Join(lines[i+1:], "\n")
+			break
+		}
 		if strings.HasPrefix(line, "**Tool**: ") {
 			info.ToolName = strings.TrimPrefix(line, "**Tool**: ")
 		} else if strings.HasPrefix(line, "**URL**: ") {
@@ -210,6 +542,8 @@ func parseApprovalMessage(message string) *ApprovalInfo {
 			info.Description = strings.TrimPrefix(line, "**Description**: ")
 		} else if strings.HasPrefix(line, "**File path**: ") {
 			info.FilePath = strings.TrimPrefix(line, "**File path**: ")
+		} else if strings.HasPrefix(line, "**Changes**: ") {
+			info.Changes = strings.TrimPrefix(line, "**Changes**: ")
 		}
 	}
 
@@ -260,15 +594,30 @@ func buildApprovalMarkdownText(info *ApprovalInfo) string {
 		text.WriteString(fmt.Sprintf("*File path:* `%s`", info.FilePath))
 	}
 
+	// Handle Edit, MultiEdit and Write tools
+	if info.Changes != "" {
+		text.WriteString(fmt.Sprintf("\n*Changes:* %s", info.Changes))
+	}
+	if info.Diff != "" {
+		text.WriteString("\n" + info.Diff)
+	}
+
 	return text.String()
 }
 
 // buildStatusMarkdownText creates markdown text for approval status
-func buildStatusMarkdownText(userID string, approved bool) string {
+func buildStatusMarkdownText(userID string, approved bool, grantScope string) string {
 	var statusEmoji, statusText string
 	if approved {
 		statusEmoji = ":white_check_mark:"
-		statusText = "Approved"
+		switch grantScope {
+		case approval.ScopeSession:
+			statusText = "Approved for this session"
+		case approval.ScopePattern:
+			statusText = "Approved command pattern for this session"
+		default:
+			statusText = "Approved"
+		}
 	} else {
 		statusEmoji = ":x:"
 		statusText = "Denied"
@@ -277,6 +626,100 @@ func buildStatusMarkdownText(userID string, approved bool) string {
 	return fmt.Sprintf("────────────────\n%s *%s* by <@%s>", statusEmoji, statusText, userID)
 }
 
+// maxEditableValueLength is the maximum length of a plain text input value in a modal
+const maxEditableValueLength = 3000
+
+// maxEditedValueDisplayLength is the maximum length of an edited value shown inline in the approval message
+const maxEditedValueDisplayLength = 200
+
+// editableInputOrder lists well-known tool input keys in the order they are shown in the edit modal
+var editableInputOrder = []string{"command", "url", "file_path", "path", "pattern", "old_string", "new_string", "content", "prompt", "description"}
+
+// multilineInputKeys are tool input keys edited with a multiline text input
+var multilineInputKeys = map[string]bool{
+	"command":    true,
+	"old_string": true,
+	"new_string": true,
+	"content":    true,
+	"prompt":     true,
+}
+
+// EditableInputKeys returns the keys of a tool input that can be edited in the edit modal
+// Only string values that fit into a plain text input are editable; other values are kept as they are.
+func EditableInputKeys(input map[string]interface{}) []string {
+	priority := make(map[string]int, len(editableInputOrder))
+	for i, key := range editableInputOrder {
+		priority[key] = i
+	}
+
+	var keys []string
+	for key, value := range input {
+		if s, ok := value.(string); ok && len(s) <= maxEditableValueLength {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		pi, iKnown := priority[keys[i]]
+		pj, jKnown := priority[keys[j]]
+		switch {
+		case iKnown && jKnown:
+			return pi < pj
+		case iKnown != jKnown:
+			return iKnown
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+
+	return keys
+}
+
+// EditInputBlockID returns the block ID of the edit modal input for a tool input key
+func EditInputBlockID(key string) string {
+	return "edit_input_" + key
+}
+
+// EditApprovalModal creates a modal pre-filled with the tool input for editing before approval
+func EditApprovalModal(metadata, toolName string, input map[string]interface{}) slack.ModalViewRequest {
+	var blockSet []slack.Block
+	blockSet = append(blockSet, slack.NewContextBlock(
+		"edit_tool_context",
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("Edit the input of `%s`. The edited input is used when the tool runs.", toolName), false, false),
+	))
+
+	for _, key := range EditableInputKeys(input) {
+		value, _ := input[key].(string)
+
+		element := slack.NewPlainTextInputBlockElement(nil, "value")
+		element.InitialValue = value
+		element.Multiline = multilineInputKeys[key]
+		element.MaxLength = maxEditableValueLength
+
+		block := slack.NewInputBlock(
+			EditInputBlockID(key),
+			slack.NewTextBlockObject(slack.PlainTextType, key, false, false),
+			nil,
+			element,
+		)
+		// Empty values may stay empty
+		block.Optional = value == ""
+		blockSet = append(blockSet, block)
+	}
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      "edit_approval_modal",
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, "Edit & Approve", false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false),
+		Close:           slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false),
+		PrivateMetadata: metadata, // Store metadata for later use
+		Blocks: slack.Blocks{
+			BlockSet: blockSet,
+		},
+	}
+}
+
 // DenyReasonModal creates a modal for entering denial reason
 func DenyReasonModal(metadata string) slack.ModalViewRequest {
 	return slack.ModalViewRequest{
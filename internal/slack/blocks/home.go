@@ -0,0 +1,141 @@
+package blocks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Action IDs of the buttons in the App Home tab
+const (
+	HomeStartSessionActionID = "home_start_session"
+	HomeStopSessionActionID  = "home_stop_session"
+)
+
+// HomeSession is a running session shown in the App Home tab
+type HomeSession struct {
+	ChannelID  string
+	ThreadTS   string
+	WorkDir    string // Name of the working directory
+	State      string
+	LastActive time.Time
+}
+
+// HomeRecentSession is a finished session shown in the App Home tab
+type HomeRecentSession struct {
+	ChannelID string
+	WorkDir   string // Name of the working directory
+	Status    string
+	Cost      float64
+	EndedAt   time.Time
+}
+
+// HomeApproval is an approval request shown in the App Home tab
+type HomeApproval struct {
+	ChannelID   string
+	ToolName    string
+	InitiatorID string
+	RequestedAt time.Time
+}
+
+// HomeData is what the App Home tab of a user shows
+type HomeData struct {
+	Active    []HomeSession
+	Approvals []HomeApproval
+	Recent    []HomeRecentSession
+}
+
+// HomeView creates the App Home tab of a user
+// Each active session has a Stop button whose value is "<channel ID>:<thread ts>".
+func HomeView(data HomeData) slack.HomeTabViewRequest {
+	blockSet := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "Claude sessions", false, false)),
+		slack.NewActionBlock(
+			"home_actions",
+			slack.NewButtonBlockElement(
+				HomeStartSessionActionID,
+				"",
+				slack.NewTextBlockObject(slack.PlainTextType, "Start a new session", false, false),
+			).WithStyle(slack.StylePrimary),
+		),
+		slack.NewDividerBlock(),
+		homeHeading("Your active sessions"),
+	}
+
+	if len(data.Active) == 0 {
+		blockSet = append(blockSet, homeEmpty("No sessions are running."))
+	}
+	for _, s := range data.Active {
+		stop := slack.NewButtonBlockElement(
+			HomeStopSessionActionID,
+			s.ChannelID+":"+s.ThreadTS,
+			slack.NewTextBlockObject(slack.PlainTextType, "Stop", false, false),
+		).WithStyle(slack.StyleDanger)
+		text := fmt.Sprintf("%s · `%s`\n%s · last active %s", channelLink(s.ChannelID), s.WorkDir, s.State, slackDate(s.LastActive))
+		blockSet = append(blockSet, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+			nil,
+			slack.NewAccessory(stop),
+		))
+	}
+
+	blockSet = append(blockSet, slack.NewDividerBlock(), homeHeading("Waiting for your approval"))
+	if len(data.Approvals) == 0 {
+		blockSet = append(blockSet, homeEmpty("No approval requests are waiting for you."))
+	}
+	for _, a := range data.Approvals {
+		text := fmt.Sprintf("`%s` in %s\nRequested %s", a.ToolName, channelLink(a.ChannelID), slackDate(a.RequestedAt))
+		if a.InitiatorID != "" {
+			text += fmt.Sprintf(" in a session of <@%s>", a.InitiatorID)
+		}
+		blockSet = append(blockSet, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+			nil,
+			nil,
+		))
+	}
+
+	blockSet = append(blockSet, slack.NewDividerBlock(), homeHeading("Recent sessions"))
+	if len(data.Recent) == 0 {
+		blockSet = append(blockSet, homeEmpty("No sessions have finished yet."))
+	}
+	for _, s := range data.Recent {
+		text := fmt.Sprintf("%s · `%s`\n%s · $%.6f USD · ended %s", channelLink(s.ChannelID), s.WorkDir, s.Status, s.Cost, slackDate(s.EndedAt))
+		blockSet = append(blockSet, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+			nil,
+			nil,
+		))
+	}
+
+	return slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: blockSet},
+	}
+}
+
+// homeHeading creates a section heading of the App Home tab
+func homeHeading(text string) slack.Block {
+	return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*"+text+"*", false, false), nil, nil)
+}
+
+// homeEmpty creates the placeholder of an empty section of the App Home tab
+func homeEmpty(text string) slack.Block {
+	return slack.NewContextBlock("", slack.NewTextBlockObject(slack.PlainTextType, text, false, false))
+}
+
+// channelLink links to a channel
+// Direct messages with the bot cannot be linked as channels, and IDs of direct messages start with "D".
+func channelLink(channelID string) string {
+	if strings.HasPrefix(channelID, "D") {
+		return "Direct message"
+	}
+	return fmt.Sprintf("<#%s>", channelID)
+}
+
+// slackDate formats a time so that Slack shows it in the timezone of the viewing user
+func slackDate(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s>", t.Unix(), t.UTC().Format("2006-01-02 15:04 UTC"))
+}
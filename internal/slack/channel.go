@@ -18,6 +18,8 @@ type ChannelInfo struct {
 	IsIM      bool
 	IsMpim    bool
 	IsPrivate bool
+	UserID    string // Counterpart user of a direct message
+	UserName  string // Display name of the counterpart user of a direct message
 }
 
 // ChannelCache caches channel information
@@ -70,6 +72,17 @@ func (c *ChannelCache) GetChannel(ctx context.Context, channelID string) (*Chann
 		IsIM:      channel.IsIM,
 		IsMpim:    channel.IsMpIM,
 		IsPrivate: channel.IsPrivate,
+		UserID:    channel.User,
+	}
+
+	// Direct messages have no name, so they are named after the user the bot talks to
+	if info.IsIM && info.UserID != "" {
+		user, err := c.client.GetUserInfoContext(ctx, info.UserID)
+		if err != nil {
+			log.Error().Err(err).Str("user_id", info.UserID).Msg("Failed to get user info")
+		} else {
+			info.UserName = userDisplayName(user)
+		}
 	}
 
 	// Update cache
@@ -89,11 +102,17 @@ func (c *ChannelCache) GetChannelName(ctx context.Context, channelID string) str
 		return channelID
 	}
 
-	// Handle different channel types
+	return channelDisplayName(info)
+}
+
+// channelDisplayName returns the name of a channel with a prefix telling its type
+func channelDisplayName(info *ChannelInfo) string {
 	switch {
 	case info.IsIM:
-		// Direct message - for now just show the channel ID
-		// TODO: Could fetch user info to show @username
+		// Direct message, named after the counterpart user when known
+		if info.UserName != "" {
+			return "@" + info.UserName
+		}
 		return "Direct Message"
 	case info.IsMpim:
 		// Multi-party IM (group DM)
@@ -109,12 +128,24 @@ func (c *ChannelCache) GetChannelName(ctx context.Context, channelID string) str
 		if info.Name != "" {
 			return "#" + info.Name
 		}
-		return channelID
+		return info.ID
 	default:
 		// Unknown type, return name if available
 		if info.Name != "" {
 			return info.Name
 		}
-		return channelID
+		return info.ID
+	}
+}
+
+// userDisplayName returns the name Slack shows for a user
+func userDisplayName(user *slack.User) string {
+	switch {
+	case user.Profile.DisplayName != "":
+		return user.Profile.DisplayName
+	case user.RealName != "":
+		return user.RealName
+	default:
+		return user.Name
 	}
 }
@@ -0,0 +1,86 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestChannelDisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		info *ChannelInfo
+		want string
+	}{
+		{
+			name: "direct message",
+			info: &ChannelInfo{ID: "D123", IsIM: true, UserID: "U123", UserName: "alice"},
+			want: "@alice",
+		},
+		{
+			name: "direct message with unknown user",
+			info: &ChannelInfo{ID: "D123", IsIM: true, UserID: "U123"},
+			want: "Direct Message",
+		},
+		{
+			name: "group direct message",
+			info: &ChannelInfo{ID: "G123", IsMpim: true},
+			want: "Group DM",
+		},
+		{
+			name: "private channel",
+			info: &ChannelInfo{ID: "C123", Name: "secret", IsChannel: true, IsPrivate: true},
+			want: "🔒secret",
+		},
+		{
+			name: "public channel",
+			info: &ChannelInfo{ID: "C123", Name: "general", IsChannel: true},
+			want: "#general",
+		},
+		{
+			name: "channel without name",
+			info: &ChannelInfo{ID: "C123", IsChannel: true},
+			want: "C123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := channelDisplayName(tt.info); got != tt.want {
+				t.Errorf("channelDisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserDisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		user *slack.User
+		want string
+	}{
+		{
+			name: "display name",
+			user: &slack.User{Name: "alice", RealName: "Alice Smith", Profile: slack.UserProfile{DisplayName: "ali"}},
+			want: "ali",
+		},
+		{
+			name: "real name",
+			user: &slack.User{Name: "alice", RealName: "Alice Smith"},
+			want: "Alice Smith",
+		},
+		{
+			name: "user name",
+			user: &slack.User{Name: "alice"},
+			want: "alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userDisplayName(tt.user); got != tt.want {
+				t.Errorf("userDisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
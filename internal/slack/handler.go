@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/slack-go/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/approval"
 	"github.com/yuya-takeyama/cc-slack/internal/config"
 	"github.com/yuya-takeyama/cc-slack/internal/mcp"
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
+	"github.com/yuya-takeyama/cc-slack/internal/slack/blocks"
 	"github.com/yuya-takeyama/cc-slack/internal/tools"
 )
 
@@ -48,6 +52,7 @@ type Handler struct {
 	signingSecret      string
 	sessionMgr         SessionManager
 	approvalResponder  ApprovalResponder
+	approvers          *approval.Approvers
 	assistantUsername  string
 	assistantIconEmoji string
 	assistantIconURL   string
@@ -56,26 +61,97 @@ type Handler struct {
 	botToken           string // Store bot token for file downloads
 	config             *config.Config
 	botUserID          string // Store bot user ID for mention detection
+	outbox             *outbox
+	homeRefresher      *homeRefresher
 }
 
 // SessionManager interface for managing Claude Code sessions
 type SessionManager interface {
 	GetSessionByThread(channelID, threadTS string) (*Session, error)
-	CreateSession(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID string) (bool, string, error)
-	SendMessage(sessionID, message string) error
+	CreateSession(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID, messageTS string, opts SessionOptions) (bool, string, error)
+	SendMessage(sessionID, userID, messageTS, message string) error
+	GetLatestSessionByChannel(channelID string) (*Session, error)
+	ListSessionsByChannel(channelID string) ([]*Session, error)
+	ListSessionsByUser(userID string) ([]*Session, error)
+	ListRecentSessionsByUser(userID string, limit int) ([]*SessionSummary, error)
+	GetThreadStatus(channelID, threadTS string) (*ThreadStatus, error)
+	StopSession(channelID, threadTS, userID string) error
+	ArchiveThread(channelID, threadTS string) (*Worktree, error)
+	RemoveWorktree(channelID, threadTS string) (*Worktree, error)
+	GitPatch(channelID, threadTS, baseTree, tree string) (string, error)
+	CreatePullRequest(channelID, threadTS string) (*PullRequest, error)
 }
 
 // ApprovalResponder interface for sending approval responses
 type ApprovalResponder interface {
 	SendApprovalResponse(requestID string, response mcp.ApprovalResponse) error
+	ApprovalInitiator(requestID string) (string, bool)
+	ApprovalInput(requestID string) (string, map[string]interface{}, bool)
+	RecordRejectedApprover(requestID, userID string)
+	PendingApprovals() []mcp.PendingApproval
 }
 
 // Session represents a Claude Code session
 type Session struct {
+	SessionID  string
+	ChannelID  string
+	ThreadTS   string
+	WorkDir    string
+	UserID     string // Slack user who started the session
+	Running    bool   // Claude is working on a prompt, otherwise it waits for the next message
+	LastActive time.Time
+}
+
+// SessionSummary describes a finished session
+type SessionSummary struct {
 	SessionID string
 	ChannelID string
 	ThreadTS  string
 	WorkDir   string
+	Status    string
+	Cost      float64 // USD
+	EndedAt   time.Time
+}
+
+// States of a thread while a Claude process runs for it
+const (
+	ThreadStateRunning = "running"
+	ThreadStateWaiting = "waiting for input"
+)
+
+// ThreadStatus describes the sessions that have run in a thread
+type ThreadStatus struct {
+	ChannelID string
+	ThreadTS  string
+	WorkDir   string
+	SessionID string // Latest session of the thread
+	UserID    string // User who started the latest session, empty when unknown
+	State     string // running, waiting for input, or the final status of the latest session
+	Active    bool   // A Claude process is running for the thread
+	Sessions  int
+	TotalCost float64 // Cost of the finished sessions in USD
+}
+
+// SessionOptions are the Claude Code options chosen when starting a session
+// Empty values leave the choice to the working directory profile.
+type SessionOptions struct {
+	Model            string
+	PermissionMode   string
+	ExtendedThinking *bool
+}
+
+// Worktree represents the git worktree a thread's sessions run in
+type Worktree struct {
+	Path   string
+	Branch string
+}
+
+// PullRequest represents a pull request opened from a thread's worktree
+type PullRequest struct {
+	URL       string
+	Branch    string
+	Created   bool // false when the changes were pushed to the pull request opened before
+	Committed bool // false when the worktree had no uncommitted changes
 }
 
 // NewHandler creates a new Slack handler
@@ -87,6 +163,8 @@ func NewHandler(cfg *config.Config, sessionMgr SessionManager, botUserID string)
 		botToken:      cfg.Slack.BotToken,
 		config:        cfg,
 		botUserID:     botUserID,
+		outbox:        newOutbox(),
+		homeRefresher: newHomeRefresher(),
 	}
 
 	// Apply configuration
@@ -123,6 +201,9 @@ func (h *Handler) Configure() {
 	// Apply file upload options
 	h.fileUploadEnabled = h.config.Slack.FileUpload.Enabled
 	h.imagesDir = h.config.Slack.FileUpload.ImagesDir
+
+	// Apply approver policy
+	h.approvers = approval.NewApprovers(h.config.Approval)
 }
 
 // GetClient returns the Slack client
@@ -169,7 +250,8 @@ func (h *Handler) formatWorkingDirectory(workDir string) string {
 }
 
 // createThreadAndStartSession creates a new Slack thread and starts a Claude session
-func (h *Handler) createThreadAndStartSession(channelID, workDir, prompt, userID string) {
+// Images of imageFileIDs are downloaded for the new thread and attached to the prompt.
+func (h *Handler) createThreadAndStartSession(channelID, workDir, prompt, userID string, opts SessionOptions, imageFileIDs []string) {
 	// Create initial message with working directory information
 	var initialText strings.Builder
 	initialText.WriteString("🚀 Starting Claude Code session")
@@ -194,9 +276,13 @@ func (h *Handler) createThreadAndStartSession(channelID, workDir, prompt, userID
 		return
 	}
 
+	if len(imageFileIDs) > 0 {
+		prompt = h.appendImagePaths(prompt, h.downloadImageFiles(threadTS, h.getFiles(imageFileIDs)))
+	}
+
 	// Create session with the selected working directory
 	ctx := context.Background()
-	resumed, previousSessionID, err := h.sessionMgr.CreateSession(ctx, channelID, threadTS, workDir, prompt, userID)
+	resumed, previousSessionID, err := h.sessionMgr.CreateSession(ctx, channelID, threadTS, workDir, prompt, userID, "", opts)
 	if err != nil {
 		h.client.PostMessage(
 			channelID,
@@ -217,3 +303,113 @@ func (h *Handler) createThreadAndStartSession(channelID, workDir, prompt, userID
 		// Log new session creation
 	}
 }
+
+// mayControlSession reports whether a user may act on a session started by initiatorID,
+// such as stopping it or pushing its changes. Those are the users who may answer its approval requests.
+func (h *Handler) mayControlSession(userID, initiatorID string) bool {
+	authorized, err := h.approvers.IsAuthorized(userID, initiatorID, h.userGroupMembers)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("user_id", userID).
+			Str("initiator_id", initiatorID).
+			Msg("failed to check approver")
+	}
+	return authorized
+}
+
+// stopSession interrupts the running session in a thread on behalf of a user.
+// The summary is posted by the session manager once the session has stopped.
+func (h *Handler) stopSession(channelID, threadTS, userID string) {
+	if session, err := h.sessionMgr.GetSessionByThread(channelID, threadTS); err == nil && session != nil && !h.mayControlSession(userID, session.UserID) {
+		h.postEphemeralToThread(channelID, threadTS, userID, stopNotAllowedText(session.UserID))
+		return
+	}
+
+	if err := h.sessionMgr.StopSession(channelID, threadTS, userID); err != nil {
+		log.Error().
+			Err(err).
+			Str("channel_id", channelID).
+			Str("thread_ts", threadTS).
+			Msg("failed to stop session")
+
+		_, err := h.client.PostEphemeral(
+			channelID,
+			userID,
+			slack.MsgOptionText("There is no running session to stop in this thread.", false),
+			slack.MsgOptionTS(threadTS),
+		)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to post stop failure message")
+		}
+	}
+}
+
+// stopNotAllowedText tells a user that only others may stop a session started by initiatorID
+func stopNotAllowedText(initiatorID string) string {
+	text := "You are not allowed to stop this session."
+	if initiatorID != "" {
+		text += fmt.Sprintf(" Please ask <@%s> to stop it.", initiatorID)
+	}
+	return text
+}
+
+// archiveThread archives a thread on behalf of a user who may control its sessions.
+// When the thread has a worktree, a button to remove it is offered instead of removing it right away.
+func (h *Handler) archiveThread(channelID, threadTS, userID string) {
+	if !h.authorizeThreadAction(channelID, threadTS, userID, "Failed to archive thread", "archive this thread") {
+		return
+	}
+
+	wt, err := h.sessionMgr.ArchiveThread(channelID, threadTS)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("channel_id", channelID).
+			Str("thread_ts", threadTS).
+			Msg("failed to archive thread")
+
+		h.postEphemeralToThread(channelID, threadTS, userID, fmt.Sprintf("Failed to archive thread: %v", err))
+		return
+	}
+
+	if wt == nil {
+		if err := h.PostToThread(channelID, threadTS, messages.FormatThreadArchivedMessage("", "")); err != nil {
+			log.Error().Err(err).Msg("failed to post thread archived message")
+		}
+		return
+	}
+
+	text := messages.FormatThreadArchivedMessage(wt.Path, wt.Branch)
+	err = h.queueMessage(channelID, "", mergeNone, text, h.postMessageFunc(
+		channelID,
+		slack.MsgOptionTS(threadTS),
+		slack.MsgOptionBlocks(blocks.ThreadArchived(text, threadTS)...),
+	))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to post thread archived message")
+	}
+}
+
+// postEphemeralToThread posts a message in a thread that only the given user can see
+func (h *Handler) postEphemeralToThread(channelID, threadTS, userID, text string) {
+	_, err := h.client.PostEphemeral(
+		channelID,
+		userID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionTS(threadTS),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to post ephemeral message")
+	}
+}
+
+// isStopCommand reports whether a thread message asks to stop the running session
+func isStopCommand(text string) bool {
+	return strings.EqualFold(strings.TrimSpace(text), "stop")
+}
+
+// isArchiveCommand reports whether a thread message asks to archive the thread
+func isArchiveCommand(text string) bool {
+	return strings.EqualFold(strings.TrimSpace(text), "archive")
+}
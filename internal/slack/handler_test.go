@@ -2,9 +2,22 @@ package slack
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/yuya-takeyama/cc-slack/internal/approval"
 	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/mcp"
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
+	"github.com/yuya-takeyama/cc-slack/internal/slack/blocks"
 )
 
 func TestRemoveBotMentionFromText(t *testing.T) {
@@ -112,11 +125,22 @@ func TestRemoveBotMentionFromText(t *testing.T) {
 
 // MockSessionManager implements SessionManager for testing
 type MockSessionManager struct {
+	mu                       sync.Mutex
 	createSessionCalls       []createSessionCall
 	sendMessageCalls         []sendMessageCall
 	getSessionByThreadCalls  []getSessionByThreadCall
 	getSessionByThreadReturn *Session
 	getSessionByThreadError  error
+	stopSessionCalls         []stopSessionCall
+	latestSessionReturn      *Session
+	channelSessionsReturn    []*Session
+	threadStatusReturn       *ThreadStatus
+	userSessionsReturn       []*Session
+	recentSessionsReturn     []*SessionSummary
+	sendMessageBlock         chan struct{} // SendMessage waits for it to be closed when set
+	createPullRequestCalls   int
+	archiveThreadCalls       int
+	removeWorktreeCalls      int
 }
 
 type createSessionCall struct {
@@ -124,6 +148,7 @@ type createSessionCall struct {
 	threadTS      string
 	workDir       string
 	initialPrompt string
+	userID        string
 }
 
 type sendMessageCall struct {
@@ -131,12 +156,20 @@ type sendMessageCall struct {
 	message   string
 }
 
+type stopSessionCall struct {
+	channelID string
+	threadTS  string
+	userID    string
+}
+
 type getSessionByThreadCall struct {
 	channelID string
 	threadTS  string
 }
 
 func (m *MockSessionManager) GetSessionByThread(channelID, threadTS string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.getSessionByThreadCalls = append(m.getSessionByThreadCalls, getSessionByThreadCall{
 		channelID: channelID,
 		threadTS:  threadTS,
@@ -144,17 +177,25 @@ func (m *MockSessionManager) GetSessionByThread(channelID, threadTS string) (*Se
 	return m.getSessionByThreadReturn, m.getSessionByThreadError
 }
 
-func (m *MockSessionManager) CreateSession(ctx context.Context, channelID, threadTS, workDir, initialPrompt string) (bool, string, error) {
+func (m *MockSessionManager) CreateSession(ctx context.Context, channelID, threadTS, workDir, initialPrompt, userID, messageTS string, opts SessionOptions) (bool, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.createSessionCalls = append(m.createSessionCalls, createSessionCall{
 		channelID:     channelID,
 		threadTS:      threadTS,
 		workDir:       workDir,
 		initialPrompt: initialPrompt,
+		userID:        userID,
 	})
 	return false, "", nil
 }
 
-func (m *MockSessionManager) SendMessage(sessionID, message string) error {
+func (m *MockSessionManager) SendMessage(sessionID, userID, messageTS, message string) error {
+	if m.sendMessageBlock != nil {
+		<-m.sendMessageBlock
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.sendMessageCalls = append(m.sendMessageCalls, sendMessageCall{
 		sessionID: sessionID,
 		message:   message,
@@ -162,12 +203,108 @@ func (m *MockSessionManager) SendMessage(sessionID, message string) error {
 	return nil
 }
 
+func (m *MockSessionManager) GetLatestSessionByChannel(channelID string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.latestSessionReturn == nil {
+		return nil, fmt.Errorf("no active session in channel %s", channelID)
+	}
+	return m.latestSessionReturn, nil
+}
+
+func (m *MockSessionManager) ListSessionsByChannel(channelID string) ([]*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.channelSessionsReturn, nil
+}
+
+func (m *MockSessionManager) ListSessionsByUser(userID string) ([]*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.userSessionsReturn, nil
+}
+
+func (m *MockSessionManager) ListRecentSessionsByUser(userID string, limit int) ([]*SessionSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.recentSessionsReturn, nil
+}
+
+func (m *MockSessionManager) GetThreadStatus(channelID, threadTS string) (*ThreadStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.threadStatusReturn == nil {
+		return nil, fmt.Errorf("no session has run in this thread")
+	}
+	return m.threadStatusReturn, nil
+}
+
+func (m *MockSessionManager) StopSession(channelID, threadTS, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopSessionCalls = append(m.stopSessionCalls, stopSessionCall{
+		channelID: channelID,
+		threadTS:  threadTS,
+		userID:    userID,
+	})
+	return nil
+}
+
+func (m *MockSessionManager) ArchiveThread(channelID, threadTS string) (*Worktree, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.archiveThreadCalls++
+	return nil, nil
+}
+
+func (m *MockSessionManager) RemoveWorktree(channelID, threadTS string) (*Worktree, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeWorktreeCalls++
+	return nil, fmt.Errorf("thread %s has no worktree", threadTS)
+}
+
+func (m *MockSessionManager) GitPatch(channelID, threadTS, baseTree, tree string) (string, error) {
+	return "", nil
+}
+
+func (m *MockSessionManager) CreatePullRequest(channelID, threadTS string) (*PullRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.createPullRequestCalls++
+	return nil, fmt.Errorf("pull requests are not configured")
+}
+
+// MockApprovalResponder implements ApprovalResponder for testing
+type MockApprovalResponder struct {
+	pendingApprovals []mcp.PendingApproval
+}
+
+func (m *MockApprovalResponder) SendApprovalResponse(requestID string, response mcp.ApprovalResponse) error {
+	return nil
+}
+
+func (m *MockApprovalResponder) ApprovalInitiator(requestID string) (string, bool) {
+	return "", false
+}
+
+func (m *MockApprovalResponder) ApprovalInput(requestID string) (string, map[string]interface{}, bool) {
+	return "", nil, false
+}
+
+func (m *MockApprovalResponder) RecordRejectedApprover(requestID, userID string) {}
+
+func (m *MockApprovalResponder) PendingApprovals() []mcp.PendingApproval {
+	return m.pendingApprovals
+}
+
 // createTestConfig creates a minimal config for testing
 func createTestConfig() *config.Config {
 	return &config.Config{
 		Slack: config.SlackConfig{
-			BotToken:      "test-token",
-			SigningSecret: "test-secret",
+			BotToken:         "test-token",
+			SigningSecret:    "test-secret",
+			SlashCommandName: "/cc",
 			MessageFilter: config.MessageFilterConfig{
 				Enabled:        true,
 				RequireMention: true,
@@ -246,3 +383,976 @@ func TestDetermineWorkDir(t *testing.T) {
 		})
 	}
 }
+
+func TestIsStopCommand(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "stop", want: true},
+		{text: "  Stop\n", want: true},
+		{text: "STOP", want: true},
+		{text: "stop the server", want: false},
+		{text: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := isStopCommand(tt.text); got != tt.want {
+				t.Errorf("isStopCommand(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsArchiveCommand(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "archive", want: true},
+		{text: " Archive\n", want: true},
+		{text: "archive the logs", want: false},
+		{text: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := isArchiveCommand(tt.text); got != tt.want {
+				t.Errorf("isArchiveCommand(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleThreadMessageEventStop(t *testing.T) {
+	sessionMgr := &MockSessionManager{
+		getSessionByThreadReturn: &Session{SessionID: "session-1", ChannelID: "C123", ThreadTS: "1700000000.000100"},
+	}
+	handler := &Handler{
+		config:     createTestConfig(),
+		sessionMgr: sessionMgr,
+	}
+
+	handler.handleThreadMessageEvent(&slackevents.MessageEvent{
+		Channel:         "C123",
+		User:            "U456",
+		ThreadTimeStamp: "1700000000.000100",
+	}, "stop")
+
+	if len(sessionMgr.sendMessageCalls) != 0 {
+		t.Errorf("SendMessage called %d times, want 0", len(sessionMgr.sendMessageCalls))
+	}
+	if len(sessionMgr.stopSessionCalls) != 1 {
+		t.Fatalf("StopSession called %d times, want 1", len(sessionMgr.stopSessionCalls))
+	}
+	want := stopSessionCall{channelID: "C123", threadTS: "1700000000.000100", userID: "U456"}
+	if got := sessionMgr.stopSessionCalls[0]; got != want {
+		t.Errorf("StopSession(%+v), want %+v", got, want)
+	}
+}
+
+func TestHandleThreadMessageEventStopByOtherUser(t *testing.T) {
+	client, calls := newTestSlackClient(t)
+	sessionMgr := &MockSessionManager{
+		getSessionByThreadReturn: &Session{SessionID: "session-1", ChannelID: "C123", ThreadTS: "1700000000.000100", UserID: "U_OWNER"},
+	}
+	handler := &Handler{
+		client:     client,
+		config:     createTestConfig(),
+		sessionMgr: sessionMgr,
+		approvers:  approval.NewApprovers(config.ApprovalConfig{Approvers: config.ApproversConfig{Mode: approval.ApproversInitiator}}),
+	}
+
+	handler.handleThreadMessageEvent(&slackevents.MessageEvent{
+		Channel:         "C123",
+		User:            "U456",
+		ThreadTimeStamp: "1700000000.000100",
+	}, "stop")
+
+	if len(sessionMgr.stopSessionCalls) != 0 {
+		t.Errorf("StopSession called %d times, want 0", len(sessionMgr.stopSessionCalls))
+	}
+	want := []string{"chat.postEphemeral You are not allowed to stop this session. Please ask <@U_OWNER> to stop it."}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Slack API calls = %q, want %q", got, want)
+	}
+}
+
+func TestHandleStopSubcommand(t *testing.T) {
+	running := &Session{SessionID: "session-1", ChannelID: "C123", ThreadTS: "1700000000.000100", UserID: "U_OWNER"}
+
+	tests := []struct {
+		name          string
+		text          string
+		latestSession *Session
+		threadSession *Session
+		approvers     config.ApproversConfig
+		wantText      string
+		wantStopCalls int
+	}{
+		{
+			name:          "running session",
+			text:          "stop",
+			latestSession: running,
+			wantText:      "Stopping session `session-1`",
+			wantStopCalls: 1,
+		},
+		{
+			name:          "no running session",
+			text:          "stop",
+			wantText:      "There is no running session to stop in this channel.",
+			wantStopCalls: 0,
+		},
+		{
+			name:          "session ID",
+			text:          "Stop session-1",
+			wantText:      "Stopping session `session-1`",
+			wantStopCalls: 1,
+		},
+		{
+			name:          "unknown session ID",
+			text:          "stop session-2",
+			wantText:      "There is no running session `session-2` in this channel.",
+			wantStopCalls: 0,
+		},
+		{
+			name:          "thread link",
+			text:          "stop https://example.slack.com/archives/C123/p1700000000000100",
+			threadSession: running,
+			wantText:      "Stopping session `session-1`",
+			wantStopCalls: 1,
+		},
+		{
+			name:          "thread link to another channel",
+			text:          "stop https://example.slack.com/archives/CPRIVATE/p1700000000000100",
+			threadSession: &Session{SessionID: "session-9", ChannelID: "CPRIVATE", ThreadTS: "1700000000.000100"},
+			wantText:      "Only sessions in this channel can be stopped here.",
+			wantStopCalls: 0,
+		},
+		{
+			name:          "session of another user",
+			text:          "stop",
+			latestSession: running,
+			approvers:     config.ApproversConfig{Mode: approval.ApproversInitiator},
+			wantText:      "You are not allowed to stop this session. Please ask <@U_OWNER> to stop it.",
+			wantStopCalls: 0,
+		},
+		{
+			name:          "session of another user stopped by an admin",
+			text:          "stop",
+			latestSession: running,
+			approvers:     config.ApproversConfig{Mode: approval.ApproversInitiatorAndAdmins, Admins: []string{"U456"}},
+			wantText:      "Stopping session `session-1`",
+			wantStopCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionMgr := &MockSessionManager{
+				latestSessionReturn:      tt.latestSession,
+				channelSessionsReturn:    []*Session{running},
+				getSessionByThreadReturn: tt.threadSession,
+			}
+			handler := &Handler{
+				config:     createTestConfig(),
+				sessionMgr: sessionMgr,
+				approvers:  approval.NewApprovers(config.ApprovalConfig{Approvers: tt.approvers}),
+			}
+
+			got := handler.handleSlashCommand(slack.SlashCommand{
+				Command:   "/cc",
+				Text:      tt.text,
+				UserID:    "U456",
+				ChannelID: "C123",
+			})
+			if got != tt.wantText {
+				t.Errorf("handleSlashCommand() = %q, want %q", got, tt.wantText)
+			}
+			if len(sessionMgr.stopSessionCalls) != tt.wantStopCalls {
+				t.Errorf("StopSession called %d times, want %d", len(sessionMgr.stopSessionCalls), tt.wantStopCalls)
+			}
+		})
+	}
+}
+
+func TestParseSubcommand(t *testing.T) {
+	tests := []struct {
+		text           string
+		wantSubcommand string
+		wantArgs       string
+	}{
+		{text: "", wantSubcommand: "", wantArgs: ""},
+		{text: "  LIST ", wantSubcommand: "list", wantArgs: ""},
+		{text: "resume https://example.slack.com/archives/C123/p1700000000000100  keep going", wantSubcommand: "resume", wantArgs: "https://example.slack.com/archives/C123/p1700000000000100  keep going"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			subcommand, args := parseSubcommand(tt.text)
+			if subcommand != tt.wantSubcommand || args != tt.wantArgs {
+				t.Errorf("parseSubcommand(%q) = %q, %q, want %q, %q", tt.text, subcommand, args, tt.wantSubcommand, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseThreadLink(t *testing.T) {
+	tests := []struct {
+		link          string
+		wantChannelID string
+		wantThreadTS  string
+		wantOK        bool
+	}{
+		{
+			link:          "https://example.slack.com/archives/C123/p1700000000000100",
+			wantChannelID: "C123",
+			wantThreadTS:  "1700000000.000100",
+			wantOK:        true,
+		},
+		{
+			link:          "<https://example.slack.com/archives/C123/p1700000000000200?thread_ts=1700000000.000100&cid=C123>",
+			wantChannelID: "C123",
+			wantThreadTS:  "1700000000.000100",
+			wantOK:        true,
+		},
+		{
+			link:          "<https://example.slack.com/archives/C123/p1700000000000100|thread>",
+			wantChannelID: "C123",
+			wantThreadTS:  "1700000000.000100",
+			wantOK:        true,
+		},
+		{link: "session-1"},
+		{link: "https://example.slack.com/archives/C123"},
+		{link: "https://example.slack.com/archives/C123/pabc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			channelID, threadTS, ok := parseThreadLink(tt.link)
+			if channelID != tt.wantChannelID || threadTS != tt.wantThreadTS || ok != tt.wantOK {
+				t.Errorf("parseThreadLink(%q) = %q, %q, %v, want %q, %q, %v",
+					tt.link, channelID, threadTS, ok, tt.wantChannelID, tt.wantThreadTS, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHandleSlashSubcommands(t *testing.T) {
+	cfg := createTestConfigWithWorkingDirs([]config.WorkingDirectoryConfig{
+		{Name: "api", Path: "/src/api"},
+		{Name: "web", Path: "/src/web"},
+	})
+	status := &ThreadStatus{
+		ChannelID: "C123",
+		ThreadTS:  "1700000000.000100",
+		WorkDir:   "/src/api",
+		SessionID: "session-1",
+		State:     "completed",
+		Sessions:  2,
+		TotalCost: 0.25,
+	}
+
+	tests := []struct {
+		name            string
+		text            string
+		channelSessions []*Session
+		threadStatus    *ThreadStatus
+		want            string
+	}{
+		{
+			name: "list without sessions",
+			text: "list",
+			want: "There are no active sessions in this channel.",
+		},
+		{
+			name: "list",
+			text: "list",
+			channelSessions: []*Session{
+				{SessionID: "session-1", WorkDir: "/src/api", Running: true, LastActive: time.Now()},
+			},
+			want: "*Active sessions in this channel (1)*\n• `session-1` in `/src/api`: running, last active 0s ago",
+		},
+		{
+			name:         "status of a thread",
+			text:         "status https://example.slack.com/archives/C123/p1700000000000100",
+			threadStatus: status,
+			want: "*Thread status*\n" +
+				"Session ID: `session-1`\n" +
+				"Working directory: `/src/api`\n" +
+				"State: completed\n" +
+				"Sessions: 2\n" +
+				"Cost so far: $0.250000 USD",
+		},
+		{
+			name:         "status of a thread in another channel",
+			text:         "status https://example.slack.com/archives/C999/p1700000000000100",
+			threadStatus: status,
+			want:         "Only threads in this channel can be looked up here.",
+		},
+		{
+			name: "status without active session",
+			text: "status",
+			want: "There is no active session in this channel. Pass a thread link to see the status of a finished thread.",
+		},
+		{
+			name: "resume without link",
+			text: "resume",
+			want: "Usage: `/cc resume <thread link> [prompt]`",
+		},
+		{
+			name: "resume thread in another channel",
+			text: "resume https://example.slack.com/archives/C999/p1700000000000100",
+			want: "Only threads in this channel can be resumed here.",
+		},
+		{
+			name:         "resume running thread",
+			text:         "resume https://example.slack.com/archives/C123/p1700000000000100",
+			threadStatus: &ThreadStatus{SessionID: "session-1", Active: true},
+			want:         "Session `session-1` is already running in that thread.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionMgr := &MockSessionManager{
+				channelSessionsReturn: tt.channelSessions,
+				threadStatusReturn:    tt.threadStatus,
+			}
+			handler := &Handler{
+				config:     cfg,
+				sessionMgr: sessionMgr,
+			}
+
+			got := handler.handleSlashCommand(slack.SlashCommand{
+				Command:   "/cc",
+				Text:      tt.text,
+				UserID:    "U456",
+				ChannelID: "C123",
+			})
+			if got != tt.want {
+				t.Errorf("handleSlashCommand(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if len(sessionMgr.createSessionCalls) != 0 {
+				t.Errorf("CreateSession called %d times, want 0", len(sessionMgr.createSessionCalls))
+			}
+		})
+	}
+}
+
+func TestSlashCommandPrefill(t *testing.T) {
+	multi := createTestConfigWithWorkingDirs([]config.WorkingDirectoryConfig{
+		{Name: "api", Path: "/src/api"},
+		{Name: "web", Path: "/src/web"},
+	})
+	single := createTestConfig()
+	single.WorkingDirFlags = []string{"/src/api"}
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		text string
+		want blocks.SessionStartPrefill
+	}{
+		{
+			name: "prompt",
+			cfg:  multi,
+			text: " fix the flaky test ",
+			want: blocks.SessionStartPrefill{Prompt: "fix the flaky test"},
+		},
+		{
+			name: "working directory",
+			cfg:  multi,
+			text: "Web",
+			want: blocks.SessionStartPrefill{WorkDir: "/src/web"},
+		},
+		{
+			name: "working directory and prompt",
+			cfg:  multi,
+			text: "api fix the flaky test",
+			want: blocks.SessionStartPrefill{WorkDir: "/src/api", Prompt: "fix the flaky test"},
+		},
+		{
+			name: "single directory mode",
+			cfg:  single,
+			text: "api fix the flaky test",
+			want: blocks.SessionStartPrefill{Prompt: "api fix the flaky test"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &Handler{config: tt.cfg}
+			if got := handler.slashCommandPrefill(tt.text); got != tt.want {
+				t.Errorf("slashCommandPrefill(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionModalMetadata(t *testing.T) {
+	metadata := sessionModalMetadata{ChannelID: "C123", ImageFileIDs: []string{"F1", "F2"}}
+	if got := parseSessionModalMetadata(metadata.encode()); !reflect.DeepEqual(got, metadata) {
+		t.Errorf("parseSessionModalMetadata(encode()) = %+v, want %+v", got, metadata)
+	}
+
+	// Modals opened before the metadata was JSON hold the channel ID only
+	if got := parseSessionModalMetadata("C123"); !reflect.DeepEqual(got, sessionModalMetadata{ChannelID: "C123"}) {
+		t.Errorf("parseSessionModalMetadata(%q) = %+v", "C123", got)
+	}
+}
+
+func TestMessageShortcutPrompt(t *testing.T) {
+	const permalink = "https://example.slack.com/archives/C123/p1700000000000100"
+
+	tests := []struct {
+		name      string
+		text      string
+		permalink string
+		want      string
+	}{
+		{
+			name:      "text and link",
+			text:      "The login page is broken\n",
+			permalink: permalink,
+			want:      "The login page is broken\n\nSlack message: " + permalink,
+		},
+		{
+			name:      "image only message",
+			permalink: permalink,
+			want:      "Slack message: " + permalink,
+		},
+		{
+			name: "without link",
+			text: "The login page is broken",
+			want: "The login page is broken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageShortcutPrompt(tt.text, tt.permalink); got != tt.want {
+				t.Errorf("messageShortcutPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleSlashCommandNames(t *testing.T) {
+	cfg := createTestConfigWithWorkingDirs([]config.WorkingDirectoryConfig{
+		{Name: "api", Path: "/src/api"},
+		{Name: "web", Path: "/src/web"},
+	})
+	cfg.Slack.SlashCommandName = "/claude"
+	cfg.Slack.SlashCommands = []config.SlashCommandConfig{
+		{Command: "/claude-docs", WorkingDir: "docs"},
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		text    string
+		want    string
+	}{
+		{
+			name:    "configured command name",
+			command: "/claude",
+			text:    "list",
+			want:    "There are no active sessions in this channel.",
+		},
+		{
+			name:    "help uses the command name",
+			command: "/claude",
+			text:    "resume",
+			want:    "Usage: `/claude resume <thread link> [prompt]`",
+		},
+		{
+			name:    "default command name of another instance",
+			command: "/cc",
+			text:    "list",
+			want:    "Unknown command",
+		},
+		{
+			name:    "working directory command without its directory",
+			command: "/claude-docs",
+			text:    "fix the typo",
+			want:    "Working directory `docs` of /claude-docs is not configured.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &Handler{
+				config:     cfg,
+				sessionMgr: &MockSessionManager{},
+			}
+
+			got := handler.handleSlashCommand(slack.SlashCommand{
+				Command:   tt.command,
+				Text:      tt.text,
+				UserID:    "U456",
+				ChannelID: "C123",
+			})
+			if got != tt.want {
+				t.Errorf("handleSlashCommand(%q, %q) = %q, want %q", tt.command, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditedApprovalInput(t *testing.T) {
+	input := map[string]interface{}{
+		"command":     "rm -rf ./build",
+		"description": "Clean build output",
+		"timeout":     float64(60000),
+	}
+	values := map[string]map[string]slack.BlockAction{
+		"edit_input_command":     {"value": {Value: "rm -rf ./build/tmp"}},
+		"edit_input_description": {"value": {Value: "Clean build output"}},
+	}
+
+	updatedInput, editedKeys := editedApprovalInput(input, values)
+
+	if got := updatedInput["command"]; got != "rm -rf ./build/tmp" {
+		t.Errorf("command = %v, want edited command", got)
+	}
+	if got := updatedInput["description"]; got != "Clean build output" {
+		t.Errorf("description = %v, want original description", got)
+	}
+	if got := updatedInput["timeout"]; got != float64(60000) {
+		t.Errorf("timeout = %v, want non-string value to be kept", got)
+	}
+	if len(editedKeys) != 1 || editedKeys[0] != "command" {
+		t.Errorf("editedKeys = %v, want [command]", editedKeys)
+	}
+	if input["command"] != "rm -rf ./build" {
+		t.Errorf("original input was modified: %v", input["command"])
+	}
+}
+
+func TestHomeData(t *testing.T) {
+	requestedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	endedAt := requestedAt.Add(-time.Hour)
+	pending := []mcp.PendingApproval{
+		{RequestID: "r1", ToolName: "Bash", ChannelID: "C1", UserID: "U_ME", RequestedAt: requestedAt},
+		{RequestID: "r2", ToolName: "Write", ChannelID: "C2", UserID: "U_OTHER", RequestedAt: requestedAt},
+	}
+
+	tests := []struct {
+		name          string
+		approvers     config.ApproversConfig
+		wantApprovals []blocks.HomeApproval
+	}{
+		{
+			name: "anyone may answer",
+			wantApprovals: []blocks.HomeApproval{
+				{ChannelID: "C1", ToolName: "Bash", RequestedAt: requestedAt},
+			},
+		},
+		{
+			name:      "admin answers the requests of other sessions",
+			approvers: config.ApproversConfig{Mode: approval.ApproversInitiatorAndAdmins, Admins: []string{"U_ME"}},
+			wantApprovals: []blocks.HomeApproval{
+				{ChannelID: "C1", ToolName: "Bash", RequestedAt: requestedAt},
+				{ChannelID: "C2", ToolName: "Write", InitiatorID: "U_OTHER", RequestedAt: requestedAt},
+			},
+		},
+		{
+			name:      "initiator only",
+			approvers: config.ApproversConfig{Mode: approval.ApproversInitiator},
+			wantApprovals: []blocks.HomeApproval{
+				{ChannelID: "C1", ToolName: "Bash", RequestedAt: requestedAt},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfigWithWorkingDirs([]config.WorkingDirectoryConfig{
+				{Name: "api", Path: "/src/api"},
+			})
+			sessionMgr := &MockSessionManager{
+				userSessionsReturn: []*Session{
+					{SessionID: "s1", ChannelID: "C1", ThreadTS: "1.1", WorkDir: "/src/api", Running: true, LastActive: requestedAt},
+				},
+				recentSessionsReturn: []*SessionSummary{
+					{SessionID: "s0", ChannelID: "C1", ThreadTS: "1.0", WorkDir: "/src/other", Status: "completed", Cost: 0.5, EndedAt: endedAt},
+				},
+			}
+			h := &Handler{
+				sessionMgr:        sessionMgr,
+				config:            cfg,
+				approvalResponder: &MockApprovalResponder{pendingApprovals: pending},
+				approvers:         approval.NewApprovers(config.ApprovalConfig{Approvers: tt.approvers}),
+			}
+
+			got, err := h.homeData("U_ME")
+			if err != nil {
+				t.Fatalf("homeData() error = %v", err)
+			}
+			want := blocks.HomeData{
+				Active: []blocks.HomeSession{
+					{ChannelID: "C1", ThreadTS: "1.1", WorkDir: "api", State: ThreadStateRunning, LastActive: requestedAt},
+				},
+				Approvals: tt.wantApprovals,
+				Recent: []blocks.HomeRecentSession{
+					{ChannelID: "C1", WorkDir: "other", Status: "completed", Cost: 0.5, EndedAt: endedAt},
+				},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("homeData() = %+v, want %+v", got, want)
+			}
+
+			view := blocks.HomeView(got)
+			if view.Type != slack.VTHomeTab {
+				t.Errorf("HomeView() type = %q, want %q", view.Type, slack.VTHomeTab)
+			}
+		})
+	}
+}
+
+func TestHomeStopSessionAction(t *testing.T) {
+	sessionMgr := &MockSessionManager{}
+	h := &Handler{sessionMgr: sessionMgr, config: createTestConfig()}
+
+	payload := &slack.InteractionCallback{User: slack.User{ID: "U_ME"}}
+	h.handleHomeStopSessionAction(payload, &slack.BlockAction{Value: "C1:1700000000.000100"})
+	h.handleHomeStopSessionAction(payload, &slack.BlockAction{Value: "invalid"})
+
+	want := []stopSessionCall{{channelID: "C1", threadTS: "1700000000.000100", userID: "U_ME"}}
+	if !reflect.DeepEqual(sessionMgr.stopSessionCalls, want) {
+		t.Errorf("StopSession calls = %+v, want %+v", sessionMgr.stopSessionCalls, want)
+	}
+}
+
+func TestShouldProcessMessageDirectMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *slackevents.MessageEvent
+		want  bool
+	}{
+		{
+			name:  "channel message without mention",
+			event: &slackevents.MessageEvent{ChannelType: "channel", Text: "fix the tests"},
+			want:  false,
+		},
+		{
+			name:  "channel message with mention",
+			event: &slackevents.MessageEvent{ChannelType: "channel", Text: "<@UBOT> fix the tests"},
+			want:  true,
+		},
+		{
+			name:  "direct message without mention",
+			event: &slackevents.MessageEvent{ChannelType: "im", Text: "fix the tests"},
+			want:  true,
+		},
+		{
+			name:  "bot message in a direct message",
+			event: &slackevents.MessageEvent{ChannelType: "im", Text: "Starting", BotID: "B123"},
+			want:  false,
+		},
+	}
+
+	handler := &Handler{config: createTestConfig(), botUserID: "UBOT"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := handler.shouldProcessMessage(tt.event); got != tt.want {
+				t.Errorf("shouldProcessMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectMessageWorkDir(t *testing.T) {
+	handler := &Handler{config: createTestConfigWithWorkingDirs([]config.WorkingDirectoryConfig{
+		{Name: "api", Path: "/src/api"},
+		{Name: "web", Path: "/src/web"},
+	})}
+
+	tests := []struct {
+		name        string
+		text        string
+		wantWorkDir string
+		wantPrompt  string
+		wantOK      bool
+	}{
+		{
+			name:        "working directory and prompt",
+			text:        "API fix the login bug",
+			wantWorkDir: "/src/api",
+			wantPrompt:  "fix the login bug",
+			wantOK:      true,
+		},
+		{
+			name:   "working directory without prompt",
+			text:   "web",
+			wantOK: false,
+		},
+		{
+			name:   "prompt without working directory",
+			text:   "fix the login bug",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workDir, prompt, ok := handler.directMessageWorkDir(tt.text)
+			if workDir != tt.wantWorkDir || prompt != tt.wantPrompt || ok != tt.wantOK {
+				t.Errorf("directMessageWorkDir(%q) = %q, %q, %v, want %q, %q, %v", tt.text, workDir, prompt, ok, tt.wantWorkDir, tt.wantPrompt, tt.wantOK)
+			}
+		})
+	}
+}
+
+// newTestSlackClient creates a Slack client whose API calls succeed and are recorded as "<method> <text>"
+func newTestSlackClient(t *testing.T) (*slack.Client, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		calls = append(calls, strings.TrimPrefix(r.URL.Path, "/")+" "+r.FormValue("text"))
+		mu.Unlock()
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+func TestCreatePullRequestAuthorization(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		approvers   config.ApproversConfig
+		wantCreated int
+		wantCall    string
+	}{
+		{
+			name:        "initiator",
+			userID:      "U_OWNER",
+			approvers:   config.ApproversConfig{Mode: approval.ApproversInitiator},
+			wantCreated: 1,
+			wantCall:    "chat.postEphemeral Failed to open pull request: pull requests are not configured",
+		},
+		{
+			name:        "admin",
+			userID:      "U_ADMIN",
+			approvers:   config.ApproversConfig{Mode: approval.ApproversInitiatorAndAdmins, Admins: []string{"U_ADMIN"}},
+			wantCreated: 1,
+			wantCall:    "chat.postEphemeral Failed to open pull request: pull requests are not configured",
+		},
+		{
+			name:        "other user",
+			userID:      "U_OTHER",
+			approvers:   config.ApproversConfig{Mode: approval.ApproversInitiator},
+			wantCreated: 0,
+			wantCall:    "chat.postEphemeral You are not allowed to open a pull request from this thread. Please ask <@U_OWNER> to do it.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := newTestSlackClient(t)
+			sessionMgr := &MockSessionManager{
+				threadStatusReturn: &ThreadStatus{ChannelID: "C123", ThreadTS: "1700000000.000100", UserID: "U_OWNER"},
+			}
+			h := &Handler{
+				client:     client,
+				config:     createTestConfig(),
+				sessionMgr: sessionMgr,
+				approvers:  approval.NewApprovers(config.ApprovalConfig{Approvers: tt.approvers}),
+			}
+
+			h.createPullRequest("C123", "1700000000.000100", tt.userID)
+
+			if sessionMgr.createPullRequestCalls != tt.wantCreated {
+				t.Errorf("CreatePullRequest called %d times, want %d", sessionMgr.createPullRequestCalls, tt.wantCreated)
+			}
+			if got := calls(); !reflect.DeepEqual(got, []string{tt.wantCall}) {
+				t.Errorf("Slack API calls = %q, want %q", got, []string{tt.wantCall})
+			}
+		})
+	}
+}
+
+func TestThreadActionAuthorization(t *testing.T) {
+	const threadTS = "1700000000.000100"
+
+	archive := func(h *Handler, userID string) {
+		h.archiveThread("C123", threadTS, userID)
+	}
+	removeWorktree := func(h *Handler, userID string) {
+		h.handleRemoveWorktreeAction(&slack.InteractionCallback{
+			User:    slack.User{ID: userID},
+			Channel: slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C123"}}},
+		}, &slack.BlockAction{Value: threadTS})
+	}
+	archiveCalls := func(m *MockSessionManager) int { return m.archiveThreadCalls }
+	removeWorktreeCalls := func(m *MockSessionManager) int { return m.removeWorktreeCalls }
+
+	tests := []struct {
+		name      string
+		run       func(h *Handler, userID string)
+		calls     func(m *MockSessionManager) int
+		userID    string
+		approvers config.ApproversConfig
+		wantCalls int
+		wantCall  string
+	}{
+		{
+			name:      "archive by initiator",
+			run:       archive,
+			calls:     archiveCalls,
+			userID:    "U_OWNER",
+			approvers: config.ApproversConfig{Mode: approval.ApproversInitiator},
+			wantCalls: 1,
+			wantCall:  "chat.postMessage " + messages.FormatThreadArchivedMessage("", ""),
+		},
+		{
+			name:      "archive by other user",
+			run:       archive,
+			calls:     archiveCalls,
+			userID:    "U_OTHER",
+			approvers: config.ApproversConfig{Mode: approval.ApproversInitiator},
+			wantCalls: 0,
+			wantCall:  "chat.postEphemeral You are not allowed to archive this thread. Please ask <@U_OWNER> to do it.",
+		},
+		{
+			name:      "archive by anyone",
+			run:       archive,
+			calls:     archiveCalls,
+			userID:    "U_OTHER",
+			approvers: config.ApproversConfig{},
+			wantCalls: 1,
+			wantCall:  "chat.postMessage " + messages.FormatThreadArchivedMessage("", ""),
+		},
+		{
+			name:      "remove worktree by admin",
+			run:       removeWorktree,
+			calls:     removeWorktreeCalls,
+			userID:    "U_ADMIN",
+			approvers: config.ApproversConfig{Mode: approval.ApproversInitiatorAndAdmins, Admins: []string{"U_ADMIN"}},
+			wantCalls: 1,
+			wantCall:  "chat.postEphemeral Failed to remove worktree: thread " + threadTS + " has no worktree",
+		},
+		{
+			name:      "remove worktree by other user",
+			run:       removeWorktree,
+			calls:     removeWorktreeCalls,
+			userID:    "U_OTHER",
+			approvers: config.ApproversConfig{Mode: approval.ApproversInitiator},
+			wantCalls: 0,
+			wantCall:  "chat.postEphemeral You are not allowed to remove the worktree of this thread. Please ask <@U_OWNER> to do it.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := newTestSlackClient(t)
+			sessionMgr := &MockSessionManager{
+				threadStatusReturn: &ThreadStatus{ChannelID: "C123", ThreadTS: threadTS, UserID: "U_OWNER"},
+			}
+			h := &Handler{
+				client:     client,
+				config:     createTestConfig(),
+				sessionMgr: sessionMgr,
+				approvers:  approval.NewApprovers(config.ApprovalConfig{Approvers: tt.approvers}),
+			}
+
+			tt.run(h, tt.userID)
+
+			if got := tt.calls(sessionMgr); got != tt.wantCalls {
+				t.Errorf("session manager called %d times, want %d", got, tt.wantCalls)
+			}
+			if got := calls(); !reflect.DeepEqual(got, []string{tt.wantCall}) {
+				t.Errorf("Slack API calls = %q, want %q", got, []string{tt.wantCall})
+			}
+		})
+	}
+}
+
+func TestSessionOptionsFromValues(t *testing.T) {
+	selected := func(blockID, actionID, value string) map[string]map[string]slack.BlockAction {
+		return map[string]map[string]slack.BlockAction{
+			blockID: {actionID: slack.BlockAction{SelectedOption: slack.OptionBlockObject{Value: value}}},
+		}
+	}
+	permissionMode := func(value string) map[string]map[string]slack.BlockAction {
+		return selected(blocks.PermissionModeBlockID, blocks.PermissionModeActionID, value)
+	}
+
+	tests := []struct {
+		name       string
+		values     map[string]map[string]slack.BlockAction
+		configured string
+		want       SessionOptions
+		wantErr    bool
+	}{
+		{
+			name:   "directory defaults",
+			values: permissionMode(blocks.OptionDefault),
+			want:   SessionOptions{},
+		},
+		{
+			name:   "model",
+			values: selected(blocks.ModelBlockID, blocks.ModelActionID, "opus"),
+			want:   SessionOptions{Model: "opus"},
+		},
+		{
+			name:   "normal permission mode",
+			values: permissionMode(blocks.PermissionModeNormal),
+			want:   SessionOptions{PermissionMode: "default"},
+		},
+		{
+			name:       "plan mode below the directory mode",
+			values:     permissionMode("plan"),
+			configured: "acceptEdits",
+			want:       SessionOptions{PermissionMode: "plan"},
+		},
+		{
+			name:       "accept edits allowed by the directory",
+			values:     permissionMode("acceptEdits"),
+			configured: "acceptEdits",
+			want:       SessionOptions{PermissionMode: "acceptEdits"},
+		},
+		{
+			name:    "accept edits above the default mode",
+			values:  permissionMode("acceptEdits"),
+			wantErr: true,
+		},
+		{
+			name:       "bypass above the directory mode",
+			values:     permissionMode("bypassPermissions"),
+			configured: "acceptEdits",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown permission mode",
+			values:     permissionMode("yolo"),
+			configured: "bypassPermissions",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sessionOptionsFromValues(tt.values, config.ClaudeProfileConfig{PermissionMode: tt.configured})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sessionOptionsFromValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sessionOptionsFromValues() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
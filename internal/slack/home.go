@@ -0,0 +1,189 @@
+package slack
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/yuya-takeyama/cc-slack/internal/slack/blocks"
+)
+
+// homeRefreshDelay batches the App Home tab refreshes of a burst of session state changes
+const homeRefreshDelay = 2 * time.Second
+
+// maxHomeRecentSessions is the number of finished sessions shown in the App Home tab
+const maxHomeRecentSessions = 10
+
+// maxHomeActiveSessions keeps the App Home tab below Slack's limit of 100 blocks
+const maxHomeActiveSessions = 30
+
+// handleAppHomeOpened publishes the App Home tab when a user opens it
+func (h *Handler) handleAppHomeOpened(event *slackevents.AppHomeOpenedEvent) {
+	if event.Tab != "home" {
+		return
+	}
+	go h.publishHome(event.User)
+}
+
+// homeRefresher batches the App Home tab refreshes of each user
+type homeRefresher struct {
+	mu      sync.Mutex
+	pending map[string]bool // Users whose App Home tab is about to be refreshed
+}
+
+// newHomeRefresher creates an App Home refresher
+func newHomeRefresher() *homeRefresher {
+	return &homeRefresher{pending: make(map[string]bool)}
+}
+
+// schedule calls publish for a user after homeRefreshDelay unless a refresh is already scheduled
+func (r *homeRefresher) schedule(userID string, publish func(userID string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending[userID] {
+		return
+	}
+	r.pending[userID] = true
+
+	time.AfterFunc(homeRefreshDelay, func() {
+		r.mu.Lock()
+		delete(r.pending, userID)
+		r.mu.Unlock()
+
+		publish(userID)
+	})
+}
+
+// RefreshHome republishes the App Home tab of a user after their sessions or approval requests changed
+// Changes within homeRefreshDelay of each other are published together.
+func (h *Handler) RefreshHome(userID string) {
+	if userID == "" || h.homeRefresher == nil {
+		return
+	}
+	h.homeRefresher.schedule(userID, h.publishHome)
+}
+
+// publishHome publishes the App Home tab of a user
+func (h *Handler) publishHome(userID string) {
+	data, err := h.homeData(userID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID).Msg("failed to collect App Home data")
+		return
+	}
+
+	if _, err := h.client.PublishView(userID, blocks.HomeView(data), ""); err != nil {
+		log.Error().Err(err).Str("user_id", userID).Msg("failed to publish App Home")
+	}
+}
+
+// homeData collects the sessions and approval requests shown in the App Home tab of a user
+func (h *Handler) homeData(userID string) (blocks.HomeData, error) {
+	var data blocks.HomeData
+
+	active, err := h.sessionMgr.ListSessionsByUser(userID)
+	if err != nil {
+		return data, fmt.Errorf("failed to list active sessions: %w", err)
+	}
+	if len(active) > maxHomeActiveSessions {
+		active = active[:maxHomeActiveSessions]
+	}
+	for _, s := range active {
+		state := ThreadStateWaiting
+		if s.Running {
+			state = ThreadStateRunning
+		}
+		data.Active = append(data.Active, blocks.HomeSession{
+			ChannelID:  s.ChannelID,
+			ThreadTS:   s.ThreadTS,
+			WorkDir:    h.workingDirName(s.WorkDir),
+			State:      state,
+			LastActive: s.LastActive,
+		})
+	}
+
+	recent, err := h.sessionMgr.ListRecentSessionsByUser(userID, maxHomeRecentSessions)
+	if err != nil {
+		return data, fmt.Errorf("failed to list recent sessions: %w", err)
+	}
+	for _, s := range recent {
+		data.Recent = append(data.Recent, blocks.HomeRecentSession{
+			ChannelID: s.ChannelID,
+			WorkDir:   h.workingDirName(s.WorkDir),
+			Status:    s.Status,
+			Cost:      s.Cost,
+			EndedAt:   s.EndedAt,
+		})
+	}
+
+	data.Approvals = h.homeApprovals(userID)
+	return data, nil
+}
+
+// homeApprovals returns the pending approval requests waiting on a user
+// Those are the requests of the user's own sessions and, when only some users may answer
+// approval requests, the requests of other sessions the user is allowed to answer.
+func (h *Handler) homeApprovals(userID string) []blocks.HomeApproval {
+	if h.approvalResponder == nil {
+		return nil
+	}
+
+	var approvals []blocks.HomeApproval
+	for _, pending := range h.approvalResponder.PendingApprovals() {
+		approval := blocks.HomeApproval{
+			ChannelID:   pending.ChannelID,
+			ToolName:    pending.ToolName,
+			RequestedAt: pending.RequestedAt,
+		}
+		if pending.UserID != userID {
+			if !h.approvers.Restricted() {
+				continue
+			}
+			authorized, err := h.approvers.IsAuthorized(userID, pending.UserID, h.userGroupMembers)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("request_id", pending.RequestID).
+					Str("user_id", userID).
+					Msg("failed to check approver")
+			}
+			if !authorized {
+				continue
+			}
+			approval.InitiatorID = pending.UserID
+		}
+		approvals = append(approvals, approval)
+	}
+	return approvals
+}
+
+// workingDirName returns the configured name of a working directory, or its base name
+func (h *Handler) workingDirName(workDir string) string {
+	if wd, ok := h.config.FindWorkingDir(workDir); ok && wd.Name != "" {
+		return wd.Name
+	}
+	return filepath.Base(workDir)
+}
+
+// handleHomeStopSessionAction handles the Stop button of a session in the App Home tab
+// The button value carries the session's thread as "<channel ID>:<thread ts>".
+func (h *Handler) handleHomeStopSessionAction(payload *slack.InteractionCallback, action *slack.BlockAction) {
+	channelID, threadTS, ok := strings.Cut(action.Value, ":")
+	if !ok {
+		log.Error().Str("value", action.Value).Msg("invalid App Home stop button value")
+		return
+	}
+
+	h.stopSession(channelID, threadTS, payload.User.ID)
+	h.RefreshHome(payload.User.ID)
+}
+
+// handleHomeStartSessionAction handles the Start a new session button in the App Home tab
+// The App Home tab belongs to no channel, so the modal asks for the channel to start the thread in.
+func (h *Handler) handleHomeStartSessionAction(payload *slack.InteractionCallback) {
+	h.openRepoModal(payload.TriggerID, sessionModalMetadata{}, blocks.SessionStartPrefill{ChooseChannel: true})
+}
@@ -10,7 +10,10 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/slack-go/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/approval"
+	"github.com/yuya-takeyama/cc-slack/internal/config"
 	"github.com/yuya-takeyama/cc-slack/internal/mcp"
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
 	"github.com/yuya-takeyama/cc-slack/internal/richtext"
 	"github.com/yuya-takeyama/cc-slack/internal/slack/blocks"
 )
@@ -61,35 +64,69 @@ func (h *Handler) HandleInteraction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	response := h.handleInteractionCallback(&payload)
+	if response != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleInteractionCallback dispatches an interaction payload regardless of the transport it arrived on.
+// It returns the response body to send back to Slack, or nil for an empty acknowledgement.
+func (h *Handler) handleInteractionCallback(payload *slack.InteractionCallback) map[string]interface{} {
 	switch payload.Type {
 	case slack.InteractionTypeBlockActions:
-		// Handle button clicks for approval_prompt
+		// Handle button clicks for approval_prompt and session controls
 		for _, action := range payload.ActionCallback.BlockActions {
-			if strings.HasPrefix(action.ActionID, "approve_") {
-				h.handleApprovalAction(&payload, action, true)
+			if action.ActionID == "stop_session" {
+				h.handleStopSessionAction(payload, action)
+			} else if action.ActionID == "create_pull_request" {
+				h.handleCreatePullRequestAction(payload, action)
+			} else if action.ActionID == "upload_patch" {
+				h.handleUploadPatchAction(payload, action)
+			} else if action.ActionID == "remove_worktree" {
+				h.handleRemoveWorktreeAction(payload, action)
+			} else if action.ActionID == blocks.HomeStopSessionActionID {
+				h.handleHomeStopSessionAction(payload, action)
+			} else if action.ActionID == blocks.HomeStartSessionActionID {
+				h.handleHomeStartSessionAction(payload)
+			} else if strings.HasPrefix(action.ActionID, "edit_approve_") {
+				h.handleEditApprovalAction(payload, action)
+			} else if strings.HasPrefix(action.ActionID, "approve_session_") {
+				h.handleSessionApprovalAction(payload, action, approval.ScopeSession)
+			} else if strings.HasPrefix(action.ActionID, "approve_pattern_") {
+				h.handleSessionApprovalAction(payload, action, approval.ScopePattern)
+			} else if strings.HasPrefix(action.ActionID, "approve_") {
+				h.handleApprovalAction(payload, action, true)
 			} else if strings.HasPrefix(action.ActionID, "deny_with_reason_") {
-				h.handleDenyWithReasonAction(&payload, action)
+				h.handleDenyWithReasonAction(payload, action)
 			} else if strings.HasPrefix(action.ActionID, "deny_") {
-				h.handleApprovalAction(&payload, action, false)
+				h.handleApprovalAction(payload, action, false)
 			}
 		}
+	case slack.InteractionTypeMessageAction:
+		// Handle message shortcuts
+		if payload.CallbackID == startSessionShortcutID {
+			go h.openMessageShortcutModal(payload)
+		}
 	case slack.InteractionTypeViewSubmission:
 		// Handle modal submissions
-		if payload.View.CallbackID == "repo_modal" {
-			h.handleRepoModalSubmission(w, &payload)
-			return // Don't send 200 OK here, handleRepoModalSubmission will handle response
-		}
-		if payload.View.CallbackID == "repo_modal_single" {
-			h.handleSingleDirModalSubmission(w, &payload)
-			return
-		}
-		if payload.View.CallbackID == "deny_reason_modal" {
-			h.handleDenyReasonModalSubmission(w, &payload)
-			return
+		switch payload.View.CallbackID {
+		case "repo_modal":
+			return h.handleRepoModalSubmission(payload)
+		case "repo_modal_single":
+			return h.handleSingleDirModalSubmission(payload)
+		case "deny_reason_modal":
+			return h.handleDenyReasonModalSubmission(payload)
+		case "edit_approval_modal":
+			return h.handleEditApprovalModalSubmission(payload)
 		}
 	}
 
-	w.WriteHeader(http.StatusOK)
+	return nil
 }
 
 // handleApprovalAction handles approval/denial button clicks
@@ -102,6 +139,10 @@ func (h *Handler) handleApprovalAction(payload *slack.InteractionCallback, actio
 		requestID = strings.TrimPrefix(action.ActionID, "deny_")
 	}
 
+	if !h.authorizeApprover(payload, requestID) {
+		return
+	}
+
 	// Send approval response to MCP server
 	if h.approvalResponder != nil && requestID != "" {
 		response := mcp.ApprovalResponse{
@@ -118,15 +159,301 @@ func (h *Handler) handleApprovalAction(payload *slack.InteractionCallback, actio
 		err := h.approvalResponder.SendApprovalResponse(requestID, response)
 		if err != nil {
 			fmt.Printf("Failed to send approval response: %v\n", err)
+			h.postApprovalUnavailable(payload)
+			return
 		}
 	}
 
 	// Update the message with enhanced status information
-	h.updateApprovalMessage(payload, approved)
+	h.updateApprovalMessage(payload, approved, "")
+}
+
+// handleSessionApprovalAction handles the approve-for-session button clicks
+// The MCP server records a grant so matching requests in the session are approved without asking again.
+func (h *Handler) handleSessionApprovalAction(payload *slack.InteractionCallback, action *slack.BlockAction, scope string) {
+	// Extract request ID from action ID
+	requestID := strings.TrimPrefix(action.ActionID, fmt.Sprintf("approve_%s_", scope))
+
+	if !h.authorizeApprover(payload, requestID) {
+		return
+	}
+
+	if h.approvalResponder != nil && requestID != "" {
+		response := mcp.ApprovalResponse{
+			Behavior: "allow",
+			Message:  "Approved for this session via Slack",
+			// IMPORTANT: When behavior is "allow", updatedInput is required
+			UpdatedInput: map[string]interface{}{}, // Empty map for no changes
+			GrantScope:   scope,
+		}
+
+		err := h.approvalResponder.SendApprovalResponse(requestID, response)
+		if err != nil {
+			fmt.Printf("Failed to send approval response: %v\n", err)
+			h.postApprovalUnavailable(payload)
+			return
+		}
+	}
+
+	h.updateApprovalMessage(payload, true, scope)
+}
+
+// authorizeApprover checks that the clicking user may answer an approval request
+// Unauthorized clicks are rejected with an ephemeral message and recorded in the session transcript.
+func (h *Handler) authorizeApprover(payload *slack.InteractionCallback, requestID string) bool {
+	if h.approvers == nil || h.approvalResponder == nil || requestID == "" {
+		return true
+	}
+
+	initiatorID, exists := h.approvalResponder.ApprovalInitiator(requestID)
+	if !exists {
+		// The request is no longer pending, which is reported when the response is sent
+		return true
+	}
+
+	authorized, err := h.approvers.IsAuthorized(payload.User.ID, initiatorID, h.userGroupMembers)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("user_id", payload.User.ID).
+			Msg("failed to check approver")
+	}
+	if authorized {
+		return true
+	}
+
+	h.approvalResponder.RecordRejectedApprover(requestID, payload.User.ID)
+
+	_, err = h.client.PostEphemeral(
+		payload.Channel.ID,
+		payload.User.ID,
+		slack.MsgOptionText(fmt.Sprintf("You are not allowed to answer this approval request. Please ask <@%s> to review it.", initiatorID), false),
+		slack.MsgOptionTS(payload.Message.ThreadTimestamp),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to post unauthorized approver message")
+	}
+
+	return false
+}
+
+// userGroupMembers returns the user IDs of the members of a Slack user group
+func (h *Handler) userGroupMembers(userGroupID string) ([]string, error) {
+	return h.client.GetUserGroupMembers(userGroupID)
+}
+
+// postApprovalUnavailable tells the clicking user that the approval request is no longer pending,
+// e.g. because it timed out or was answered by someone else
+func (h *Handler) postApprovalUnavailable(payload *slack.InteractionCallback) {
+	_, err := h.client.PostEphemeral(
+		payload.Channel.ID,
+		payload.User.ID,
+		slack.MsgOptionText("This approval request is no longer pending. It may have expired or already been answered.", false),
+		slack.MsgOptionTS(payload.Message.ThreadTimestamp),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to post approval unavailable message")
+	}
+}
+
+// handleEditApprovalAction opens a modal for editing the tool input before approving it
+func (h *Handler) handleEditApprovalAction(payload *slack.InteractionCallback, action *slack.BlockAction) {
+	// Extract request ID from action ID
+	requestID := strings.TrimPrefix(action.ActionID, "edit_approve_")
+
+	if h.approvalResponder == nil || !h.authorizeApprover(payload, requestID) {
+		return
+	}
+
+	toolName, input, exists := h.approvalResponder.ApprovalInput(requestID)
+	if !exists {
+		h.postApprovalUnavailable(payload)
+		return
+	}
+	if len(blocks.EditableInputKeys(input)) == 0 {
+		_, err := h.client.PostEphemeral(
+			payload.Channel.ID,
+			payload.User.ID,
+			slack.MsgOptionText(fmt.Sprintf("The input of `%s` cannot be edited. Please approve or deny it as it is.", toolName), false),
+			slack.MsgOptionTS(payload.Message.ThreadTimestamp),
+		)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to post edit unavailable message")
+		}
+		return
+	}
+
+	// Get the original text from the message
+	var originalText string
+	if len(payload.Message.Blocks.BlockSet) > 0 {
+		if section, ok := payload.Message.Blocks.BlockSet[0].(*slack.SectionBlock); ok && section.Text != nil {
+			originalText = section.Text.Text
+		}
+	}
+
+	// Create metadata with request ID and message info
+	metadata := map[string]string{
+		"request_id":    requestID,
+		"channel_id":    payload.Channel.ID,
+		"message_ts":    payload.Message.Timestamp,
+		"user_id":       payload.User.ID,
+		"original_text": originalText,
+	}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	// Create and open modal
+	modal := blocks.EditApprovalModal(string(metadataJSON), toolName, input)
+
+	_, err := h.client.OpenView(payload.TriggerID, modal)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to open edit approval modal")
+	}
+}
+
+// handleStopSessionAction handles the Stop button on the session start message
+func (h *Handler) handleStopSessionAction(payload *slack.InteractionCallback, action *slack.BlockAction) {
+	// The button value carries the thread timestamp of the session
+	threadTS := action.Value
+	if threadTS == "" {
+		threadTS = payload.Message.ThreadTimestamp
+	}
+
+	h.stopSession(payload.Channel.ID, threadTS, payload.User.ID)
+}
+
+// handleCreatePullRequestAction handles the Commit & open PR button on the session result message
+// Pushing and opening the pull request takes longer than Slack waits for a response, so it runs in the background.
+func (h *Handler) handleCreatePullRequestAction(payload *slack.InteractionCallback, action *slack.BlockAction) {
+	threadTS := action.Value
+	if threadTS == "" {
+		threadTS = payload.Message.ThreadTimestamp
+	}
+
+	go h.createPullRequest(payload.Channel.ID, threadTS, payload.User.ID)
+}
+
+// authorizeThreadAction checks that a user may control the sessions of a thread, e.g. to archive it
+// Errors are reported with the failure prefix, and unauthorized users are told they may not perform
+// the action, both with an ephemeral message.
+func (h *Handler) authorizeThreadAction(channelID, threadTS, userID, failure, action string) bool {
+	status, err := h.sessionMgr.GetThreadStatus(channelID, threadTS)
+	if err != nil {
+		h.postEphemeralToThread(channelID, threadTS, userID, fmt.Sprintf("%s: %v", failure, err))
+		return false
+	}
+	if h.mayControlSession(userID, status.UserID) {
+		return true
+	}
+
+	text := fmt.Sprintf("You are not allowed to %s.", action)
+	if status.UserID != "" {
+		text += fmt.Sprintf(" Please ask <@%s> to do it.", status.UserID)
+	}
+	h.postEphemeralToThread(channelID, threadTS, userID, text)
+	return false
+}
+
+// createPullRequest opens a pull request from a thread's worktree and posts its link to the thread
+func (h *Handler) createPullRequest(channelID, threadTS, userID string) {
+	if !h.authorizeThreadAction(channelID, threadTS, userID, "Failed to open pull request", "open a pull request from this thread") {
+		return
+	}
+
+	pr, err := h.sessionMgr.CreatePullRequest(channelID, threadTS)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("channel_id", channelID).
+			Str("thread_ts", threadTS).
+			Msg("failed to create pull request")
+
+		h.postEphemeralToThread(channelID, threadTS, userID, fmt.Sprintf("Failed to open pull request: %v", err))
+		return
+	}
+
+	text := messages.FormatPullRequestMessage(pr.URL, pr.Branch, pr.Created, pr.Committed, userID)
+	if err := h.PostToThread(channelID, threadTS, text); err != nil {
+		log.Error().Err(err).Msg("failed to post pull request message")
+	}
+}
+
+// handleUploadPatchAction handles the Upload patch button on the session result message
+func (h *Handler) handleUploadPatchAction(payload *slack.InteractionCallback, action *slack.BlockAction) {
+	threadTS := payload.Message.ThreadTimestamp
+	if threadTS == "" {
+		threadTS = payload.Message.Timestamp
+	}
+
+	baseTree, tree, ok := strings.Cut(action.Value, "..")
+	if !ok {
+		log.Error().Str("value", action.Value).Msg("invalid upload patch value")
+		return
+	}
+
+	patch, err := h.sessionMgr.GitPatch(payload.Channel.ID, threadTS, baseTree, tree)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("channel_id", payload.Channel.ID).
+			Str("thread_ts", threadTS).
+			Msg("failed to create patch")
+
+		h.postEphemeralToThread(payload.Channel.ID, threadTS, payload.User.ID, fmt.Sprintf("Failed to create patch: %v", err))
+		return
+	}
+	if patch == "" {
+		h.postEphemeralToThread(payload.Channel.ID, threadTS, payload.User.ID, "The session made no changes.")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.patch", tree[:min(len(tree), 7)])
+	if err := h.PostSnippet(payload.Channel.ID, threadTS, filename, "Session changes", patch, "diff"); err != nil {
+		log.Error().Err(err).Msg("failed to upload patch")
+	}
+}
+
+// handleRemoveWorktreeAction handles the Remove worktree button on the thread archived message
+func (h *Handler) handleRemoveWorktreeAction(payload *slack.InteractionCallback, action *slack.BlockAction) {
+	threadTS := action.Value
+	if threadTS == "" {
+		threadTS = payload.Message.ThreadTimestamp
+	}
+
+	if !h.authorizeThreadAction(payload.Channel.ID, threadTS, payload.User.ID, "Failed to remove worktree", "remove the worktree of this thread") {
+		return
+	}
+
+	wt, err := h.sessionMgr.RemoveWorktree(payload.Channel.ID, threadTS)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("channel_id", payload.Channel.ID).
+			Str("thread_ts", threadTS).
+			Msg("failed to remove worktree")
+
+		h.postEphemeralToThread(payload.Channel.ID, threadTS, payload.User.ID, fmt.Sprintf("Failed to remove worktree: %v", err))
+		return
+	}
+
+	// Replace the message without the button so the worktree cannot be removed twice
+	text := messages.FormatWorktreeRemovedMessage(wt.Path, wt.Branch)
+	err = h.queueMessage(payload.Channel.ID, "", mergeNone, text, h.updateMessageFunc(
+		payload.Channel.ID,
+		payload.Message.Timestamp,
+		slack.MsgOptionBlocks(slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+			nil,
+			nil,
+		)),
+	))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update thread archived message")
+	}
 }
 
 // updateApprovalMessage updates the approval message with status and user information
-func (h *Handler) updateApprovalMessage(payload *slack.InteractionCallback, approved bool) {
+func (h *Handler) updateApprovalMessage(payload *slack.InteractionCallback, approved bool, grantScope string) {
 	// Preserve the original blocks and add a status block
 	originalBlocks := payload.Message.Blocks.BlockSet
 
@@ -144,7 +471,7 @@ func (h *Handler) updateApprovalMessage(payload *slack.InteractionCallback, appr
 	}
 
 	// Create new blocks with updated text
-	newBlocks := blocks.ApprovalMessageUpdate(originalText, payload.User.ID, approved)
+	newBlocks := blocks.ApprovalMessageUpdate(originalText, payload.User.ID, approved, grantScope)
 
 	// Update the message
 	_, _, _, err := h.client.UpdateMessage(
@@ -159,10 +486,10 @@ func (h *Handler) updateApprovalMessage(payload *slack.InteractionCallback, appr
 }
 
 // openRepoModal opens the working directory selection modal
-func (h *Handler) openRepoModal(triggerID, channelID, userID, initialText string) {
+func (h *Handler) openRepoModal(triggerID string, metadata sessionModalMetadata, prefill blocks.SessionStartPrefill) {
 	// In single directory mode, show modal with only prompt input
 	if h.config.IsSingleDirectoryMode() {
-		modal := blocks.SessionStartModalSingle(channelID)
+		modal := blocks.SessionStartModalSingle(metadata.encode(), h.claudeProfile(h.config.GetSingleWorkingDirectory()), prefill)
 
 		// Open modal
 		_, err := h.client.OpenView(triggerID, modal)
@@ -173,12 +500,7 @@ func (h *Handler) openRepoModal(triggerID, channelID, userID, initialText string
 	}
 
 	// Multi-directory mode: Create modal view
-	modal := blocks.SessionStartModal(channelID, h.config.WorkingDirs)
-
-	// Set initial text if provided
-	if initialText != "" {
-		// TODO: Pre-populate the rich text input with initialText
-	}
+	modal := blocks.SessionStartModal(metadata.encode(), h.config.WorkingDirs, prefill)
 
 	// Open modal
 	_, err := h.client.OpenView(triggerID, modal)
@@ -187,8 +509,17 @@ func (h *Handler) openRepoModal(triggerID, channelID, userID, initialText string
 	}
 }
 
+// openWorkingDirModal opens the session start modal with a single working directory to choose from
+func (h *Handler) openWorkingDirModal(triggerID, channelID string, wd config.WorkingDirectoryConfig) {
+	metadata := sessionModalMetadata{ChannelID: channelID}
+	modal := blocks.SessionStartModal(metadata.encode(), []config.WorkingDirectoryConfig{wd}, blocks.SessionStartPrefill{WorkDir: wd.Path})
+	if _, err := h.client.OpenView(triggerID, modal); err != nil {
+		log.Error().Err(err).Msg("failed to open modal")
+	}
+}
+
 // handleRepoModalSubmission handles the working directory selection modal submission
-func (h *Handler) handleRepoModalSubmission(w http.ResponseWriter, payload *slack.InteractionCallback) {
+func (h *Handler) handleRepoModalSubmission(payload *slack.InteractionCallback) map[string]interface{} {
 	values := payload.View.State.Values
 
 	// Extract selected repository path
@@ -222,10 +553,12 @@ func (h *Handler) handleRepoModalSubmission(w http.ResponseWriter, payload *slac
 				"repo_block": "Please select a working directory",
 			},
 		}
+		return errorResponse
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(errorResponse)
-		return
+	opts, err := sessionOptionsFromValues(values, h.claudeProfile(repoPath))
+	if err != nil {
+		return sessionOptionsErrorResponse(err)
 	}
 
 	// Success - close modal
@@ -233,22 +566,72 @@ func (h *Handler) handleRepoModalSubmission(w http.ResponseWriter, payload *slac
 		"response_action": "clear",
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(successResponse)
-
 	// Get channel ID from private metadata (stored during modal creation)
-	channelID := payload.View.PrivateMetadata
+	metadata := parseSessionModalMetadata(payload.View.PrivateMetadata)
+	channelID := metadata.ChannelID
+	if channelID == "" {
+		// Modals opened from the App Home tab ask for the channel instead
+		channelID = values[blocks.ChannelBlockID][blocks.ChannelActionID].SelectedConversation
+	}
 	if channelID == "" {
 		log.Error().Msg("channel ID not found in private metadata")
-		return
+		return successResponse
 	}
 
 	// Create thread and start session asynchronously
-	go h.createThreadAndStartSession(channelID, repoPath, prompt, payload.User.ID)
+	go h.createThreadAndStartSession(channelID, repoPath, prompt, payload.User.ID, opts, metadata.ImageFileIDs)
+
+	return successResponse
+}
+
+// sessionOptionsErrorResponse shows an invalid session option below the permission mode select
+func sessionOptionsErrorResponse(err error) map[string]interface{} {
+	return map[string]interface{}{
+		"response_action": "errors",
+		"errors": map[string]string{
+			blocks.PermissionModeBlockID: err.Error(),
+		},
+	}
+}
+
+// claudeProfile returns the Claude Code profile of a working directory, which is empty when it is not configured
+func (h *Handler) claudeProfile(workDir string) config.ClaudeProfileConfig {
+	wd, _ := h.config.FindWorkingDir(workDir)
+	return wd.Claude
+}
+
+// sessionOptionsFromValues extracts the Claude Code options chosen in the session start modal
+// Options left at "Directory default" stay empty so the working directory profile decides.
+func sessionOptionsFromValues(values map[string]map[string]slack.BlockAction, profile config.ClaudeProfileConfig) (SessionOptions, error) {
+	var opts SessionOptions
+	if v := values[blocks.ModelBlockID][blocks.ModelActionID].SelectedOption.Value; v != "" && v != blocks.OptionDefault {
+		opts.Model = v
+	}
+	switch v := values[blocks.PermissionModeBlockID][blocks.PermissionModeActionID].SelectedOption.Value; v {
+	case "", blocks.OptionDefault:
+	case blocks.PermissionModeNormal:
+		opts.PermissionMode = "default"
+	default:
+		opts.PermissionMode = v
+	}
+	switch values[blocks.ThinkingBlockID][blocks.ThinkingActionID].SelectedOption.Value {
+	case blocks.OptionOn:
+		enabled := true
+		opts.ExtendedThinking = &enabled
+	case blocks.OptionOff:
+		enabled := false
+		opts.ExtendedThinking = &enabled
+	}
+
+	// The modal only offers allowed modes, but its submission can carry any value
+	if !profile.AllowsPermissionMode(opts.PermissionMode) {
+		return SessionOptions{}, fmt.Errorf("permission mode %q is not allowed in this working directory", opts.PermissionMode)
+	}
+	return opts, nil
 }
 
 // handleSingleDirModalSubmission handles the modal submission in single directory mode
-func (h *Handler) handleSingleDirModalSubmission(w http.ResponseWriter, payload *slack.InteractionCallback) {
+func (h *Handler) handleSingleDirModalSubmission(payload *slack.InteractionCallback) map[string]interface{} {
 	values := payload.View.State.Values
 
 	// Extract initial prompt from rich text input
@@ -262,23 +645,33 @@ func (h *Handler) handleSingleDirModalSubmission(w http.ResponseWriter, payload
 		}
 	}
 
+	workDir := h.config.GetSingleWorkingDirectory()
+	opts, err := sessionOptionsFromValues(values, h.claudeProfile(workDir))
+	if err != nil {
+		return sessionOptionsErrorResponse(err)
+	}
+
 	// Success - close modal
 	successResponse := map[string]interface{}{
 		"response_action": "clear",
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(successResponse)
-
 	// Get channel ID from private metadata (stored during modal creation)
-	channelID := payload.View.PrivateMetadata
+	metadata := parseSessionModalMetadata(payload.View.PrivateMetadata)
+	channelID := metadata.ChannelID
+	if channelID == "" {
+		// Modals opened from the App Home tab ask for the channel instead
+		channelID = values[blocks.ChannelBlockID][blocks.ChannelActionID].SelectedConversation
+	}
 	if channelID == "" {
 		log.Error().Msg("channel ID not found in private metadata (single mode)")
-		return
+		return successResponse
 	}
 
 	// Use the configured single working directory
-	go h.createThreadAndStartSession(channelID, h.config.GetSingleWorkingDirectory(), prompt, payload.User.ID)
+	go h.createThreadAndStartSession(channelID, workDir, prompt, payload.User.ID, opts, metadata.ImageFileIDs)
+
+	return successResponse
 }
 
 // convertRichTextToString converts Slack rich text to plain string
@@ -293,6 +686,10 @@ func (h *Handler) handleDenyWithReasonAction(payload *slack.InteractionCallback,
 	// Extract request ID from action ID
 	requestID := strings.TrimPrefix(action.ActionID, "deny_with_reason_")
 
+	if !h.authorizeApprover(payload, requestID) {
+		return
+	}
+
 	// Get the original text from the message
 	var originalText string
 	if len(payload.Message.Blocks.BlockSet) > 0 {
@@ -321,7 +718,7 @@ func (h *Handler) handleDenyWithReasonAction(payload *slack.InteractionCallback,
 }
 
 // handleDenyReasonModalSubmission handles the denial reason modal submission
-func (h *Handler) handleDenyReasonModalSubmission(w http.ResponseWriter, payload *slack.InteractionCallback) {
+func (h *Handler) handleDenyReasonModalSubmission(payload *slack.InteractionCallback) map[string]interface{} {
 	values := payload.View.State.Values
 
 	// Extract denial reason
@@ -336,8 +733,7 @@ func (h *Handler) handleDenyReasonModalSubmission(w http.ResponseWriter, payload
 	var metadata map[string]string
 	if err := json.Unmarshal([]byte(payload.View.PrivateMetadata), &metadata); err != nil {
 		log.Error().Err(err).Msg("failed to parse metadata")
-		w.WriteHeader(http.StatusOK)
-		return
+		return nil
 	}
 
 	requestID := metadata["request_id"]
@@ -355,10 +751,7 @@ func (h *Handler) handleDenyReasonModalSubmission(w http.ResponseWriter, payload
 				"reason_block": "Please provide a reason for denial",
 			},
 		}
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(errorResponse)
-		return
+		return errorResponse
 	}
 
 	// Success - close modal
@@ -366,9 +759,6 @@ func (h *Handler) handleDenyReasonModalSubmission(w http.ResponseWriter, payload
 		"response_action": "clear",
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(successResponse)
-
 	// Send denial response with reason to MCP server
 	if h.approvalResponder != nil && requestID != "" {
 		response := mcp.ApprovalResponse{
@@ -389,6 +779,8 @@ func (h *Handler) handleDenyReasonModalSubmission(w http.ResponseWriter, payload
 	if channelID != "" && messageTS != "" {
 		h.updateApprovalMessageWithReason(channelID, messageTS, userID, reason, originalText)
 	}
+
+	return successResponse
 }
 
 // updateApprovalMessageWithReason updates the approval message with denial reason
@@ -413,3 +805,95 @@ func (h *Handler) updateApprovalMessageWithReason(channelID, messageTS, userID,
 		log.Error().Err(err).Msg("failed to update message with denial reason")
 	}
 }
+
+// handleEditApprovalModalSubmission approves a request with the tool input edited in the modal
+func (h *Handler) handleEditApprovalModalSubmission(payload *slack.InteractionCallback) map[string]interface{} {
+	// Parse metadata from private metadata
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(payload.View.PrivateMetadata), &metadata); err != nil {
+		log.Error().Err(err).Msg("failed to parse metadata")
+		return nil
+	}
+
+	requestID := metadata["request_id"]
+	channelID := metadata["channel_id"]
+	messageTS := metadata["message_ts"]
+	userID := metadata["user_id"]
+	originalText := metadata["original_text"]
+
+	if h.approvalResponder == nil || requestID == "" {
+		return nil
+	}
+
+	_, input, exists := h.approvalResponder.ApprovalInput(requestID)
+	if !exists {
+		return approvalUnavailableModalErrors(payload)
+	}
+
+	updatedInput, editedKeys := editedApprovalInput(input, payload.View.State.Values)
+
+	response := mcp.ApprovalResponse{
+		Behavior:     "allow",
+		Message:      "Approved with edits via Slack",
+		UpdatedInput: updatedInput,
+	}
+	if err := h.approvalResponder.SendApprovalResponse(requestID, response); err != nil {
+		log.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to send edited approval response")
+		return approvalUnavailableModalErrors(payload)
+	}
+
+	// Update the original message
+	if channelID != "" && messageTS != "" {
+		_, _, _, err := h.client.UpdateMessage(
+			channelID,
+			messageTS,
+			slack.MsgOptionBlocks(blocks.ApprovalEditedUpdate(originalText, userID, editedKeys, updatedInput)...),
+		)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to update message with edited approval")
+		}
+	}
+
+	// Success - close modal
+	return map[string]interface{}{
+		"response_action": "clear",
+	}
+}
+
+// editedApprovalInput applies the values submitted in the edit modal to a copy of the original tool input
+// It returns the updated input and the keys whose values changed.
+func editedApprovalInput(input map[string]interface{}, values map[string]map[string]slack.BlockAction) (map[string]interface{}, []string) {
+	updatedInput := make(map[string]interface{}, len(input))
+	for key, value := range input {
+		updatedInput[key] = value
+	}
+
+	var editedKeys []string
+	for _, key := range blocks.EditableInputKeys(input) {
+		field, ok := values[blocks.EditInputBlockID(key)]["value"]
+		if !ok {
+			continue
+		}
+		if field.Value != input[key] {
+			updatedInput[key] = field.Value
+			editedKeys = append(editedKeys, key)
+		}
+	}
+
+	return updatedInput, editedKeys
+}
+
+// approvalUnavailableModalErrors keeps a modal open with an error when its approval request is no longer pending
+func approvalUnavailableModalErrors(payload *slack.InteractionCallback) map[string]interface{} {
+	errors := map[string]string{}
+	for blockID := range payload.View.State.Values {
+		errors[blockID] = "This approval request is no longer pending. It may have expired or already been answered."
+	}
+	return map[string]interface{}{
+		"response_action": "errors",
+		"errors":          errors,
+	}
+}
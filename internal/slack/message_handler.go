@@ -64,16 +64,23 @@ func (h *Handler) HandleEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.handleEventsAPIEvent(eventsAPIEvent)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleEventsAPIEvent dispatches an Events API event regardless of the transport it arrived on
+func (h *Handler) handleEventsAPIEvent(eventsAPIEvent slackevents.EventsAPIEvent) {
 	// Handle callback events
 	if eventsAPIEvent.Type == slackevents.CallbackEvent {
 		innerEvent := eventsAPIEvent.InnerEvent
 		switch ev := innerEvent.Data.(type) {
 		case *slackevents.MessageEvent:
 			h.handleMessage(ev)
+		case *slackevents.AppHomeOpenedEvent:
+			h.handleAppHomeOpened(ev)
 		}
 	}
-
-	w.WriteHeader(http.StatusOK)
 }
 
 // handleMessage handles message events
@@ -108,6 +115,18 @@ func (h *Handler) handleMessage(event *slackevents.MessageEvent) {
 
 // handleThreadMessageEvent handles message events in existing threads
 func (h *Handler) handleThreadMessageEvent(event *slackevents.MessageEvent, text string) {
+	// A bare "stop" interrupts the running session instead of being sent as a prompt
+	if isStopCommand(text) {
+		h.stopSession(event.Channel, event.ThreadTimeStamp, event.User)
+		return
+	}
+
+	// A bare "archive" marks the thread as done and offers removing its worktree
+	if isArchiveCommand(text) {
+		h.archiveThread(event.Channel, event.ThreadTimeStamp, event.User)
+		return
+	}
+
 	// Try to find existing session
 	session, err := h.sessionMgr.GetSessionByThread(event.Channel, event.ThreadTimeStamp)
 	if err == nil && session != nil {
@@ -128,7 +147,7 @@ func (h *Handler) handleThreadMessageEvent(event *slackevents.MessageEvent, text
 		}
 
 		// Existing session found - send message to it
-		err = h.sessionMgr.SendMessage(session.SessionID, text)
+		err = h.sessionMgr.SendMessage(session.SessionID, event.User, event.TimeStamp, text)
 		if err != nil {
 			h.client.PostMessage(
 				event.Channel,
@@ -145,31 +164,41 @@ func (h *Handler) handleThreadMessageEvent(event *slackevents.MessageEvent, text
 
 // handleNewSessionFromMessage creates a new session or resumes one for message events
 func (h *Handler) handleNewSessionFromMessage(event *slackevents.MessageEvent, text string, threadTS string) {
+	// Determine working directory
+	workDir := h.determineWorkDir(event.Channel)
+
 	// In multi-directory mode, validate working directory availability
 	if !h.config.IsSingleDirectoryMode() {
 		// For new threads, prevent mention-based start
 		if event.ThreadTimeStamp == "" {
-			// Post error message with guidance
-			blocksSlice := blocks.MultiDirectoryError(h.config.Slack.SlashCommandName)
-
-			_, _, err := h.client.PostMessage(
-				event.Channel,
-				slack.MsgOptionBlocks(blocksSlice...),
-				slack.MsgOptionTS(threadTS),
-			)
-			if err != nil {
-				fmt.Printf("Failed to post error message: %v\n", err)
+			// In direct messages, a message starting with the name of a working directory starts a session there
+			var ok bool
+			if isDirectMessage(event) {
+				workDir, text, ok = h.directMessageWorkDir(text)
+			}
+			if !ok {
+				// Post error message with guidance
+				blocksSlice := blocks.MultiDirectoryError(h.config.Slack.SlashCommandName)
+				if isDirectMessage(event) {
+					blocksSlice = blocks.DirectMessageDirectoryError(h.config.WorkingDirs)
+				}
+
+				_, _, err := h.client.PostMessage(
+					event.Channel,
+					slack.MsgOptionBlocks(blocksSlice...),
+					slack.MsgOptionTS(threadTS),
+				)
+				if err != nil {
+					fmt.Printf("Failed to post error message: %v\n", err)
+				}
+				return
 			}
-			return
 		}
 
 		// For existing threads, we'll let the session manager handle validation
 		// It will check if the thread has a working directory stored
 	}
 
-	// Determine working directory
-	workDir := h.determineWorkDir(event.Channel)
-
 	// Process attachments if any, but defer actual download
 	var hasImages bool
 	var files []slack.File
@@ -199,7 +228,7 @@ func (h *Handler) handleNewSessionFromMessage(event *slackevents.MessageEvent, t
 
 	// Create session with text including image paths
 	ctx := context.Background()
-	resumed, previousSessionID, err := h.sessionMgr.CreateSession(ctx, event.Channel, threadTS, workDir, initialPrompt, event.User)
+	resumed, previousSessionID, err := h.sessionMgr.CreateSession(ctx, event.Channel, threadTS, workDir, initialPrompt, event.User, event.TimeStamp, SessionOptions{})
 	if err != nil {
 		h.client.PostMessage(
 			event.Channel,
@@ -246,6 +275,16 @@ func (h *Handler) handleNewSessionFromMessage(event *slackevents.MessageEvent, t
 	// Image processing has already been done before session creation
 }
 
+// directMessageWorkDir splits a direct message starting a session into its working directory and prompt
+// It reports false unless the message starts with the name of a working directory followed by a prompt.
+func (h *Handler) directMessageWorkDir(text string) (string, string, bool) {
+	prefill := h.slashCommandPrefill(text)
+	if prefill.WorkDir == "" || prefill.Prompt == "" {
+		return "", "", false
+	}
+	return prefill.WorkDir, prefill.Prompt, true
+}
+
 // shouldProcessMessage filters message events based on configuration
 func (h *Handler) shouldProcessMessage(event *slackevents.MessageEvent) bool {
 	// If filtering is disabled, process all messages
@@ -263,8 +302,8 @@ func (h *Handler) shouldProcessMessage(event *slackevents.MessageEvent) bool {
 		return false
 	}
 
-	// Check if bot mention is required
-	if h.config.Slack.MessageFilter.RequireMention {
+	// Check if bot mention is required; direct messages are always meant for the bot
+	if h.config.Slack.MessageFilter.RequireMention && !isDirectMessage(event) {
 		if !h.containsBotMention(event.Text) {
 			return false
 		}
@@ -304,6 +343,11 @@ func (h *Handler) shouldProcessMessage(event *slackevents.MessageEvent) bool {
 	return true
 }
 
+// isDirectMessage reports whether a message was sent in a direct message with the bot
+func isDirectMessage(event *slackevents.MessageEvent) bool {
+	return event.ChannelType == slack.TYPE_IM
+}
+
 // containsBotMention checks if the text contains a mention of the bot
 func (h *Handler) containsBotMention(text string) bool {
 	if h.botUserID == "" {
@@ -340,14 +384,19 @@ func RemoveBotMentionFromText(text string, botUserID string) string {
 
 // processMessageAttachments processes attachments directly from message event
 func (h *Handler) processMessageAttachments(event *slackevents.MessageEvent, files []slack.File) []string {
+	threadTS := event.ThreadTimeStamp
+	if threadTS == "" {
+		threadTS = event.TimeStamp
+	}
+	return h.downloadImageFiles(threadTS, files)
+}
+
+// downloadImageFiles downloads the images among files for a thread and returns their local paths
+func (h *Handler) downloadImageFiles(threadTS string, files []slack.File) []string {
 	// Create session-specific directory structure
 	// Format: images/{thread_ts}/{uuid}/
 	sessionID := uuid.New().String()
-	sessionDir := event.ThreadTimeStamp
-	if sessionDir == "" {
-		sessionDir = event.TimeStamp
-	}
-	sessionDir = strings.ReplaceAll(sessionDir, ".", "_")
+	sessionDir := strings.ReplaceAll(threadTS, ".", "_")
 
 	imageDir := filepath.Join(h.imagesDir, sessionDir, sessionID)
 	if err := os.MkdirAll(imageDir, 0755); err != nil {
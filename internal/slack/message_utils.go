@@ -1,37 +1,134 @@
 package slack
 
 import (
+	"context"
+	"path/filepath"
+	"time"
+
 	"github.com/slack-go/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/diff"
 	"github.com/yuya-takeyama/cc-slack/internal/slack/blocks"
 	"github.com/yuya-takeyama/cc-slack/internal/tools"
 )
 
+// Messages are sent through the outbox, which keeps their order per channel and retries rate-limited calls.
+// Methods returning a timestamp wait for delivery; the others return once the message is queued
+// and failures are logged by the outbox.
+
+// queueMessage queues a Slack API call without waiting for delivery
+// Messages with the same non-empty key queued in a burst are merged according to merge.
+func (h *Handler) queueMessage(channelID, key string, merge mergeMode, text string, send func(text string) (string, error)) error {
+	if h.outbox == nil {
+		_, err := send(text)
+		return err
+	}
+	return h.outbox.post(channelID, &outboundMessage{key: key, merge: merge, text: text, send: send})
+}
+
+// callMessage queues a Slack API call and waits for its result
+func (h *Handler) callMessage(channelID string, send func(text string) (string, error)) (string, error) {
+	if h.outbox == nil {
+		return send("")
+	}
+	return h.outbox.call(channelID, &outboundMessage{send: send})
+}
+
+// postMessageFunc returns a send function posting a message with the given options and the (merged) text
+func (h *Handler) postMessageFunc(channelID string, options ...slack.MsgOption) func(text string) (string, error) {
+	return func(text string) (string, error) {
+		if text != "" {
+			options = append(options[:len(options):len(options)], slack.MsgOptionText(text, false))
+		}
+		_, messageTS, err := h.client.PostMessage(channelID, options...)
+		return messageTS, err
+	}
+}
+
+// updateMessageFunc returns a send function updating a message with the given options and the (latest) text
+func (h *Handler) updateMessageFunc(channelID, messageTS string, options ...slack.MsgOption) func(text string) (string, error) {
+	return func(text string) (string, error) {
+		if text != "" {
+			options = append(options[:len(options):len(options)], slack.MsgOptionText(text, false))
+		}
+		_, _, _, err := h.client.UpdateMessage(channelID, messageTS, options...)
+		return messageTS, err
+	}
+}
+
+// OutboxStats returns the statistics of the outbound Slack message queues
+func (h *Handler) OutboxStats() OutboxStats {
+	if h.outbox == nil {
+		return OutboxStats{}
+	}
+	return h.outbox.snapshot()
+}
+
+// Shutdown stops accepting outbound messages and waits until the queued ones are sent
+func (h *Handler) Shutdown(ctx context.Context) error {
+	if h.outbox == nil {
+		return nil
+	}
+	return h.outbox.shutdown(ctx)
+}
+
 // PostToThread posts a message to a Slack thread
 func (h *Handler) PostToThread(channelID, threadTS, text string) error {
-	_, _, err := h.client.PostMessage(
+	return h.queueMessage(channelID, "", mergeNone, text, h.postMessageFunc(channelID, slack.MsgOptionTS(threadTS)))
+}
+
+// PostSessionStartMessage posts the session start message with a Stop button to a Slack thread
+func (h *Handler) PostSessionStartMessage(channelID, threadTS, text string) error {
+	return h.queueMessage(channelID, "", mergeNone, text, h.postMessageFunc(
+		channelID,
+		slack.MsgOptionTS(threadTS),
+		slack.MsgOptionBlocks(blocks.SessionStart(text, threadTS)...),
+	))
+}
+
+// PostResultMessage posts the result message of a session to a Slack thread
+// When patch is not empty, the message gets a button uploading the changes of the session as a patch.
+// When pullRequest is true, it also gets a button committing the changes and opening a pull request.
+func (h *Handler) PostResultMessage(channelID, threadTS, text, patch string, pullRequest bool) error {
+	if patch == "" && !pullRequest {
+		return h.PostToThread(channelID, threadTS, text)
+	}
+	return h.queueMessage(channelID, "", mergeNone, text, h.postMessageFunc(
+		channelID,
+		slack.MsgOptionTS(threadTS),
+		slack.MsgOptionBlocks(blocks.SessionResult(text, threadTS, patch, pullRequest)...),
+	))
+}
+
+// PostStatusMessage posts the compact mode status message of a session to a Slack thread
+// It returns the timestamp of the posted message.
+func (h *Handler) PostStatusMessage(channelID, threadTS, text string) (string, error) {
+	return h.callMessage(channelID, h.postMessageFunc(
 		channelID,
 		slack.MsgOptionText(text, false),
 		slack.MsgOptionTS(threadTS),
-	)
-	return err
+	))
+}
+
+// UpdateStatusMessage replaces the text of the compact mode status message
+// Updates queued in a burst are collapsed into the latest one.
+func (h *Handler) UpdateStatusMessage(channelID, messageTS, text string) error {
+	return h.queueMessage(channelID, "update:"+messageTS, mergeReplace, text, h.updateMessageFunc(channelID, messageTS))
 }
 
 // PostRichTextToThread posts a rich text message to a Slack thread
 func (h *Handler) PostRichTextToThread(channelID, threadTS string, elements []slack.RichTextElement) error {
-	_, _, err := h.client.PostMessage(
+	return h.queueMessage(channelID, "", mergeNone, "", h.postMessageFunc(
 		channelID,
 		slack.MsgOptionTS(threadTS),
 		slack.MsgOptionBlocks(
 			slack.NewRichTextBlock("rich_text", elements...),
 		),
-	)
-	return err
+	))
 }
 
 // PostAssistantMessage posts a message with assistant display options
 func (h *Handler) PostAssistantMessage(channelID, threadTS, text string) error {
 	options := []slack.MsgOption{
-		slack.MsgOptionText(text, false),
 		slack.MsgOptionTS(threadTS),
 	}
 
@@ -47,14 +144,13 @@ func (h *Handler) PostAssistantMessage(channelID, threadTS, text string) error {
 		options = append(options, slack.MsgOptionIconURL(h.assistantIconURL))
 	}
 
-	_, _, err := h.client.PostMessage(channelID, options...)
-	return err
+	return h.queueMessage(channelID, "", mergeNone, text, h.postMessageFunc(channelID, options...))
 }
 
 // PostToolMessage posts a message with tool-specific display options
+// A burst of messages of the same tool type in a thread is posted as a single message.
 func (h *Handler) PostToolMessage(channelID, threadTS, text, toolType string) error {
 	options := []slack.MsgOption{
-		slack.MsgOptionText(text, false),
 		slack.MsgOptionTS(threadTS),
 	}
 
@@ -65,8 +161,7 @@ func (h *Handler) PostToolMessage(channelID, threadTS, text, toolType string) er
 	// Add icon emoji
 	options = append(options, slack.MsgOptionIconEmoji(toolInfo.SlackIcon))
 
-	_, _, err := h.client.PostMessage(channelID, options...)
-	return err
+	return h.queueMessage(channelID, "tool:"+threadTS+":"+toolType, mergeAppend, text, h.postMessageFunc(channelID, options...))
 }
 
 // PostToolRichTextMessage posts a rich text message with tool-specific display options
@@ -85,13 +180,63 @@ func (h *Handler) PostToolRichTextMessage(channelID, threadTS string, elements [
 	// Add icon emoji
 	options = append(options, slack.MsgOptionIconEmoji(toolInfo.SlackIcon))
 
-	_, _, err := h.client.PostMessage(channelID, options...)
-	return err
+	return h.queueMessage(channelID, "", mergeNone, "", h.postMessageFunc(channelID, options...))
+}
+
+// AddReaction adds an emoji reaction to a message
+func (h *Handler) AddReaction(channelID, messageTS, name string) error {
+	return h.queueMessage(channelID, "", mergeNone, "", func(string) (string, error) {
+		return "", h.client.AddReaction(name, slack.NewRefToMessage(channelID, messageTS))
+	})
+}
+
+// RemoveReaction removes an emoji reaction added by the bot from a message
+func (h *Handler) RemoveReaction(channelID, messageTS, name string) error {
+	return h.queueMessage(channelID, "", mergeNone, "", func(string) (string, error) {
+		return "", h.client.RemoveReaction(name, slack.NewRefToMessage(channelID, messageTS))
+	})
+}
+
+// PostDiffSnippet uploads a diff that is too long to show inline as a snippet file in a Slack thread
+func (h *Handler) PostDiffSnippet(channelID, threadTS, title string, d diff.Result) error {
+	return h.PostSnippet(channelID, threadTS, filepath.Base(d.Path)+".diff", title, d.String(), "diff")
+}
+
+// PostSnippet uploads text as a snippet file in a Slack thread
+func (h *Handler) PostSnippet(channelID, threadTS, filename, title, content, snippetType string) error {
+	return h.queueMessage(channelID, "", mergeNone, "", func(string) (string, error) {
+		_, err := h.client.UploadFileV2(slack.UploadFileV2Parameters{
+			Channel:         channelID,
+			ThreadTimestamp: threadTS,
+			Filename:        filename,
+			Title:           title,
+			Content:         content,
+			FileSize:        len(content),
+			SnippetType:     snippetType,
+		})
+		return "", err
+	})
+}
+
+// PostApprovalNotice posts a compact approval-related line such as an automatic decision or a reminder
+func (h *Handler) PostApprovalNotice(channelID, threadTS, text string) error {
+	return h.PostToolMessage(channelID, threadTS, text, tools.MessageApprovalPrompt)
 }
 
 // PostApprovalRequest posts an approval request with buttons using markdown
-func (h *Handler) PostApprovalRequest(channelID, threadTS, message, requestID, userID string) error {
+// It returns the timestamp of the posted message.
+func (h *Handler) PostApprovalRequest(channelID, threadTS, message, requestID, userID string) (string, error) {
 	options := blocks.ApprovalRequestOptions(channelID, threadTS, message, requestID, userID)
-	_, _, err := h.client.PostMessage(channelID, options...)
-	return err
+	messageTS, err := h.callMessage(channelID, h.postMessageFunc(channelID, options...))
+	h.RefreshHome(userID)
+	return messageTS, err
+}
+
+// UpdateApprovalExpired replaces the buttons of an approval request that timed out with an expired status
+func (h *Handler) UpdateApprovalExpired(channelID, messageTS, message, userID string, timeout time.Duration) error {
+	return h.queueMessage(channelID, "", mergeNone, "", h.updateMessageFunc(
+		channelID,
+		messageTS,
+		slack.MsgOptionBlocks(blocks.ApprovalExpired(message, userID, timeout)...),
+	))
 }
@@ -0,0 +1,315 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+)
+
+// Outbox settings
+const (
+	// outboxQueueSize is the number of messages a channel queue holds before senders block
+	outboxQueueSize = 100
+	// outboxMaxRetries is how often a message is retried after a rate limit or server error
+	outboxMaxRetries = 5
+	// outboxRetryBackoff is the first wait before retrying a server error; it doubles on every retry
+	outboxRetryBackoff = time.Second
+	// outboxMaxCoalescedLength keeps merged messages well below Slack's text limit
+	outboxMaxCoalescedLength = 3000
+)
+
+// errOutboxClosed is returned for messages sent after the outbox was shut down
+var errOutboxClosed = errors.New("slack outbox is closed")
+
+// mergeMode tells how queued messages with the same key are combined
+type mergeMode int
+
+const (
+	// mergeNone sends every message on its own
+	mergeNone mergeMode = iota
+	// mergeAppend joins the texts of a burst of messages into one message
+	mergeAppend
+	// mergeReplace sends only the last of a burst of messages, such as updates of the same message
+	mergeReplace
+)
+
+// outboundMessage is a Slack API call waiting in a channel queue
+type outboundMessage struct {
+	key   string // Messages with the same key may be merged according to merge
+	merge mergeMode
+	text  string
+	send  func(text string) (string, error) // Performs the call with the (merged) text and returns the message timestamp
+
+	done       chan outboundResult // Receives the result when the sender waits for delivery, nil otherwise
+	enqueuedAt time.Time
+}
+
+// outboundResult is the outcome of a Slack API call
+type outboundResult struct {
+	messageTS string
+	err       error
+}
+
+// OutboxStats reports the state of the outbound Slack queues
+type OutboxStats struct {
+	Queued            int           `json:"queued"`              // Messages currently waiting
+	MaxQueued         int           `json:"max_queued"`          // Most messages ever waiting in one channel
+	Sent              int64         `json:"sent"`                // Slack API calls made successfully
+	Coalesced         int64         `json:"coalesced"`           // Messages merged into another message
+	Retries           int64         `json:"retries"`             // Retries after rate limits or server errors
+	RateLimited       int64         `json:"rate_limited"`        // Rate limit responses received
+	Failed            int64         `json:"failed"`              // Messages given up on
+	BackPressureWaits int64         `json:"back_pressure_waits"` // Sends that blocked on a full queue
+	MaxWait           time.Duration `json:"max_wait_ns"`         // Longest time a message waited in a queue
+}
+
+// outbox sends Slack API calls through one FIFO queue per channel
+// Calls for a channel are made one at a time, so messages keep their order inside a thread
+// even when a call is retried after a rate limit.
+type outbox struct {
+	mu     sync.Mutex
+	queues map[string]*channelQueue
+	stats  OutboxStats
+	closed bool
+	idle   *sync.Cond // Signalled when a channel queue drains
+}
+
+// channelQueue is the queue of a single channel
+type channelQueue struct {
+	messages []*outboundMessage
+	running  bool       // A worker is draining the queue
+	notFull  *sync.Cond // Signalled when messages are taken from the queue
+}
+
+// newOutbox creates an empty outbox
+func newOutbox() *outbox {
+	o := &outbox{queues: make(map[string]*channelQueue)}
+	o.idle = sync.NewCond(&o.mu)
+	return o
+}
+
+// post queues a message without waiting for delivery
+// Failures are logged once all retries are exhausted.
+func (o *outbox) post(channelID string, msg *outboundMessage) error {
+	return o.enqueue(channelID, msg)
+}
+
+// call queues a message and waits until it is delivered or given up on
+func (o *outbox) call(channelID string, msg *outboundMessage) (string, error) {
+	msg.done = make(chan outboundResult, 1)
+	if err := o.enqueue(channelID, msg); err != nil {
+		return "", err
+	}
+	result := <-msg.done
+	return result.messageTS, result.err
+}
+
+// enqueue appends a message to the channel queue, blocking while the queue is full
+func (o *outbox) enqueue(channelID string, msg *outboundMessage) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return errOutboxClosed
+	}
+
+	q, ok := o.queues[channelID]
+	if !ok {
+		q = &channelQueue{notFull: sync.NewCond(&o.mu)}
+		o.queues[channelID] = q
+	}
+
+	if len(q.messages) >= outboxQueueSize {
+		o.stats.BackPressureWaits++
+		log.Warn().Str("channel_id", channelID).Int("queued", len(q.messages)).Msg("Slack outbox queue is full, waiting")
+		for len(q.messages) >= outboxQueueSize && !o.closed {
+			q.notFull.Wait()
+		}
+		if o.closed {
+			return errOutboxClosed
+		}
+	}
+
+	msg.enqueuedAt = time.Now()
+	q.messages = append(q.messages, msg)
+	o.stats.Queued++
+	if len(q.messages) > o.stats.MaxQueued {
+		o.stats.MaxQueued = len(q.messages)
+	}
+
+	if !q.running {
+		q.running = true
+		go o.drain(channelID, q)
+	}
+	return nil
+}
+
+// drain sends the messages of a channel queue until it is empty
+func (o *outbox) drain(channelID string, q *channelQueue) {
+	for {
+		o.mu.Lock()
+		if len(q.messages) == 0 {
+			q.running = false
+			delete(o.queues, channelID)
+			o.idle.Broadcast()
+			o.mu.Unlock()
+			return
+		}
+		msg, merged := o.takeLocked(q)
+		q.notFull.Broadcast()
+		o.mu.Unlock()
+
+		messageTS, err := o.deliver(msg)
+
+		o.mu.Lock()
+		if err != nil {
+			o.stats.Failed++
+		} else {
+			o.stats.Sent++
+		}
+		o.mu.Unlock()
+
+		if err != nil && msg.done == nil {
+			log.Error().Err(err).Str("channel_id", channelID).Int("merged", len(merged)).Msg("Failed to send Slack message")
+		}
+		result := outboundResult{messageTS: messageTS, err: err}
+		for _, m := range append(merged, msg) {
+			if m.done != nil {
+				m.done <- result
+			}
+		}
+	}
+}
+
+// takeLocked removes the next message from the queue, merging the burst of messages queued behind it
+// It returns the message to send and the messages merged into it.
+func (o *outbox) takeLocked(q *channelQueue) (*outboundMessage, []*outboundMessage) {
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	o.stats.Queued--
+	o.recordWaitLocked(msg)
+
+	if msg.merge == mergeNone || msg.key == "" {
+		return msg, nil
+	}
+
+	var merged []*outboundMessage
+	text := msg.text
+	for len(q.messages) > 0 {
+		next := q.messages[0]
+		if next.key != msg.key || next.merge != msg.merge {
+			break
+		}
+		if msg.merge == mergeAppend {
+			// Waiting senders need their own result, so only fire-and-forget messages are joined
+			if next.done != nil || len(text)+1+len(next.text) > outboxMaxCoalescedLength {
+				break
+			}
+			text += "\n" + next.text
+		} else {
+			text = next.text
+		}
+		merged = append(merged, msg)
+		msg = next
+		q.messages = q.messages[1:]
+		o.stats.Queued--
+		o.stats.Coalesced++
+		o.recordWaitLocked(next)
+	}
+
+	if len(merged) > 0 {
+		msg = &outboundMessage{key: msg.key, merge: msg.merge, text: text, send: msg.send, done: msg.done}
+	}
+	return msg, merged
+}
+
+// recordWaitLocked records how long a message waited in its queue
+func (o *outbox) recordWaitLocked(msg *outboundMessage) {
+	if wait := time.Since(msg.enqueuedAt); wait > o.stats.MaxWait {
+		o.stats.MaxWait = wait
+	}
+}
+
+// deliver makes the Slack API call, retrying after rate limits and server errors
+func (o *outbox) deliver(msg *outboundMessage) (string, error) {
+	backoff := outboxRetryBackoff
+	for attempt := 0; ; attempt++ {
+		messageTS, err := msg.send(msg.text)
+		if err == nil {
+			return messageTS, nil
+		}
+
+		wait, retryable := retryDelay(err, backoff)
+		if !retryable || attempt >= outboxMaxRetries {
+			return "", err
+		}
+
+		o.mu.Lock()
+		o.stats.Retries++
+		var rateLimited *slack.RateLimitedError
+		if errors.As(err, &rateLimited) {
+			o.stats.RateLimited++
+		}
+		o.mu.Unlock()
+
+		log.Warn().Err(err).Dur("retry_after", wait).Int("attempt", attempt+1).Msg("Retrying Slack API call")
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
+
+// retryDelay tells whether a failed Slack API call can be retried and how long to wait first
+// Rate limits are retried after the Retry-After duration returned by Slack.
+func retryDelay(err error, backoff time.Duration) (time.Duration, bool) {
+	var rateLimited *slack.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		if rateLimited.RetryAfter > 0 {
+			return rateLimited.RetryAfter, true
+		}
+		return backoff, true
+	}
+
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) && retryable.Retryable() {
+		return backoff, true
+	}
+	return 0, false
+}
+
+// snapshot returns a copy of the outbox statistics
+func (o *outbox) snapshot() OutboxStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stats
+}
+
+// shutdown stops accepting messages and waits until the queued ones are sent or ctx is done
+func (o *outbox) shutdown(ctx context.Context) error {
+	o.mu.Lock()
+	o.closed = true
+	for _, q := range o.queues {
+		q.notFull.Broadcast()
+	}
+	o.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		o.mu.Lock()
+		for len(o.queues) > 0 {
+			o.idle.Wait()
+		}
+		o.mu.Unlock()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,151 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// recordingSender records the texts sent through an outbox
+type recordingSender struct {
+	mu    sync.Mutex
+	sent  []string
+	fails []error // Errors returned by the first calls
+}
+
+func (r *recordingSender) send(text string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.fails) > 0 {
+		err := r.fails[0]
+		r.fails = r.fails[1:]
+		return "", err
+	}
+	r.sent = append(r.sent, text)
+	return "ts", nil
+}
+
+func TestOutbox(t *testing.T) {
+	tests := []struct {
+		name      string
+		messages  []outboundMessage
+		fails     []error
+		want      []string
+		wantStats OutboxStats
+	}{
+		{
+			name: "keeps order",
+			messages: []outboundMessage{
+				{text: "1"},
+				{text: "2"},
+				{text: "3"},
+			},
+			want:      []string{"1", "2", "3"},
+			wantStats: OutboxStats{Sent: 3},
+		},
+		{
+			name: "appends a burst of messages with the same key",
+			messages: []outboundMessage{
+				{text: "first"},
+				{key: "tool:Read", merge: mergeAppend, text: "a"},
+				{key: "tool:Read", merge: mergeAppend, text: "b"},
+				{key: "tool:Grep", merge: mergeAppend, text: "c"},
+			},
+			want:      []string{"first", "a\nb", "c"},
+			wantStats: OutboxStats{Sent: 3, Coalesced: 1},
+		},
+		{
+			name: "keeps only the latest of a burst of updates",
+			messages: []outboundMessage{
+				{text: "first"},
+				{key: "update:1", merge: mergeReplace, text: "a"},
+				{key: "update:1", merge: mergeReplace, text: "b"},
+				{key: "update:1", merge: mergeReplace, text: "c"},
+			},
+			want:      []string{"first", "c"},
+			wantStats: OutboxStats{Sent: 2, Coalesced: 2},
+		},
+		{
+			name:      "retries after a rate limit",
+			messages:  []outboundMessage{{text: "1"}, {text: "2"}},
+			fails:     []error{&slack.RateLimitedError{RetryAfter: time.Millisecond}},
+			want:      []string{"1", "2"},
+			wantStats: OutboxStats{Sent: 2, Retries: 1, RateLimited: 1},
+		},
+		{
+			name:      "gives up on errors that are not retryable",
+			messages:  []outboundMessage{{text: "1"}, {text: "2"}},
+			fails:     []error{errors.New("channel_not_found")},
+			want:      []string{"2"},
+			wantStats: OutboxStats{Sent: 1, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOutbox()
+			sender := &recordingSender{fails: tt.fails}
+
+			// Hold the queue with a waiting message so the test messages queue up as a burst
+			release := make(chan struct{})
+			if err := o.post("C123", &outboundMessage{send: func(string) (string, error) {
+				<-release
+				return "", nil
+			}}); err != nil {
+				t.Fatalf("post() error = %v", err)
+			}
+			for i := range tt.messages {
+				msg := tt.messages[i]
+				msg.send = sender.send
+				if err := o.post("C123", &msg); err != nil {
+					t.Fatalf("post() error = %v", err)
+				}
+			}
+			close(release)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := o.shutdown(ctx); err != nil {
+				t.Fatalf("shutdown() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(sender.sent, tt.want) {
+				t.Errorf("sent = %q, want %q", sender.sent, tt.want)
+			}
+
+			stats := o.snapshot()
+			stats.Sent-- // The message holding the queue
+			stats.MaxQueued, stats.MaxWait = 0, 0
+			if stats != tt.wantStats {
+				t.Errorf("stats = %+v, want %+v", stats, tt.wantStats)
+			}
+
+			if err := o.post("C123", &outboundMessage{send: sender.send}); !errors.Is(err, errOutboxClosed) {
+				t.Errorf("post() after shutdown error = %v, want %v", err, errOutboxClosed)
+			}
+		})
+	}
+}
+
+func TestOutboxCall(t *testing.T) {
+	o := newOutbox()
+
+	messageTS, err := o.call("C123", &outboundMessage{send: func(string) (string, error) {
+		return "1234.5678", nil
+	}})
+	if err != nil || messageTS != "1234.5678" {
+		t.Errorf("call() = %q, %v, want %q, nil", messageTS, err, "1234.5678")
+	}
+
+	wantErr := errors.New("invalid_blocks")
+	if _, err := o.call("C123", &outboundMessage{send: func(string) (string, error) {
+		return "", wantErr
+	}}); !errors.Is(err, wantErr) {
+		t.Errorf("call() error = %v, want %v", err, wantErr)
+	}
+}
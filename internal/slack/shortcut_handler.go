@@ -0,0 +1,93 @@
+package slack
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/slack/blocks"
+)
+
+// startSessionShortcutID is the callback ID of the "Start Claude session from this message" message shortcut
+const startSessionShortcutID = "start_session_from_message"
+
+// sessionModalMetadata is the private metadata of the session start modals
+type sessionModalMetadata struct {
+	ChannelID    string   `json:"channel_id"`
+	ImageFileIDs []string `json:"image_file_ids,omitempty"` // Images of the message a shortcut was used on
+}
+
+// encode encodes the metadata for a modal's private metadata
+func (m sessionModalMetadata) encode() string {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return m.ChannelID
+	}
+	return string(data)
+}
+
+// parseSessionModalMetadata decodes the private metadata of a session start modal
+// Modals opened before the metadata was JSON hold the channel ID only.
+func parseSessionModalMetadata(privateMetadata string) sessionModalMetadata {
+	var metadata sessionModalMetadata
+	if err := json.Unmarshal([]byte(privateMetadata), &metadata); err != nil {
+		return sessionModalMetadata{ChannelID: privateMetadata}
+	}
+	return metadata
+}
+
+// openMessageShortcutModal opens the session start modal for the message a shortcut was used on
+// The prompt is seeded with the message text and a link to it, and its images are attached once the session starts.
+func (h *Handler) openMessageShortcutModal(payload *slack.InteractionCallback) {
+	channelID := payload.Channel.ID
+	message := payload.Message
+
+	permalink, err := h.client.GetPermalink(&slack.PermalinkParameters{
+		Channel: channelID,
+		Ts:      message.Timestamp,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("channel_id", channelID).Msg("failed to get message permalink")
+	}
+
+	metadata := sessionModalMetadata{ChannelID: channelID}
+	if h.fileUploadEnabled {
+		for _, file := range message.Files {
+			if strings.HasPrefix(file.Mimetype, "image/") {
+				metadata.ImageFileIDs = append(metadata.ImageFileIDs, file.ID)
+			}
+		}
+	}
+
+	h.openRepoModal(payload.TriggerID, metadata, blocks.SessionStartPrefill{
+		Prompt: messageShortcutPrompt(RemoveBotMentionFromText(message.Text, h.botUserID), permalink),
+		Images: len(metadata.ImageFileIDs),
+	})
+}
+
+// messageShortcutPrompt returns the prompt a session started from a message begins with
+func messageShortcutPrompt(text, permalink string) string {
+	text = strings.TrimSpace(text)
+	if permalink == "" {
+		return text
+	}
+	if text == "" {
+		return "Slack message: " + permalink
+	}
+	return text + "\n\nSlack message: " + permalink
+}
+
+// getFiles returns the Slack files with the given IDs, skipping the ones that cannot be read
+func (h *Handler) getFiles(fileIDs []string) []slack.File {
+	var files []slack.File
+	for _, id := range fileIDs {
+		file, _, _, err := h.client.GetFileInfo(id, 0, 0)
+		if err != nil {
+			log.Error().Err(err).Str("file_id", id).Msg("failed to get file info")
+			continue
+		}
+		files = append(files, *file)
+	}
+	return files
+}
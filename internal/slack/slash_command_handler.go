@@ -2,12 +2,19 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/slack-go/slack"
+	"github.com/yuya-takeyama/cc-slack/internal/config"
+	"github.com/yuya-takeyama/cc-slack/internal/messages"
+	"github.com/yuya-takeyama/cc-slack/internal/slack/blocks"
 )
 
 // HandleSlashCommand handles Slack slash commands (e.g., /cc)
@@ -41,6 +48,18 @@ func (h *Handler) HandleSlashCommand(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	text := h.handleSlashCommand(cmd)
+
+	w.WriteHeader(http.StatusOK)
+	if text != "" {
+		fmt.Fprint(w, text)
+	}
+}
+
+// handleSlashCommand dispatches a slash command regardless of the transport it arrived on.
+// It returns the text to respond with, or an empty string for an empty acknowledgement.
+// Text responses to slash commands are only shown to the user who ran the command.
+func (h *Handler) handleSlashCommand(cmd slack.SlashCommand) string {
 	// Log the command for debugging
 	log.Info().
 		Str("command", cmd.Command).
@@ -49,17 +68,300 @@ func (h *Handler) HandleSlashCommand(w http.ResponseWriter, r *http.Request) {
 		Str("channel_id", cmd.ChannelID).
 		Msg("received slash command")
 
-	// Handle /cc command
-	if cmd.Command == "/cc" {
+	if cmd.Command != h.config.Slack.SlashCommandName {
+		// Additional commands start sessions in their working directory
+		if sc, ok := h.config.FindSlashCommand(cmd.Command); ok {
+			return h.handleWorkingDirCommand(cmd, sc)
+		}
+
+		// Unknown command
+		return "Unknown command"
+	}
+
+	subcommand, args := parseSubcommand(cmd.Text)
+	switch subcommand {
+	case "":
 		// Open modal asynchronously
-		go h.openRepoModal(cmd.TriggerID, cmd.ChannelID, cmd.UserID, cmd.Text)
+		go h.openRepoModal(cmd.TriggerID, sessionModalMetadata{ChannelID: cmd.ChannelID}, blocks.SessionStartPrefill{})
+		return ""
+	case "help":
+		return slashCommandHelp(cmd.Command)
+	case "list":
+		return h.handleListSubcommand(cmd)
+	case "status":
+		return h.handleStatusSubcommand(cmd, args)
+	case "stop":
+		return h.handleStopSubcommand(cmd, args)
+	case "resume":
+		return h.handleResumeSubcommand(cmd, args)
+	default:
+		return h.handleStartSubcommand(cmd)
+	}
+}
 
-		// Return 200 immediately
-		w.WriteHeader(http.StatusOK)
-		return
+// parseSubcommand splits slash command text into a lowercased subcommand and its arguments
+func parseSubcommand(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ""
 	}
+	subcommand, args, _ := strings.Cut(text, " ")
+	return strings.ToLower(subcommand), strings.TrimSpace(args)
+}
 
-	// Unknown command
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprint(w, "Unknown command")
+// slashCommandHelp returns the usage of the slash command
+func slashCommandHelp(command string) string {
+	return strings.Join([]string{
+		"*Usage*",
+		fmt.Sprintf("`%s [prompt]` Open the session start form, with the prompt filled in", command),
+		fmt.Sprintf("`%s <directory> <prompt>` Start a session in a working directory without the form", command),
+		fmt.Sprintf("`%s list` List the active sessions in this channel", command),
+		fmt.Sprintf("`%s status [thread link]` Show the state and cost of a thread's sessions", command),
+		fmt.Sprintf("`%s stop [session ID | thread link]` Stop a session", command),
+		fmt.Sprintf("`%s resume <thread link> [prompt]` Resume the session of a thread", command),
+		fmt.Sprintf("`%s help` Show this help", command),
+		"Without a thread link or session ID, status and stop use the most recently active session in this channel.",
+	}, "\n")
+}
+
+// handleListSubcommand handles "/cc list"
+func (h *Handler) handleListSubcommand(cmd slack.SlashCommand) string {
+	sessions, err := h.sessionMgr.ListSessionsByChannel(cmd.ChannelID)
+	if err != nil {
+		return fmt.Sprintf("Failed to list sessions: %v", err)
+	}
+	if len(sessions) == 0 {
+		return "There are no active sessions in this channel."
+	}
+
+	lines := []string{fmt.Sprintf("*Active sessions in this channel (%d)*", len(sessions))}
+	for _, session := range sessions {
+		state := ThreadStateWaiting
+		if session.Running {
+			state = ThreadStateRunning
+		}
+		lines = append(lines, fmt.Sprintf("• `%s` in `%s`: %s, last active %s ago",
+			session.SessionID, session.WorkDir, state, messages.FormatDuration(time.Since(session.LastActive).Round(time.Second))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleStatusSubcommand handles "/cc status [thread link]"
+func (h *Handler) handleStatusSubcommand(cmd slack.SlashCommand, args string) string {
+	channelID, threadTS := "", ""
+	if args != "" {
+		var ok bool
+		if channelID, threadTS, ok = parseThreadLink(args); !ok {
+			return fmt.Sprintf("`%s` is not a link to a Slack thread.", args)
+		}
+		if channelID != cmd.ChannelID {
+			return "Only threads in this channel can be looked up here."
+		}
+	} else {
+		session, err := h.sessionMgr.GetLatestSessionByChannel(cmd.ChannelID)
+		if err != nil || session == nil {
+			return "There is no active session in this channel. Pass a thread link to see the status of a finished thread."
+		}
+		channelID, threadTS = session.ChannelID, session.ThreadTS
+	}
+
+	status, err := h.sessionMgr.GetThreadStatus(channelID, threadTS)
+	if err != nil {
+		return fmt.Sprintf("Failed to get thread status: %v", err)
+	}
+	return formatThreadStatus(status)
+}
+
+// formatThreadStatus formats the answer of "/cc status"
+func formatThreadStatus(status *ThreadStatus) string {
+	return fmt.Sprintf("*Thread status*\n"+
+		"Session ID: `%s`\n"+
+		"Working directory: `%s`\n"+
+		"State: %s\n"+
+		"Sessions: %d\n"+
+		"Cost so far: $%.6f USD",
+		status.SessionID, status.WorkDir, status.State, status.Sessions, status.TotalCost)
+}
+
+// handleStopSubcommand handles "/cc stop [session ID | thread link]".
+// Slash commands carry no thread context, so without an argument it targets the most recently active session in the channel.
+func (h *Handler) handleStopSubcommand(cmd slack.SlashCommand, args string) string {
+	session, notFound := h.findSession(cmd.ChannelID, args)
+	if session == nil {
+		return notFound
+	}
+	if !h.mayControlSession(cmd.UserID, session.UserID) {
+		return stopNotAllowedText(session.UserID)
+	}
+
+	if err := h.sessionMgr.StopSession(session.ChannelID, session.ThreadTS, cmd.UserID); err != nil {
+		log.Error().
+			Err(err).
+			Str("channel_id", session.ChannelID).
+			Str("thread_ts", session.ThreadTS).
+			Msg("failed to stop session")
+		return fmt.Sprintf("Failed to stop session: %v", err)
+	}
+
+	return fmt.Sprintf("Stopping session `%s`", session.SessionID)
+}
+
+// findSession finds the active session "/cc stop" targets by session ID or thread link
+// Without a target, the most recently active session in the channel is used. Like with
+// "/cc resume", only sessions in the channel the command was run in can be targeted.
+// When there is no such session, it returns the text to respond with instead.
+func (h *Handler) findSession(channelID, target string) (*Session, string) {
+	if target == "" {
+		session, err := h.sessionMgr.GetLatestSessionByChannel(channelID)
+		if err != nil || session == nil {
+			return nil, "There is no running session to stop in this channel."
+		}
+		return session, ""
+	}
+
+	if linkChannelID, threadTS, ok := parseThreadLink(target); ok {
+		if linkChannelID != channelID {
+			return nil, "Only sessions in this channel can be stopped here."
+		}
+		session, err := h.sessionMgr.GetSessionByThread(linkChannelID, threadTS)
+		if err != nil || session == nil {
+			return nil, "There is no running session in that thread."
+		}
+		return session, ""
+	}
+
+	sessions, err := h.sessionMgr.ListSessionsByChannel(channelID)
+	if err != nil {
+		return nil, fmt.Sprintf("Failed to list sessions: %v", err)
+	}
+	for _, session := range sessions {
+		if session.SessionID == target {
+			return session, ""
+		}
+	}
+	return nil, fmt.Sprintf("There is no running session `%s` in this channel.", target)
+}
+
+// handleResumeSubcommand handles "/cc resume <thread link> [prompt]"
+// Sessions are resumed in their own thread, so the link has to point to a thread in this channel.
+func (h *Handler) handleResumeSubcommand(cmd slack.SlashCommand, args string) string {
+	link, prompt, _ := strings.Cut(args, " ")
+	channelID, threadTS, ok := parseThreadLink(link)
+	if !ok {
+		return fmt.Sprintf("Usage: `%s resume <thread link> [prompt]`", cmd.Command)
+	}
+	if channelID != cmd.ChannelID {
+		return "Only threads in this channel can be resumed here."
+	}
+
+	status, err := h.sessionMgr.GetThreadStatus(channelID, threadTS)
+	if err != nil {
+		return fmt.Sprintf("Failed to resume session: %v", err)
+	}
+	if status.Active {
+		return fmt.Sprintf("Session `%s` is already running in that thread.", status.SessionID)
+	}
+
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		prompt = defaultResumePrompt
+	}
+	go h.resumeSession(channelID, threadTS, cmd.UserID, prompt)
+	return fmt.Sprintf("Resuming session `%s`", status.SessionID)
+}
+
+// defaultResumePrompt is sent when a session is resumed with "/cc resume" without a prompt
+const defaultResumePrompt = "Continue from where you left off."
+
+// resumeSession resumes the session of a thread on behalf of a user
+func (h *Handler) resumeSession(channelID, threadTS, userID, prompt string) {
+	if err := h.PostToThread(channelID, threadTS, messages.FormatSessionResumedMessage(userID, prompt)); err != nil {
+		log.Error().Err(err).Msg("failed to post session resumed message")
+	}
+
+	_, _, err := h.sessionMgr.CreateSession(context.Background(), channelID, threadTS, "", prompt, userID, "", SessionOptions{})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("channel_id", channelID).
+			Str("thread_ts", threadTS).
+			Msg("failed to resume session")
+		h.postEphemeralToThread(channelID, threadTS, userID, fmt.Sprintf("Failed to resume session: %v", err))
+	}
+}
+
+// handleStartSubcommand handles slash command text that is not a subcommand
+// "/cc <directory> <prompt>" starts a session right away. Any other text opens the session start modal
+// with the text as its prompt, and with the working directory selected when the text starts with its name.
+func (h *Handler) handleStartSubcommand(cmd slack.SlashCommand) string {
+	prefill := h.slashCommandPrefill(cmd.Text)
+	if wd, ok := h.config.FindWorkingDir(prefill.WorkDir); ok && prefill.WorkDir != "" && prefill.Prompt != "" {
+		go h.createThreadAndStartSession(cmd.ChannelID, wd.Path, prefill.Prompt, cmd.UserID, SessionOptions{}, nil)
+		return fmt.Sprintf("Starting a session in %s…", wd.Name)
+	}
+
+	go h.openRepoModal(cmd.TriggerID, sessionModalMetadata{ChannelID: cmd.ChannelID}, prefill)
+	return ""
+}
+
+// slashCommandPrefill returns the initial values of the session start modal for slash command text
+// In multi-directory mode, a first word naming a working directory selects it instead of being part of the prompt.
+func (h *Handler) slashCommandPrefill(text string) blocks.SessionStartPrefill {
+	text = strings.TrimSpace(text)
+	if h.config.IsSingleDirectoryMode() {
+		return blocks.SessionStartPrefill{Prompt: text}
+	}
+
+	name, prompt, _ := strings.Cut(text, " ")
+	if wd, ok := h.config.FindWorkingDirByName(name); ok {
+		return blocks.SessionStartPrefill{WorkDir: wd.Path, Prompt: strings.TrimSpace(prompt)}
+	}
+	return blocks.SessionStartPrefill{Prompt: text}
+}
+
+// handleWorkingDirCommand handles an additional slash command bound to a working directory
+// The text is the prompt; without one, the session start modal is opened with the working directory selected.
+func (h *Handler) handleWorkingDirCommand(cmd slack.SlashCommand, sc config.SlashCommandConfig) string {
+	wd, ok := h.config.FindWorkingDirByName(sc.WorkingDir)
+	if !ok {
+		return fmt.Sprintf("Working directory `%s` of %s is not configured.", sc.WorkingDir, cmd.Command)
+	}
+
+	prompt := strings.TrimSpace(cmd.Text)
+	if prompt == "" {
+		go h.openWorkingDirModal(cmd.TriggerID, cmd.ChannelID, wd)
+		return ""
+	}
+
+	go h.createThreadAndStartSession(cmd.ChannelID, wd.Path, prompt, cmd.UserID, SessionOptions{}, nil)
+	return fmt.Sprintf("Starting a session in %s…", wd.Name)
+}
+
+// parseThreadLink extracts the channel and thread timestamp from a Slack message link
+// Links to replies carry the thread in their thread_ts parameter; other links point to the thread's parent message,
+// whose timestamp is the path element after "p" without its decimal point.
+func parseThreadLink(link string) (string, string, bool) {
+	// Slack may escape links as <url> or <url|label>
+	link = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(link), "<"), ">")
+	link, _, _ = strings.Cut(link, "|")
+
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "archives" || parts[1] == "" {
+		return "", "", false
+	}
+	channelID := parts[1]
+
+	if threadTS := u.Query().Get("thread_ts"); threadTS != "" {
+		return channelID, threadTS, true
+	}
+
+	digits := strings.TrimPrefix(parts[2], "p")
+	if len(digits) <= 6 || digits == parts[2] || strings.Trim(digits, "0123456789") != "" {
+		return "", "", false
+	}
+	return channelID, digits[:len(digits)-6] + "." + digits[len(digits)-6:], true
 }
@@ -0,0 +1,107 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// SocketModeRunner receives events, interactions and slash commands over a
+// Socket Mode WebSocket connection and dispatches them to the same handlers
+// used by the HTTP endpoints
+type SocketModeRunner struct {
+	handler *Handler
+	client  *socketmode.Client
+}
+
+// NewSocketModeRunner creates a new Socket Mode runner for the handler.
+// Additional slack options (e.g. slack.OptionAPIURL) can be passed to point
+// the connection at a different API endpoint.
+func NewSocketModeRunner(handler *Handler, appToken string, options ...slack.Option) *SocketModeRunner {
+	options = append([]slack.Option{slack.OptionAppLevelToken(appToken)}, options...)
+	api := slack.New(handler.botToken, options...)
+
+	return &SocketModeRunner{
+		handler: handler,
+		client:  socketmode.New(api),
+	}
+}
+
+// Run connects to Slack and processes incoming requests until the context is cancelled
+func (r *SocketModeRunner) Run(ctx context.Context) error {
+	go r.processEvents(ctx)
+	return r.client.RunContext(ctx)
+}
+
+// processEvents consumes events emitted by the Socket Mode client
+func (r *SocketModeRunner) processEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt := <-r.client.Events:
+			r.handleEvent(evt)
+		}
+	}
+}
+
+// handleEvent acknowledges and dispatches a single Socket Mode event
+func (r *SocketModeRunner) handleEvent(evt socketmode.Event) {
+	switch evt.Type {
+	case socketmode.EventTypeConnecting:
+		log.Info().Msg("connecting to Slack with Socket Mode")
+	case socketmode.EventTypeConnected:
+		log.Info().Msg("connected to Slack with Socket Mode")
+	case socketmode.EventTypeConnectionError:
+		log.Error().Interface("data", evt.Data).Msg("Socket Mode connection failed, retrying")
+	case socketmode.EventTypeInvalidAuth:
+		log.Error().Msg("Socket Mode authentication failed, check slack.app_token")
+
+	case socketmode.EventTypeEventsAPI:
+		eventsAPIEvent, ok := evt.Data.(slackevents.EventsAPIEvent)
+		if !ok {
+			log.Warn().Interface("data", evt.Data).Msg("ignored unexpected Events API payload")
+			return
+		}
+		// Acknowledge first so Slack does not retry while the session starts, and handle
+		// the event in the background so that it does not hold up the requests queued behind it
+		r.client.Ack(*evt.Request)
+		go r.handler.handleEventsAPIEvent(eventsAPIEvent)
+
+	case socketmode.EventTypeInteractive:
+		callback, ok := evt.Data.(slack.InteractionCallback)
+		if !ok {
+			log.Warn().Interface("data", evt.Data).Msg("ignored unexpected interaction payload")
+			return
+		}
+		// Modal submissions respond through the acknowledgement payload, so they are handled
+		// before acknowledging. Anything else is acknowledged first and handled in the background
+		// so that a slow button click does not make the clicks queued behind it miss Slack's
+		// 3 second deadline.
+		if callback.Type != slack.InteractionTypeViewSubmission {
+			r.client.Ack(*evt.Request)
+			go r.handler.handleInteractionCallback(&callback)
+			return
+		}
+		if response := r.handler.handleInteractionCallback(&callback); response != nil {
+			r.client.Ack(*evt.Request, response)
+		} else {
+			r.client.Ack(*evt.Request)
+		}
+
+	case socketmode.EventTypeSlashCommand:
+		cmd, ok := evt.Data.(slack.SlashCommand)
+		if !ok {
+			log.Warn().Interface("data", evt.Data).Msg("ignored unexpected slash command payload")
+			return
+		}
+		if text := r.handler.handleSlashCommand(cmd); text != "" {
+			r.client.Ack(*evt.Request, map[string]interface{}{"text": text})
+		} else {
+			r.client.Ack(*evt.Request)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/slack-go/slack"
+)
+
+// fakeSocketModeServer emulates apps.connections.open and the Socket Mode WebSocket endpoint
+type fakeSocketModeServer struct {
+	server   *httptest.Server
+	requests []string
+	acks     chan map[string]interface{}
+}
+
+func newFakeSocketModeServer(t *testing.T, requests ...string) *fakeSocketModeServer {
+	t.Helper()
+
+	f := &fakeSocketModeServer{
+		requests: requests,
+		acks:     make(chan map[string]interface{}, len(requests)),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apps.connections.open", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer xapp-test" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer xapp-test")
+		}
+		wsURL := "ws" + strings.TrimPrefix(f.server.URL, "http") + "/link"
+		fmt.Fprintf(w, `{"ok":true,"url":%q}`, wsURL)
+	})
+	mux.HandleFunc("/link", func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hello","num_connections":1}`)); err != nil {
+			return
+		}
+		for _, req := range f.requests {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+				return
+			}
+		}
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var ack map[string]interface{}
+			if err := json.Unmarshal(data, &ack); err != nil {
+				t.Errorf("failed to parse ack: %v", err)
+				continue
+			}
+			f.acks <- ack
+		}
+	})
+
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeSocketModeServer) waitAck(t *testing.T) map[string]interface{} {
+	t.Helper()
+	select {
+	case ack := <-f.acks:
+		return ack
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for ack")
+		return nil
+	}
+}
+
+func TestSocketModeRunner(t *testing.T) {
+	eventRequest := `{
+		"envelope_id": "env-event",
+		"type": "events_api",
+		"payload": {
+			"type": "event_callback",
+			"event": {
+				"type": "message",
+				"channel": "C123",
+				"user": "U456",
+				"text": "<@UBOT> hello",
+				"ts": "1700000001.000200",
+				"thread_ts": "1700000000.000100"
+			}
+		}
+	}`
+	interactionRequest := `{
+		"envelope_id": "env-interaction",
+		"type": "interactive",
+		"payload": {
+			"type": "block_actions",
+			"user": {"id": "U456"},
+			"actions": [{"action_id": "unknown_action", "value": ""}]
+		}
+	}`
+	commandRequest := `{
+		"envelope_id": "env-command",
+		"type": "slash_commands",
+		"accepts_response_payload": true,
+		"payload": {
+			"command": "/unknown",
+			"is_enterprise_install": "false",
+			"user_id": "U456",
+			"channel_id": "C123"
+		}
+	}`
+	server := newFakeSocketModeServer(t, eventRequest, interactionRequest, commandRequest)
+
+	// The event blocks in SendMessage until the interaction has been acknowledged
+	sessionMgr := &MockSessionManager{
+		getSessionByThreadReturn: &Session{SessionID: "session-1", ChannelID: "C123", ThreadTS: "1700000000.000100"},
+		sendMessageBlock:         make(chan struct{}),
+	}
+	handler := &Handler{
+		config:     createTestConfig(),
+		sessionMgr: sessionMgr,
+		botToken:   "xoxb-test",
+		botUserID:  "UBOT",
+	}
+
+	runner := NewSocketModeRunner(handler, "xapp-test", slack.OptionAPIURL(server.server.URL+"/"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runner.Run(ctx)
+
+	// Events API request is acknowledged and routed to the existing session
+	ack := server.waitAck(t)
+	if ack["envelope_id"] != "env-event" {
+		t.Errorf("envelope_id = %v, want env-event", ack["envelope_id"])
+	}
+
+	// Block actions are acknowledged while the event is still being handled
+	ack = server.waitAck(t)
+	if ack["envelope_id"] != "env-interaction" {
+		t.Errorf("envelope_id = %v, want env-interaction", ack["envelope_id"])
+	}
+	close(sessionMgr.sendMessageBlock)
+
+	// Slash command request is answered through the ack payload
+	ack = server.waitAck(t)
+	if ack["envelope_id"] != "env-command" {
+		t.Errorf("envelope_id = %v, want env-command", ack["envelope_id"])
+	}
+	payload, _ := ack["payload"].(map[string]interface{})
+	if payload["text"] != "Unknown command" {
+		t.Errorf("payload text = %v, want Unknown command", payload["text"])
+	}
+
+	// The event is handled in the background after its ack
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		sessionMgr.mu.Lock()
+		n := len(sessionMgr.sendMessageCalls)
+		sessionMgr.mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sessionMgr.mu.Lock()
+	defer sessionMgr.mu.Unlock()
+	if len(sessionMgr.sendMessageCalls) != 1 {
+		t.Fatalf("SendMessage called %d times, want 1", len(sessionMgr.sendMessageCalls))
+	}
+	call := sessionMgr.sendMessageCalls[0]
+	if call.sessionID != "session-1" || call.message != "hello" {
+		t.Errorf("SendMessage(%q, %q), want (session-1, hello)", call.sessionID, call.message)
+	}
+}
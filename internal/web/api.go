@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -351,3 +352,102 @@ func GetThreadSessions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// MessageResponse represents a transcript message in the API response
+type MessageResponse struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Subtype   string `json:"subtype,omitempty"`
+	ToolName  string `json:"tool_name,omitempty"`
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SessionMessagesResponse represents the session messages API response
+type SessionMessagesResponse struct {
+	Session  SessionResponse   `json:"session"`
+	Messages []MessageResponse `json:"messages"`
+}
+
+// GetSessionMessages handles GET /api/sessions/:session_id/messages
+func GetSessionMessages(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	// Extract session_id from URL path
+	// Expected path: /api/sessions/{session_id}/messages
+	sessionID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/sessions/"), "/messages")
+
+	if sessionID == "" {
+		http.Error(w, "Session ID is required", http.StatusBadRequest)
+		return
+	}
+
+	session, err := queries.GetSession(ctx, sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Str("session_id", sessionID).Msg("Failed to get session")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	thread, err := queries.GetThreadByID(ctx, session.ThreadID)
+	if err != nil {
+		log.Error().Err(err).Int64("thread_id", session.ThreadID).Msg("Failed to get thread")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	messages, err := queries.ListMessagesBySessionID(ctx, session.ID)
+	if err != nil {
+		log.Error().Err(err).Str("session_id", sessionID).Msg("Failed to list messages")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	sessionResp := SessionResponse{
+		SessionID: session.SessionID,
+		ThreadTs:  thread.ThreadTs,
+		Status:    session.Status.String,
+		StartedAt: session.StartedAt.Time.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if session.EndedAt.Valid {
+		sessionResp.EndedAt = session.EndedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+
+	if session.InitialPrompt.Valid {
+		sessionResp.InitialPrompt = session.InitialPrompt.String
+	}
+
+	// Build response
+	response := SessionMessagesResponse{
+		Session:  sessionResp,
+		Messages: make([]MessageResponse, 0, len(messages)),
+	}
+
+	for _, message := range messages {
+		response.Messages = append(response.Messages, MessageResponse{
+			ID:        message.ID,
+			Type:      message.Type,
+			Subtype:   message.Subtype.String,
+			ToolName:  message.ToolName.String,
+			ToolUseID: message.ToolUseID.String,
+			Content:   message.Content.String,
+			IsError:   message.IsError,
+			CreatedAt: message.CreatedAt.Time.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	// Return JSON response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	ccdatabase "github.com/yuya-takeyama/cc-slack/internal/database"
+	"github.com/yuya-takeyama/cc-slack/internal/db"
+)
+
+func setupTestDatabase(t *testing.T) *db.Queries {
+	t.Helper()
+
+	conn, err := ccdatabase.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if err := ccdatabase.Migrate(conn, "../../migrations"); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	SetDatabase(conn)
+	return queries
+}
+
+func TestGetSessionMessages(t *testing.T) {
+	q := setupTestDatabase(t)
+	ctx := context.Background()
+
+	thread, err := q.CreateThread(ctx, db.CreateThreadParams{
+		ChannelID:        "C123",
+		ThreadTs:         "1700000000.000100",
+		WorkingDirectory: "/tmp/project",
+	})
+	if err != nil {
+		t.Fatalf("failed to create thread: %v", err)
+	}
+	session, err := q.CreateSessionWithInitialPrompt(ctx, db.CreateSessionWithInitialPromptParams{
+		ThreadID:      thread.ID,
+		SessionID:     "session-1",
+		InitialPrompt: sql.NullString{String: "Fix the bug", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	for _, p := range []db.CreateMessageParams{
+		{SessionID: session.ID, Type: "prompt", Content: sql.NullString{String: "Fix the bug", Valid: true}},
+		{SessionID: session.ID, Type: "tool_use", ToolName: sql.NullString{String: "Bash", Valid: true}, ToolUseID: sql.NullString{String: "toolu_1", Valid: true}},
+		{SessionID: session.ID, Type: "tool_result", ToolUseID: sql.NullString{String: "toolu_1", Valid: true}, IsError: true},
+	} {
+		if err := q.CreateMessage(ctx, p); err != nil {
+			t.Fatalf("failed to create message: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantTypes  []string
+	}{
+		{
+			name:       "existing session",
+			path:       "/api/sessions/session-1/messages",
+			wantStatus: http.StatusOK,
+			wantTypes:  []string{"prompt", "tool_use", "tool_result"},
+		},
+		{
+			name:       "unknown session",
+			path:       "/api/sessions/unknown/messages",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			GetSessionMessages(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp SessionMessagesResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Session.ThreadTs != "1700000000.000100" {
+				t.Errorf("thread_ts = %q, want 1700000000.000100", resp.Session.ThreadTs)
+			}
+			if len(resp.Messages) != len(tt.wantTypes) {
+				t.Fatalf("got %d messages, want %d", len(resp.Messages), len(tt.wantTypes))
+			}
+			for i, want := range tt.wantTypes {
+				if resp.Messages[i].Type != want {
+					t.Errorf("messages[%d].type = %q, want %q", i, resp.Messages[i].Type, want)
+				}
+			}
+			if !resp.Messages[2].IsError {
+				t.Error("expected tool_result to be marked as error")
+			}
+		})
+	}
+}
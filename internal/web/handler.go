@@ -113,5 +113,14 @@ func (h *Handler) handleAPI(w http.ResponseWriter, r *http.Request, path string)
 		return
 	}
 
+	if strings.HasPrefix(path, "/api/sessions/") && strings.HasSuffix(path, "/messages") {
+		if r.Method == http.MethodGet {
+			GetSessionMessages(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
 	http.NotFound(w, r)
 }
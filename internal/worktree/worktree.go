@@ -0,0 +1,110 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// BranchName returns the name of the branch created for a Slack thread
+func BranchName(prefix, channelID, threadTS string) string {
+	return prefix + Name(channelID, threadTS)
+}
+
+// Name returns the directory name of the worktree of a Slack thread
+func Name(channelID, threadTS string) string {
+	return channelID + "-" + strings.ReplaceAll(threadTS, ".", "-")
+}
+
+// Exists reports whether a worktree directory is present on disk
+func Exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Create adds a worktree of the repository at repoDir
+// A new branch is created from the repository's HEAD, or an existing branch is checked out
+// when the worktree is recreated for a thread whose worktree was removed.
+func Create(ctx context.Context, repoDir, path, branch string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	args := []string{"worktree", "add"}
+	if branchExists(ctx, repoDir, branch) {
+		args = append(args, path, branch)
+	} else {
+		args = append(args, "-b", branch, path)
+	}
+
+	if _, err := git(ctx, repoDir, args...); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+	return nil
+}
+
+// Remove removes a worktree and keeps its branch
+// It fails when the worktree has uncommitted changes, so no work is lost.
+func Remove(ctx context.Context, repoDir, path string) error {
+	if _, err := git(ctx, repoDir, "worktree", "remove", path); err != nil {
+		return fmt.Errorf("failed to remove worktree: %w", err)
+	}
+	return nil
+}
+
+// Commit commits all changes in a worktree, including untracked files that are not ignored
+// It reports whether a commit was made; a worktree without changes is left as is.
+func Commit(ctx context.Context, path, message string) (bool, error) {
+	status, err := git(ctx, path, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(status) == "" {
+		return false, nil
+	}
+
+	if _, err := git(ctx, path, "add", "--all"); err != nil {
+		return false, fmt.Errorf("failed to stage changes: %w", err)
+	}
+	if _, err := git(ctx, path, "commit", "--quiet", "--message", message); err != nil {
+		return false, fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return true, nil
+}
+
+// Push pushes a branch of a worktree to the remote and sets it as the branch's upstream
+func Push(ctx context.Context, path, remote, branch string) error {
+	if _, err := git(ctx, path, "push", "--set-upstream", remote, branch); err != nil {
+		return fmt.Errorf("failed to push branch: %w", err)
+	}
+	return nil
+}
+
+// RemoteURL returns the URL of a remote of the repository at dir
+func RemoteURL(ctx context.Context, dir, remote string) (string, error) {
+	out, err := git(ctx, dir, "remote", "get-url", remote)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// branchExists reports whether a local branch exists in the repository
+func branchExists(ctx context.Context, repoDir, branch string) bool {
+	_, err := git(ctx, repoDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// git runs a git command in dir and returns its output
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
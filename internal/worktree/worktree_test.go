@@ -0,0 +1,91 @@
+package worktree
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestBranchName(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		channelID string
+		threadTS  string
+		want      string
+	}{
+		{
+			name:      "default prefix",
+			prefix:    "cc-slack/",
+			channelID: "C123",
+			threadTS:  "1700000000.000100",
+			want:      "cc-slack/C123-1700000000-000100",
+		},
+		{
+			name:      "no prefix",
+			channelID: "C123",
+			threadTS:  "1700000000.000100",
+			want:      "C123-1700000000-000100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BranchName(tt.prefix, tt.channelID, tt.threadTS); got != tt.want {
+				t.Errorf("BranchName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAndRemove(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	ctx := context.Background()
+	repoDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		if _, err := git(ctx, repoDir, args...); err != nil {
+			t.Fatalf("failed to set up repository: %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "worktrees", "C123-1")
+	branch := "cc-slack/C123-1"
+
+	if err := Create(ctx, repoDir, path, branch); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !Exists(path) {
+		t.Fatal("worktree was not created")
+	}
+
+	// Uncommitted changes keep the worktree from being removed
+	if err := os.WriteFile(filepath.Join(path, "new.txt"), []byte("work"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(ctx, repoDir, path); err == nil {
+		t.Fatal("Remove() expected error for worktree with uncommitted changes")
+	}
+	if err := os.Remove(filepath.Join(path, "new.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Remove(ctx, repoDir, path); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if Exists(path) {
+		t.Fatal("worktree was not removed")
+	}
+
+	// The branch is kept, so the worktree can be recreated on it
+	if err := Create(ctx, repoDir, path, branch); err != nil {
+		t.Fatalf("Create() on existing branch error = %v", err)
+	}
+}
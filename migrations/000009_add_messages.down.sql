@@ -0,0 +1,4 @@
+-- Drop messages table
+DROP INDEX IF EXISTS idx_messages_tool_use_id;
+DROP INDEX IF EXISTS idx_messages_session_id;
+DROP TABLE IF EXISTS messages;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- Claude Code stream-json messages (session transcript)
+-- One row per message or content block received from Claude Code
+CREATE TABLE messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL, -- sessions.id (stable across temp -> real session ID updates)
+    type TEXT NOT NULL,          -- system, text, thinking, tool_use, tool_result, result
+    subtype TEXT,                -- system/result subtype, e.g. init, success, error_max_turns
+    tool_name TEXT,
+    tool_use_id TEXT,
+    content TEXT,
+    is_error BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id)
+);
+
+CREATE INDEX idx_messages_session_id ON messages(session_id, id);
+CREATE INDEX idx_messages_tool_use_id ON messages(tool_use_id);
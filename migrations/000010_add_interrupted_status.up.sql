@@ -0,0 +1,41 @@
+-- Add 'interrupted' to the sessions.status CHECK constraint
+-- SQLite doesn't support altering constraints, so we need to recreate the table
+-- messages.session_id references sessions(id), so defer FK checks until commit
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TABLE sessions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    thread_id INTEGER NOT NULL,
+    session_id TEXT NOT NULL UNIQUE,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP,
+    status TEXT CHECK(status IN ('active', 'completed', 'failed', 'timeout', 'interrupted')) DEFAULT 'active',
+    model TEXT,
+    total_cost_usd REAL,
+    input_tokens INTEGER,
+    output_tokens INTEGER,
+    duration_ms INTEGER,
+    num_turns INTEGER,
+    initial_prompt TEXT,
+    FOREIGN KEY (thread_id) REFERENCES threads(id)
+);
+
+-- Copy data to new table
+INSERT INTO sessions_new (
+    id, thread_id, session_id, started_at, ended_at, status,
+    model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt
+)
+SELECT
+    id, thread_id, session_id, started_at, ended_at, status,
+    model, total_cost_usd, input_tokens, output_tokens, duration_ms, num_turns, initial_prompt
+FROM sessions;
+
+-- Drop old table and rename new one
+DROP TABLE sessions;
+ALTER TABLE sessions_new RENAME TO sessions;
+
+-- Recreate indexes
+CREATE INDEX idx_sessions_thread_id ON sessions(thread_id);
+CREATE INDEX idx_sessions_status ON sessions(status);
+CREATE INDEX IF NOT EXISTS idx_sessions_started_at_desc ON sessions(started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sessions_thread_started_at ON sessions(thread_id, started_at DESC);
@@ -0,0 +1,4 @@
+-- Remove the worktree and archive columns from threads
+ALTER TABLE threads DROP COLUMN archived_at;
+ALTER TABLE threads DROP COLUMN worktree_branch;
+ALTER TABLE threads DROP COLUMN worktree_path;
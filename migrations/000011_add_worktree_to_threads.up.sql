@@ -0,0 +1,4 @@
+-- Add the git worktree a thread's sessions run in and the time the thread was archived
+ALTER TABLE threads ADD COLUMN worktree_path TEXT;
+ALTER TABLE threads ADD COLUMN worktree_branch TEXT;
+ALTER TABLE threads ADD COLUMN archived_at TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Remove the pull request column from threads
+ALTER TABLE threads DROP COLUMN pull_request_url;
@@ -0,0 +1,2 @@
+-- Add the pull request opened from a thread
+ALTER TABLE threads ADD COLUMN pull_request_url TEXT;
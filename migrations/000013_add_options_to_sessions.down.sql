@@ -0,0 +1,3 @@
+-- Remove the session option columns
+ALTER TABLE sessions DROP COLUMN extended_thinking;
+ALTER TABLE sessions DROP COLUMN permission_mode;
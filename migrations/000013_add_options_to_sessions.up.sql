@@ -0,0 +1,3 @@
+-- Add the options a session was started with
+ALTER TABLE sessions ADD COLUMN permission_mode TEXT;
+ALTER TABLE sessions ADD COLUMN extended_thinking BOOLEAN;
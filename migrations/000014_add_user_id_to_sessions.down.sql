@@ -0,0 +1,3 @@
+-- Remove the user column
+DROP INDEX IF EXISTS idx_sessions_user_ended_at;
+ALTER TABLE sessions DROP COLUMN user_id;
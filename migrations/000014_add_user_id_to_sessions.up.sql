@@ -0,0 +1,5 @@
+-- Add the Slack user who started a session
+ALTER TABLE sessions ADD COLUMN user_id TEXT;
+
+-- Recent sessions of a user (user_id filter + ORDER BY ended_at DESC)
+CREATE INDEX IF NOT EXISTS idx_sessions_user_ended_at ON sessions(user_id, ended_at DESC);
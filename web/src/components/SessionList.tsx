@@ -10,7 +10,7 @@ import {
 interface Session {
   session_id: string;
   thread_ts: string;
-  status: "active" | "completed" | "failed" | "unknown";
+  status: "active" | "completed" | "interrupted" | "failed" | "unknown";
   started_at: string;
   ended_at?: string;
   initial_prompt?: string;
@@ -125,7 +125,13 @@ function SessionList() {
               <div className="flex justify-between items-start">
                 <div className="flex-1">
                   <p className="text-sm font-medium text-gray-900">
-                    ID: {truncateSessionId(session.session_id)}
+                    ID:{" "}
+                    <Link
+                      to={`/sessions/${session.session_id}/messages`}
+                      className="text-blue-600 hover:text-blue-800 hover:underline"
+                    >
+                      {truncateSessionId(session.session_id)}
+                    </Link>
                   </p>
                   <p className="text-sm text-gray-500">
                     Thread:{" "}
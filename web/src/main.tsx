@@ -3,6 +3,7 @@ import ReactDOM from "react-dom/client";
 import { createBrowserRouter, RouterProvider } from "react-router-dom";
 import App from "./App";
 import ManagerPage from "./pages/ManagerPage";
+import SessionMessagesPage from "./pages/SessionMessagesPage";
 import SessionsPage from "./pages/SessionsPage";
 import ThreadSessionsPage from "./pages/ThreadSessionsPage";
 import ThreadsPage from "./pages/ThreadsPage";
@@ -21,6 +22,10 @@ const router = createBrowserRouter([
         path: "sessions",
         element: <SessionsPage />,
       },
+      {
+        path: "sessions/:sessionId/messages",
+        element: <SessionMessagesPage />,
+      },
       {
         path: "threads/:threadId/sessions",
         element: <ThreadSessionsPage />,
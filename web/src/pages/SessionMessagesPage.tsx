@@ -0,0 +1,228 @@
+import { useEffect, useState } from "react";
+import { Link, useParams } from "react-router-dom";
+import { formatDateTime } from "../utils/dateFormatter";
+import {
+  getSessionStatusColor,
+  truncateSessionId,
+} from "../utils/sessionUtils";
+
+interface Session {
+  session_id: string;
+  thread_ts: string;
+  status: "active" | "completed" | "interrupted" | "failed" | "unknown";
+  started_at: string;
+  ended_at?: string;
+  initial_prompt?: string;
+}
+
+interface Message {
+  id: number;
+  type:
+    | "prompt"
+    | "system"
+    | "text"
+    | "thinking"
+    | "tool_use"
+    | "tool_result"
+    | "approval"
+    | "result";
+  subtype?: string;
+  tool_name?: string;
+  tool_use_id?: string;
+  content?: string;
+  is_error: boolean;
+  created_at: string;
+}
+
+interface SessionMessagesResponse {
+  session: Session;
+  messages: Message[];
+}
+
+// Pretty-print JSON content such as tool inputs, falling back to raw text
+const formatContent = (content?: string): string => {
+  if (!content) {
+    return "";
+  }
+  try {
+    return JSON.stringify(JSON.parse(content), null, 2);
+  } catch {
+    return content;
+  }
+};
+
+const getMessageLabel = (
+  message: Message,
+  toolNames: Record<string, string>,
+): string => {
+  switch (message.type) {
+    case "prompt":
+      return "User";
+    case "system":
+      return `System (${message.subtype || "unknown"})`;
+    case "text":
+      return "Assistant";
+    case "thinking":
+      return "Thinking";
+    case "tool_use":
+      return `Tool: ${message.tool_name}`;
+    case "tool_result": {
+      const toolName = message.tool_use_id
+        ? toolNames[message.tool_use_id]
+        : undefined;
+      return `Result${toolName ? `: ${toolName}` : ""}${message.is_error ? " (error)" : ""}`;
+    }
+    case "approval":
+      return `Approval: ${message.tool_name} (${message.subtype})`;
+    case "result":
+      return `Session ${message.subtype || "result"}`;
+    default:
+      return message.type;
+  }
+};
+
+const getMessageColor = (message: Message): string => {
+  if (message.is_error) {
+    return "border-red-300 bg-red-50";
+  }
+  switch (message.type) {
+    case "prompt":
+      return "border-blue-300 bg-blue-50";
+    case "thinking":
+      return "border-gray-200 bg-gray-50 italic";
+    case "tool_use":
+    case "tool_result":
+      return "border-yellow-200 bg-yellow-50";
+    case "approval":
+      return "border-green-300 bg-green-50";
+    default:
+      return "border-gray-200 bg-white";
+  }
+};
+
+function SessionMessagesPage() {
+  const { sessionId } = useParams<{ sessionId: string }>();
+  const [session, setSession] = useState<Session | null>(null);
+  const [messages, setMessages] = useState<Message[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  const fetchSessionMessages = async () => {
+    try {
+      setLoading(true);
+      const response = await fetch(`/api/sessions/${sessionId}/messages`);
+      if (!response.ok) {
+        throw new Error("Failed to fetch session messages");
+      }
+      const data: SessionMessagesResponse = await response.json();
+      setSession(data.session);
+      setMessages(data.messages);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "An error occurred");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  // biome-ignore lint/correctness/useExhaustiveDependencies: fetchSessionMessages is not memoized
+  useEffect(() => {
+    fetchSessionMessages();
+  }, [sessionId]);
+
+  if (loading) {
+    return <div className="text-center py-8">Loading...</div>;
+  }
+
+  if (error) {
+    return <div className="text-center py-8 text-red-600">Error: {error}</div>;
+  }
+
+  // Map tool_use_id to tool name so results can show which tool produced them
+  const toolNames: Record<string, string> = {};
+  for (const message of messages) {
+    if (message.type === "tool_use" && message.tool_use_id) {
+      toolNames[message.tool_use_id] = message.tool_name || "";
+    }
+  }
+
+  return (
+    <div className="space-y-6">
+      <div className="flex items-center justify-between">
+        <h2 className="text-2xl font-semibold text-gray-800">Transcript</h2>
+        {session && (
+          <Link
+            to={`/threads/${session.thread_ts}/sessions`}
+            className="text-blue-600 hover:text-blue-800 text-sm"
+          >
+            ← Back to Thread Sessions
+          </Link>
+        )}
+      </div>
+
+      {session && (
+        <div className="bg-white rounded-lg shadow p-4 mb-6">
+          <div className="grid grid-cols-3 gap-4">
+            <div>
+              <p className="text-sm text-gray-600">Session</p>
+              <p className="font-medium" title={session.session_id}>
+                {truncateSessionId(session.session_id)}
+              </p>
+            </div>
+            <div>
+              <p className="text-sm text-gray-600">Status</p>
+              <span
+                className={`inline-flex px-2 text-xs leading-5 font-semibold rounded-full ${getSessionStatusColor(
+                  session.status,
+                  "table",
+                )}`}
+              >
+                {session.status}
+              </span>
+            </div>
+            <div>
+              <p className="text-sm text-gray-600">Started At</p>
+              <p className="font-medium">
+                {formatDateTime(session.started_at, {
+                  format: "medium",
+                  relative: true,
+                })}
+              </p>
+            </div>
+          </div>
+        </div>
+      )}
+
+      <div className="space-y-3">
+        {messages.map((message) => (
+          <div
+            key={message.id}
+            className={`border rounded-lg p-4 ${getMessageColor(message)}`}
+          >
+            <div className="flex justify-between items-center mb-2">
+              <p className="text-sm font-semibold text-gray-700">
+                {getMessageLabel(message, toolNames)}
+              </p>
+              <p className="text-xs text-gray-400">
+                {formatDateTime(message.created_at, { format: "medium" })}
+              </p>
+            </div>
+            {message.content && (
+              <pre className="text-sm text-gray-800 whitespace-pre-wrap break-words max-h-96 overflow-y-auto">
+                {message.type === "tool_use" || message.type === "system"
+                  ? formatContent(message.content)
+                  : message.content}
+              </pre>
+            )}
+          </div>
+        ))}
+        {messages.length === 0 && (
+          <div className="text-center py-8 text-gray-500">
+            No messages recorded for this session.
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
+
+export default SessionMessagesPage;
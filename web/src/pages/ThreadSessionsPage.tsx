@@ -17,7 +17,7 @@ interface Thread {
 
 interface Session {
   session_id: string;
-  status: "active" | "completed" | "failed" | "unknown";
+  status: "active" | "completed" | "interrupted" | "failed" | "unknown";
   started_at: string;
   ended_at?: string;
   initial_prompt?: string;
@@ -132,7 +132,12 @@ function ThreadSessionsPage() {
               {sessions.map((session) => (
                 <tr key={session.session_id} className="hover:bg-gray-50">
                   <td className="px-6 py-4 whitespace-nowrap text-sm font-medium text-gray-900">
-                    {truncateSessionId(session.session_id)}
+                    <Link
+                      to={`/sessions/${session.session_id}/messages`}
+                      className="text-blue-600 hover:text-blue-800 hover:underline"
+                    >
+                      {truncateSessionId(session.session_id)}
+                    </Link>
                   </td>
                   <td className="px-6 py-4 text-sm text-gray-500">
                     {session.initial_prompt ? (
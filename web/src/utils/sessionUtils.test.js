@@ -20,6 +20,12 @@ describe("sessionUtils", () => {
         );
       });
 
+      it("should return yellow colors for interrupted status", () => {
+        expect(getSessionStatusColor("interrupted")).toBe(
+          "text-yellow-600 bg-yellow-100",
+        );
+      });
+
       it("should return red colors for failed status", () => {
         expect(getSessionStatusColor("failed")).toBe("text-red-600 bg-red-100");
       });
@@ -50,6 +56,12 @@ describe("sessionUtils", () => {
         );
       });
 
+      it("should return yellow colors for interrupted status", () => {
+        expect(getSessionStatusColor("interrupted", "table")).toBe(
+          "bg-yellow-100 text-yellow-800",
+        );
+      });
+
       it("should return red colors for failed status", () => {
         expect(getSessionStatusColor("failed", "table")).toBe(
           "bg-red-100 text-red-800",
@@ -1,4 +1,4 @@
-type SessionStatus = "active" | "completed" | "failed" | "unknown";
+type SessionStatus = "active" | "completed" | "interrupted" | "failed" | "unknown";
 type FormatType = "card" | "table";
 
 interface Session {
@@ -23,6 +23,8 @@ export const getSessionStatusColor = (
         return "text-green-600 bg-green-100";
       case "completed":
         return "text-blue-600 bg-blue-100";
+      case "interrupted":
+        return "text-yellow-600 bg-yellow-100";
       case "failed":
         return "text-red-600 bg-red-100";
       default:
@@ -36,6 +38,8 @@ export const getSessionStatusColor = (
         return "bg-green-100 text-green-800";
       case "completed":
         return "bg-blue-100 text-blue-800";
+      case "interrupted":
+        return "bg-yellow-100 text-yellow-800";
       case "failed":
         return "bg-red-100 text-red-800";
       default: